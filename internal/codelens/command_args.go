@@ -0,0 +1,23 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package codelens
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CommandArg is an argument of one of the language server's
+// own commands, which expect arguments in the "key=value" format
+type CommandArg string
+
+func NewCommandArg(key, value string) CommandArg {
+	return CommandArg(fmt.Sprintf("%s=%s", key, value))
+}
+
+func (a CommandArg) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(a))
+}
@@ -48,6 +48,8 @@ var (
 	ctxExperimentalFeatures = &contextKey{"experimental features"}
 	ctxDocumentContext      = &contextKey{"rpc context"}
 	ctxValidationOptions    = &contextKey{"validation options"}
+	ctxFormattingOptions    = &contextKey{"formatting options"}
+	ctxInlayHintsOptions    = &contextKey{"inlay hints options"}
 )
 
 func missingContextErr(ctxKey *contextKey) *MissingContextErr {
@@ -217,3 +219,47 @@ func ValidationOptions(ctx context.Context) (settings.ValidationOptions, error)
 	}
 	return *validationOptions, nil
 }
+
+func WithFormattingOptions(ctx context.Context, formattingOptions *settings.Formatting) context.Context {
+	return context.WithValue(ctx, ctxFormattingOptions, formattingOptions)
+}
+
+func SetFormattingOptions(ctx context.Context, formattingOptions settings.Formatting) error {
+	e, ok := ctx.Value(ctxFormattingOptions).(*settings.Formatting)
+	if !ok {
+		return missingContextErr(ctxFormattingOptions)
+	}
+
+	*e = formattingOptions
+	return nil
+}
+
+func FormattingOptions(ctx context.Context) (settings.Formatting, error) {
+	formattingOptions, ok := ctx.Value(ctxFormattingOptions).(*settings.Formatting)
+	if !ok {
+		return settings.Formatting{}, missingContextErr(ctxFormattingOptions)
+	}
+	return *formattingOptions, nil
+}
+
+func WithInlayHintsOptions(ctx context.Context, inlayHintsOptions *settings.InlayHints) context.Context {
+	return context.WithValue(ctx, ctxInlayHintsOptions, inlayHintsOptions)
+}
+
+func SetInlayHintsOptions(ctx context.Context, inlayHintsOptions settings.InlayHints) error {
+	e, ok := ctx.Value(ctxInlayHintsOptions).(*settings.InlayHints)
+	if !ok {
+		return missingContextErr(ctxInlayHintsOptions)
+	}
+
+	*e = inlayHintsOptions
+	return nil
+}
+
+func InlayHintsOptions(ctx context.Context) (settings.InlayHints, error) {
+	inlayHintsOptions, ok := ctx.Value(ctxInlayHintsOptions).(*settings.InlayHints)
+	if !ok {
+		return settings.InlayHints{}, missingContextErr(ctxInlayHintsOptions)
+	}
+	return *inlayHintsOptions, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+type BackendFilename string
+
+// NewBackendFilename returns the filename of a backend configuration file
+func NewBackendFilename(name string) (BackendFilename, bool) {
+	if IsBackendFilename(name) {
+		return BackendFilename(name), true
+	}
+	return "", false
+}
+
+// IsBackendFilename returns true for partial backend configuration
+// files, as passed to `tofu init` via the -backend-config flag
+func IsBackendFilename(name string) bool {
+	return strings.HasSuffix(name, ".tfbackend")
+}
+
+func (bf BackendFilename) String() string {
+	return string(bf)
+}
+
+func (bf BackendFilename) IsJSON() bool {
+	// Backend configuration files are always in the native syntax
+	return false
+}
+
+func (bf BackendFilename) IsIgnored() bool {
+	return globalAst.IsIgnoredFile(string(bf))
+}
+
+type BackendFiles = filestore.Files[BackendFilename, hcl.File]
+
+type BackendDiags = filestore.Diags[BackendFilename]
+
+func BackendDiagsFromMap(m map[string]hcl.Diagnostics) BackendDiags {
+	return filestore.DiagsFromMap[BackendFilename](m)
+}
+
+type SourceBackendDiags = filestore.SourceDiags[BackendFilename]
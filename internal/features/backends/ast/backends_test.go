@@ -0,0 +1,24 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import "testing"
+
+func TestIsBackendFilename(t *testing.T) {
+	testCases := map[string]bool{
+		"prod.tfbackend":      true,
+		"config.s3.tfbackend": true,
+		"main.tf":             false,
+		"prod.tfvars":         false,
+		"prod.tfbackend.bak":  false,
+	}
+
+	for name, expected := range testCases {
+		if given := IsBackendFilename(name); given != expected {
+			t.Errorf("%s: expected IsBackendFilename to be %t, given %t", name, expected, given)
+		}
+	}
+}
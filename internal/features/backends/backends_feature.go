@@ -0,0 +1,133 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/features/backends/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/backends/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/backends/jobs"
+	"github.com/opentofu/tofu-ls/internal/features/backends/parser"
+	"github.com/opentofu/tofu-ls/internal/features/backends/state"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+// ModuleFeature provides access to the root module declaring the backend,
+// which is indexed as soon as any of its backend files is opened
+type ModuleFeature interface {
+	fdecoder.ModuleReader
+	IndexModule(ctx context.Context, modPath string) (job.IDs, error)
+}
+
+// BackendsFeature groups everything related to backend configuration
+// files. Its internal state keeps track of all such files in the workspace.
+type BackendsFeature struct {
+	store    *state.BackendStore
+	eventbus *eventbus.EventBus
+	stopFunc context.CancelFunc
+	logger   *log.Logger
+	events   *filestore.Events[ast.BackendFilename, hcl.File]
+
+	moduleFeature ModuleFeature
+	stateStore    *globalState.StateStore
+	fs            jobs.ReadOnlyFS
+}
+
+func NewBackendsFeature(eventbus *eventbus.EventBus, stateStore *globalState.StateStore, fs jobs.ReadOnlyFS, moduleFeature ModuleFeature) (*BackendsFeature, error) {
+	store, err := state.NewBackendStore(stateStore.ChangeStore)
+	if err != nil {
+		return nil, err
+	}
+	discardLogger := log.New(io.Discard, "", 0)
+
+	f := &BackendsFeature{
+		store:         store,
+		eventbus:      eventbus,
+		stopFunc:      func() {},
+		logger:        discardLogger,
+		moduleFeature: moduleFeature,
+		stateStore:    stateStore,
+		fs:            fs,
+	}
+	f.events = filestore.NewEvents("backend files", store, stateStore, parser.Backends, f.eventHooks())
+
+	return f, nil
+}
+
+func (f *BackendsFeature) SetLogger(logger *log.Logger) {
+	f.logger = logger
+	f.store.SetLogger(logger)
+	f.events.SetLogger(logger)
+}
+
+// Start starts the features separate goroutine.
+// It listens to various events from the EventBus and performs corresponding actions.
+func (f *BackendsFeature) Start(ctx context.Context) {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	f.stopFunc = cancelFunc
+
+	f.events.Start(ctx, f.eventbus, "feature.backends")
+}
+
+func (f *BackendsFeature) Stop() {
+	f.stopFunc()
+	f.logger.Print("stopped backends feature")
+}
+
+func (f *BackendsFeature) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	pathReader := &fdecoder.PathReader{
+		StateReader:  f.store,
+		ModuleReader: f.moduleFeature,
+	}
+
+	return pathReader.PathContext(path)
+}
+
+func (f *BackendsFeature) Paths(ctx context.Context) []lang.Path {
+	pathReader := &fdecoder.PathReader{
+		StateReader:  f.store,
+		ModuleReader: f.moduleFeature,
+	}
+
+	return pathReader.Paths(ctx)
+}
+
+// ParsedFile returns the parsed backend file, regardless
+// of whether it could be decoded against the schema
+func (f *BackendsFeature) ParsedFile(backendPath string, filename string) (*hcl.File, bool) {
+	record, err := f.store.RecordByPath(backendPath)
+	if err != nil {
+		return nil, false
+	}
+
+	file, ok := record.ParsedFiles[ast.BackendFilename(filename)]
+	return file, ok && file != nil
+}
+
+func (f *BackendsFeature) Diagnostics(path string) diagnostics.Diagnostics {
+	diags := diagnostics.NewDiagnostics()
+
+	record, err := f.store.RecordByPath(path)
+	if err != nil {
+		return diags
+	}
+
+	for source, bd := range record.Diagnostics {
+		diags.Append(source, bd.AsMap())
+	}
+
+	return diags
+}
@@ -0,0 +1,47 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/backends/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+)
+
+func backendPathContext(record *state.BackendRecord, moduleReader ModuleReader) (*decoder.PathContext, error) {
+	pathCtx := &decoder.PathContext{
+		Schema:           unknownBackendSchema,
+		ReferenceOrigins: make(reference.Origins, 0),
+		ReferenceTargets: make(reference.Targets, 0),
+		Files:            make(map[string]*hcl.File),
+		Validators:       backendValidators,
+	}
+
+	// The schema depends on the backend type declared
+	// in the terraform block of the root module
+	modPath, ok := RootModulePath(record.Path(), moduleReader)
+	if ok {
+		backend, err := moduleReader.ModuleBackend(modPath)
+		modCtx, ctxErr := moduleReader.PathContext(lang.Path{
+			Path:       modPath,
+			LanguageID: ilsp.OpenTofu.String(),
+		})
+		if err == nil && backend != nil && ctxErr == nil {
+			if bodySchema, ok := backendSchema(backend.Type, modCtx.Schema); ok {
+				pathCtx.Schema = bodySchema
+			}
+		}
+	}
+
+	for name, f := range record.ParsedFiles {
+		pathCtx.Files[name.String()] = f
+	}
+
+	return pathCtx, nil
+}
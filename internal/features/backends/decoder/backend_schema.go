@@ -0,0 +1,69 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// unknownBackendSchema is used when the backend type
+// of the root module is not known, accepting any attribute
+var unknownBackendSchema = &schema.BodySchema{
+	AnyAttribute: &schema.AttributeSchema{
+		Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+		IsOptional: true,
+	},
+}
+
+// backendSchema returns the schema of a backend configuration file for
+// the given backend type, based on the backend block of the module schema
+func backendSchema(backendType string, modSchema *schema.BodySchema) (*schema.BodySchema, bool) {
+	if modSchema == nil {
+		return nil, false
+	}
+	tfBlock, ok := modSchema.Blocks["terraform"]
+	if !ok || tfBlock.Body == nil {
+		return nil, false
+	}
+	backendBlock, ok := tfBlock.Body.Blocks["backend"]
+	if !ok {
+		return nil, false
+	}
+
+	key := schema.NewSchemaKey(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: backendType},
+		},
+	})
+	depBody, ok := backendBlock.DependentBody[key]
+	if !ok || depBody == nil {
+		return nil, false
+	}
+
+	bodySchema := depBody.Copy()
+	// Backend configuration files are merged with the body of the
+	// backend block during init, so nothing is required in them
+	relaxRequired(bodySchema)
+
+	return bodySchema, true
+}
+
+// relaxRequired makes all attributes and nested blocks
+// of the body optional, including those of nested blocks
+func relaxRequired(bodySchema *schema.BodySchema) {
+	if bodySchema == nil {
+		return
+	}
+	for _, attr := range bodySchema.Attributes {
+		attr.IsRequired = false
+		attr.IsOptional = true
+	}
+	for _, block := range bodySchema.Blocks {
+		block.MinItems = 0
+		relaxRequired(block.Body)
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	tfschema "github.com/opentofu/opentofu-schema/schema"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/backends/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/backends/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/backends/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+type ModuleReaderMock struct {
+	modPath string
+	backend *tfmod.Backend
+}
+
+func (m ModuleReaderMock) ModuleBackend(modPath string) (*tfmod.Backend, error) {
+	if modPath != m.modPath {
+		return nil, &globalState.RecordNotFoundError{Source: modPath}
+	}
+	return m.backend, nil
+}
+
+func (m ModuleReaderMock) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	s, err := tfschema.CoreModuleSchemaForVersion(tfschema.LatestAvailableVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &decoder.PathContext{
+		Schema: s,
+	}, nil
+}
+
+func (m ModuleReaderMock) MetadataReady(dir document.DirHandle) (<-chan struct{}, bool, error) {
+	return nil, true, nil
+}
+
+// backendPathDecoder returns a decoder of a backend configuration file
+// with the given source, placed in a subdirectory of a root module
+// which declares the given backend
+func backendPathDecoder(t *testing.T, backend *tfmod.Backend, src string) *decoder.PathDecoder {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, err := state.NewBackendStore(globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modPath := t.TempDir()
+	backendPath := filepath.Join(modPath, "env")
+	err = bs.Add(backendPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, diags := hclsyntax.ParseConfig([]byte(src), "dev.tfbackend", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	err = bs.UpdateParsedFiles(backendPath, ast.BackendFiles{"dev.tfbackend": f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader: bs,
+		ModuleReader: ModuleReaderMock{
+			modPath: modPath,
+			backend: backend,
+		},
+	})
+	pd, err := d.Path(lang.Path{
+		Path:       backendPath,
+		LanguageID: ilsp.OpenTofuBackend.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pd
+}
+
+func TestDecoder_backendValidation(t *testing.T) {
+	src := `path    = "terraform.tfstate"
+unknown = "foo"
+`
+	pd := backendPathDecoder(t, &tfmod.Backend{Type: "local"}, src)
+
+	diagsMap, err := pd.Validate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDiags := lang.DiagnosticsMap{
+		"dev.tfbackend": hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Unexpected attribute",
+				Detail:   `An attribute named "unknown" is not expected here`,
+				Subject: &hcl.Range{
+					Filename: "dev.tfbackend",
+					Start:    hcl.Pos{Line: 2, Column: 1, Byte: 30},
+					End:      hcl.Pos{Line: 2, Column: 16, Byte: 45},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diagsMap); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
+
+func TestDecoder_unknownBackendValidation(t *testing.T) {
+	src := `anything = "goes"
+`
+	pd := backendPathDecoder(t, nil, src)
+
+	diagsMap, err := pd.Validate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDiags := lang.DiagnosticsMap{
+		"dev.tfbackend": nil,
+	}
+	if diff := cmp.Diff(expectedDiags, diagsMap); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
+
+func TestDecoder_backendCompletion(t *testing.T) {
+	src := `
+`
+	pd := backendPathDecoder(t, &tfmod.Backend{Type: "local"}, src)
+
+	candidates, err := pd.CompletionAtPos(context.Background(), "dev.tfbackend", hcl.InitialPos)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := make([]string, 0, len(candidates.List))
+	for _, candidate := range candidates.List {
+		labels = append(labels, candidate.Label)
+	}
+	expectedLabels := []string{"path", "workspace_dir"}
+	if diff := cmp.Diff(expectedLabels, labels); diff != "" {
+		t.Fatalf("unexpected candidates: %s", diff)
+	}
+}
+
+func TestDecoder_remoteBackendValidation(t *testing.T) {
+	src := `organization = "example"
+
+workspaces {
+  name = "x"
+}
+
+unknown {
+}
+`
+	pd := backendPathDecoder(t, &tfmod.Backend{Type: "remote"}, src)
+
+	diagsMap, err := pd.Validate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDiags := lang.DiagnosticsMap{
+		"dev.tfbackend": hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Unexpected block",
+				Detail:   `Blocks of type "unknown" are not expected here`,
+				Subject: &hcl.Range{
+					Filename: "dev.tfbackend",
+					Start:    hcl.Pos{Line: 7, Column: 1, Byte: 55},
+					End:      hcl.Pos{Line: 7, Column: 8, Byte: 62},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diagsMap); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
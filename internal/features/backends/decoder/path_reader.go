@@ -0,0 +1,93 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/backends/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+type StateReader interface {
+	List() ([]*state.BackendRecord, error)
+	RecordByPath(path string) (*state.BackendRecord, error)
+}
+
+type ModuleReader interface {
+	ModuleBackend(modPath string) (*tfmod.Backend, error)
+	PathContext(path lang.Path) (*decoder.PathContext, error)
+	MetadataReady(dir document.DirHandle) (<-chan struct{}, bool, error)
+}
+
+type PathReader struct {
+	StateReader  StateReader
+	ModuleReader ModuleReader
+}
+
+var _ decoder.PathReader = &PathReader{}
+
+func (pr *PathReader) Paths(ctx context.Context) []lang.Path {
+	paths := make([]lang.Path, 0)
+
+	backendRecords, err := pr.StateReader.List()
+	if err != nil {
+		return paths
+	}
+
+	for _, record := range backendRecords {
+		paths = append(paths, lang.Path{
+			Path:       record.Path(),
+			LanguageID: ilsp.OpenTofuBackend.String(),
+		})
+	}
+
+	return paths
+}
+
+// PathContext returns a PathContext for the given path based on the language ID.
+func (pr *PathReader) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	record, err := pr.StateReader.RecordByPath(path.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch path.LanguageID {
+	case ilsp.OpenTofuBackend.String():
+		return backendPathContext(record, pr.ModuleReader)
+	}
+
+	return nil, fmt.Errorf("unknown language ID: %q", path.LanguageID)
+}
+
+// RootModulePath returns the path of the root module which the backend
+// configuration files in the given directory are most likely meant for,
+// i.e. the nearest module in the directory itself or any of its parents.
+func RootModulePath(backendPath string, moduleReader ModuleReader) (string, bool) {
+	dir := backendPath
+	for {
+		_, err := moduleReader.ModuleBackend(dir)
+		if err == nil {
+			return dir, true
+		}
+		if !globalState.IsRecordNotFound(err) {
+			return "", false
+		}
+
+		parentDir := filepath.Dir(dir)
+		if parentDir == dir {
+			return "", false
+		}
+		dir = parentDir
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl-lang/validator"
+)
+
+// backendValidators do not check for missing required attributes,
+// as the configuration is only partial and the rest may be
+// provided by the backend block or other files
+var backendValidators = []validator.Validator{
+	validator.DeprecatedAttribute{},
+	validator.UnexpectedAttribute{},
+	validator.UnexpectedBlock{},
+}
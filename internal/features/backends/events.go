@@ -0,0 +1,45 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"context"
+
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/backends/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/backends/jobs"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	"github.com/opentofu/tofu-ls/internal/job"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+func (f *BackendsFeature) eventHooks() filestore.Hooks {
+	return filestore.Hooks{
+		Parse: func(ctx context.Context, path string) error {
+			return jobs.ParseBackends(ctx, f.fs, f.store, path)
+		},
+		ParseType: op.OpTypeParseBackends,
+		Validate: func(ctx context.Context, path string) error {
+			return jobs.SchemaBackendValidation(ctx, f.store, f.moduleFeature, path)
+		},
+		ValidateType: op.OpTypeSchemaBackendValidation,
+		Index:        f.indexRootModule,
+	}
+}
+
+// indexRootModule makes sure the root module, which may not have
+// any open files, gets indexed before the backend files are decoded
+func (f *BackendsFeature) indexRootModule(ctx context.Context, path string) job.IDs {
+	modPath, ok := fdecoder.RootModulePath(path, f.moduleFeature)
+	if !ok {
+		return job.IDs{}
+	}
+
+	ids, err := f.moduleFeature.IndexModule(ctx, modPath)
+	if err != nil {
+		f.logger.Printf("failed to index root module for %q: %s", path, err)
+	}
+	return ids
+}
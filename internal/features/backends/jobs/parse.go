@@ -0,0 +1,20 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+
+	"github.com/opentofu/tofu-ls/internal/features/backends/parser"
+	"github.com/opentofu/tofu-ls/internal/features/backends/state"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+)
+
+// ParseBackends parses the backend configuration,
+// i.e. turns bytes of `*.tfbackend` files into AST ([*hcl.File]).
+func ParseBackends(ctx context.Context, fs ReadOnlyFS, backendStore *state.BackendStore, backendPath string) error {
+	return filestore.ParseFiles(ctx, fs, backendStore, parser.Backends, backendPath)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/backends/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/backends/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/backends/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// SchemaBackendValidation does schema-based validation
+// of backend configuration files (*.tfbackend) and produces
+// diagnostics associated with any "invalid" parts of code.
+//
+// It relies on previously parsed AST (via [ParseBackends])
+// and the backend type declared in the root module.
+func SchemaBackendValidation(ctx context.Context, backendStore *state.BackendStore, moduleFeature fdecoder.ModuleReader, backendPath string) error {
+	record, err := backendStore.RecordByPath(backendPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid validation if it is already in progress or already finished
+	if record.DiagnosticsState[globalAst.SchemaValidationSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(backendPath)}
+	}
+
+	err = backendStore.SetDiagnosticsState(backendPath, globalAst.SchemaValidationSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	// We only wait a short period for the root module to become ready
+	// If we have to cancel the validation, we will just run it after the next change.
+	// Files of an unknown backend accept any attribute.
+	if modPath, ok := fdecoder.RootModulePath(backendPath, moduleFeature); ok {
+		timer := time.NewTimer(2 * time.Second)
+		defer timer.Stop()
+		wCh, moduleReady, err := moduleFeature.MetadataReady(document.DirHandleFromPath(modPath))
+		if err == nil && !moduleReady {
+			select {
+			// Wait for module to be ready
+			case <-wCh:
+			// or for the remaining time to pass
+			case <-timer.C:
+			// or context cancellation
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:  backendStore,
+		ModuleReader: moduleFeature,
+	})
+	d.SetContext(idecoder.DecoderContext(ctx))
+
+	backendDecoder, err := d.Path(lang.Path{
+		Path:       backendPath,
+		LanguageID: ilsp.OpenTofuBackend.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var rErr error
+	rpcContext := lsctx.DocumentContext(ctx)
+	if rpcContext.Method == "textDocument/didChange" && ilsp.IsValidBackendLanguage(rpcContext.LanguageID) {
+		filename := path.Base(rpcContext.URI)
+		// We only revalidate a single file that changed
+		var fileDiags hcl.Diagnostics
+		fileDiags, rErr = backendDecoder.ValidateFile(ctx, filename)
+
+		backendDiags, ok := record.Diagnostics[globalAst.SchemaValidationSource]
+		if !ok {
+			backendDiags = make(ast.BackendDiags)
+		} else {
+			backendDiags = backendDiags.Copy()
+		}
+		backendDiags[ast.BackendFilename(filename)] = fileDiags
+
+		sErr := backendStore.UpdateDiagnostics(backendPath, globalAst.SchemaValidationSource, backendDiags)
+		if sErr != nil {
+			return sErr
+		}
+	} else {
+		// We validate the whole directory, e.g. on open
+		var diags lang.DiagnosticsMap
+		diags, rErr = backendDecoder.Validate(ctx)
+
+		sErr := backendStore.UpdateDiagnostics(backendPath, globalAst.SchemaValidationSource, ast.BackendDiagsFromMap(diags))
+		if sErr != nil {
+			return sErr
+		}
+	}
+
+	return rErr
+}
@@ -0,0 +1,23 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/backends/ast"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/tofu/parser"
+)
+
+// Backends recognizes and parses partial backend configuration files
+var Backends = filestore.Parser[ast.BackendFilename, hcl.File]{
+	NewFilename: ast.NewBackendFilename,
+	ParseFile: func(src []byte, filename ast.BackendFilename) (*hcl.File, hcl.Diagnostics) {
+		return parser.ParseFile(src, filename)
+	},
+	IsValidLanguage: ilsp.IsValidBackendLanguage,
+}
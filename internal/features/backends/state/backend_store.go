@@ -0,0 +1,35 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/backends/ast"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+const (
+	backendTableName = "backend"
+)
+
+// BackendStore keeps the backend configuration files of each directory
+type BackendStore = filestore.Store[ast.BackendFilename, hcl.File]
+
+// BackendRecord contains all information about backend
+// configuration files we have for a certain path
+type BackendRecord = filestore.Record[ast.BackendFilename, hcl.File]
+
+// diagnosticSources are the sources of diagnostics tracked per record
+var diagnosticSources = []globalAst.DiagnosticSource{
+	globalAst.HCLParsingSource,
+	globalAst.SchemaValidationSource,
+}
+
+func NewBackendStore(changeStore *globalState.ChangeStore) (*BackendStore, error) {
+	return filestore.NewStore[ast.BackendFilename, hcl.File](backendTableName, diagnosticSources, changeStore)
+}
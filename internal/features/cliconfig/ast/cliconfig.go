@@ -0,0 +1,69 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+type CLIConfigFilename string
+
+// NewCLIConfigFilename returns the filename of a CLI configuration file
+func NewCLIConfigFilename(name string) (CLIConfigFilename, bool) {
+	if IsCLIConfigFilename(name) {
+		return CLIConfigFilename(name), true
+	}
+	return "", false
+}
+
+// cliConfigFilenames are the names of the CLI configuration file
+// in the home directory or the APPDATA directory on Windows
+var cliConfigFilenames = []string{
+	".tofurc",
+	"tofu.rc",
+	".terraformrc",
+	"terraform.rc",
+}
+
+// IsCLIConfigFilename returns true for the CLI configuration file
+// and for *.tfrc files, which are loaded from the CLI configuration
+// directory (e.g. ~/.terraform.d) in addition to it
+func IsCLIConfigFilename(name string) bool {
+	return slices.Contains(cliConfigFilenames, name) ||
+		strings.HasSuffix(name, ".tfrc")
+}
+
+func (bf CLIConfigFilename) String() string {
+	return string(bf)
+}
+
+func (bf CLIConfigFilename) IsJSON() bool {
+	// We only support CLI configuration files in the native syntax
+	return false
+}
+
+func (bf CLIConfigFilename) IsIgnored() bool {
+	// The CLI configuration file is commonly a hidden file
+	if slices.Contains(cliConfigFilenames, string(bf)) {
+		return false
+	}
+	return globalAst.IsIgnoredFile(string(bf))
+}
+
+type CLIConfigFiles = filestore.Files[CLIConfigFilename, hcl.File]
+
+type CLIConfigDiags = filestore.Diags[CLIConfigFilename]
+
+func CLIConfigDiagsFromMap(m map[string]hcl.Diagnostics) CLIConfigDiags {
+	return filestore.DiagsFromMap[CLIConfigFilename](m)
+}
+
+type SourceCLIConfigDiags = filestore.SourceDiags[CLIConfigFilename]
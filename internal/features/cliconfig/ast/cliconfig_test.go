@@ -0,0 +1,43 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import "testing"
+
+func TestIsCLIConfigFilename(t *testing.T) {
+	testCases := map[string]bool{
+		".tofurc":          true,
+		"tofu.rc":          true,
+		".terraformrc":     true,
+		"terraform.rc":     true,
+		"credentials.tfrc": true,
+		"main.tf":          false,
+		".tofurc.bak":      false,
+	}
+
+	for name, expected := range testCases {
+		if given := IsCLIConfigFilename(name); given != expected {
+			t.Errorf("%s: expected IsCLIConfigFilename to be %t, given %t", name, expected, given)
+		}
+	}
+}
+
+func TestCLIConfigFilename_IsIgnored(t *testing.T) {
+	testCases := map[string]bool{
+		".tofurc":        false,
+		".terraformrc":   false,
+		"mirrors.tfrc":   false,
+		".mirrors.tfrc":  true,
+		"mirrors.tfrc~":  true,
+		"#mirrors.tfrc#": true,
+	}
+
+	for name, expected := range testCases {
+		if given := CLIConfigFilename(name).IsIgnored(); given != expected {
+			t.Errorf("%s: expected IsIgnored to be %t, given %t", name, expected, given)
+		}
+	}
+}
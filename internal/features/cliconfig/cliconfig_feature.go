@@ -0,0 +1,122 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/cliconfig/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/jobs"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/parser"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/state"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+// CLIConfigFeature groups everything related to CLI configuration files.
+// Its internal state keeps track of all such files which were opened or
+// discovered in the workspace.
+type CLIConfigFeature struct {
+	store    *state.CLIConfigStore
+	eventbus *eventbus.EventBus
+	stopFunc context.CancelFunc
+	logger   *log.Logger
+	events   *filestore.Events[ast.CLIConfigFilename, hcl.File]
+
+	stateStore *globalState.StateStore
+	fs         jobs.ReadOnlyFS
+}
+
+func NewCLIConfigFeature(eventbus *eventbus.EventBus, stateStore *globalState.StateStore, fs jobs.ReadOnlyFS) (*CLIConfigFeature, error) {
+	store, err := state.NewCLIConfigStore(stateStore.ChangeStore)
+	if err != nil {
+		return nil, err
+	}
+	discardLogger := log.New(io.Discard, "", 0)
+
+	f := &CLIConfigFeature{
+		store:      store,
+		eventbus:   eventbus,
+		stopFunc:   func() {},
+		logger:     discardLogger,
+		stateStore: stateStore,
+		fs:         fs,
+	}
+	f.events = filestore.NewEvents("CLI configuration files", store, stateStore, parser.CLIConfig, f.eventHooks())
+
+	return f, nil
+}
+
+func (f *CLIConfigFeature) SetLogger(logger *log.Logger) {
+	f.logger = logger
+	f.store.SetLogger(logger)
+	f.events.SetLogger(logger)
+}
+
+// Start starts the features separate goroutine.
+// It listens to various events from the EventBus and performs corresponding actions.
+func (f *CLIConfigFeature) Start(ctx context.Context) {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	f.stopFunc = cancelFunc
+
+	f.events.Start(ctx, f.eventbus, "feature.cliconfig")
+}
+
+func (f *CLIConfigFeature) Stop() {
+	f.stopFunc()
+	f.logger.Print("stopped CLI configuration feature")
+}
+
+func (f *CLIConfigFeature) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	pathReader := &fdecoder.PathReader{
+		StateReader: f.store,
+	}
+
+	return pathReader.PathContext(path)
+}
+
+func (f *CLIConfigFeature) Paths(ctx context.Context) []lang.Path {
+	pathReader := &fdecoder.PathReader{
+		StateReader: f.store,
+	}
+
+	return pathReader.Paths(ctx)
+}
+
+// ParsedFile returns the parsed CLI configuration file, regardless
+// of whether it could be decoded against the schema
+func (f *CLIConfigFeature) ParsedFile(cliConfigPath string, filename string) (*hcl.File, bool) {
+	record, err := f.store.RecordByPath(cliConfigPath)
+	if err != nil {
+		return nil, false
+	}
+
+	file, ok := record.ParsedFiles[ast.CLIConfigFilename(filename)]
+	return file, ok && file != nil
+}
+
+func (f *CLIConfigFeature) Diagnostics(path string) diagnostics.Diagnostics {
+	diags := diagnostics.NewDiagnostics()
+
+	record, err := f.store.RecordByPath(path)
+	if err != nil {
+		return diags
+	}
+
+	for source, bd := range record.Diagnostics {
+		diags.Append(source, bd.AsMap())
+	}
+
+	return diags
+}
@@ -0,0 +1,29 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/state"
+)
+
+func cliConfigPathContext(record *state.CLIConfigRecord) (*decoder.PathContext, error) {
+	pathCtx := &decoder.PathContext{
+		Schema:           cliConfigSchema,
+		ReferenceOrigins: make(reference.Origins, 0),
+		ReferenceTargets: make(reference.Targets, 0),
+		Files:            make(map[string]*hcl.File),
+		Validators:       cliConfigValidators,
+	}
+
+	for name, f := range record.ParsedFiles {
+		pathCtx.Files[name.String()] = f
+	}
+
+	return pathCtx, nil
+}
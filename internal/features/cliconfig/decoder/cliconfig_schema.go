@@ -0,0 +1,183 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const cliConfigDocsURL = "https://opentofu.org/docs/cli/config/config-file/"
+
+// cliConfigSchema is the schema of the CLI configuration file
+// (.tofurc or tofu.rc) and of *.tfrc files
+var cliConfigSchema = &schema.BodySchema{
+	Attributes: map[string]*schema.AttributeSchema{
+		"plugin_cache_dir": {
+			Constraint:  schema.LiteralType{Type: cty.String},
+			IsOptional:  true,
+			Description: lang.Markdown("Directory to cache provider plugins in, which must already exist"),
+		},
+		"plugin_cache_may_break_dependency_lock_file": {
+			Constraint: schema.LiteralType{Type: cty.Bool},
+			IsOptional: true,
+			Description: lang.Markdown("Whether to use cached providers even if their checksums " +
+				"are not recorded in the dependency lock file yet"),
+		},
+		"disable_checkpoint": {
+			Constraint:  schema.LiteralType{Type: cty.Bool},
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to disable upgrade and security bulletin checks"),
+		},
+		"disable_checkpoint_signature": {
+			Constraint:  schema.LiteralType{Type: cty.Bool},
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to disable the anonymous ID sent with upgrade and security bulletin checks"),
+		},
+	},
+	Blocks: map[string]*schema.BlockSchema{
+		"credentials": {
+			Labels: []*schema.LabelSchema{
+				{
+					Name:        "hostname",
+					Description: lang.PlainText("Hostname of the registry or remote backend"),
+				},
+			},
+			Description: lang.Markdown("Credentials for a registry or remote backend host"),
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"token": {
+						Constraint:  schema.LiteralType{Type: cty.String},
+						IsRequired:  true,
+						IsSensitive: true,
+						Description: lang.Markdown("API token for the host"),
+					},
+				},
+				HoverURL: cliConfigDocsURL,
+			},
+		},
+		"credentials_helper": {
+			Labels: []*schema.LabelSchema{
+				{
+					Name:        "name",
+					Description: lang.PlainText("Name of the credentials helper program"),
+				},
+			},
+			Description: lang.Markdown("External program providing credentials for hosts " +
+				"without a `credentials` block"),
+			MaxItems: 1,
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"args": {
+						Constraint:  schema.List{Elem: schema.LiteralType{Type: cty.String}},
+						IsOptional:  true,
+						Description: lang.Markdown("Arguments to pass to the credentials helper"),
+					},
+				},
+				HoverURL: cliConfigDocsURL,
+			},
+		},
+		"host": {
+			Labels: []*schema.LabelSchema{
+				{
+					Name:        "hostname",
+					Description: lang.PlainText("Hostname to override service discovery for"),
+				},
+			},
+			Description: lang.Markdown("Static service discovery result for a host, " +
+				"instead of requesting `/.well-known/terraform.json`"),
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"services": {
+						Constraint:  schema.Map{Elem: schema.AnyExpression{OfType: cty.DynamicPseudoType}},
+						IsRequired:  true,
+						Description: lang.Markdown("Map of service IDs (e.g. `modules.v1`) to their URLs"),
+					},
+				},
+				HoverURL: cliConfigDocsURL,
+			},
+		},
+		"provider_installation": providerInstallationBlockSchema(),
+	},
+	HoverURL: cliConfigDocsURL,
+}
+
+func providerInstallationBlockSchema() *schema.BlockSchema {
+	return &schema.BlockSchema{
+		Description: lang.Markdown("Installation methods for providers, " +
+			"overriding the default of installing them from their origin registry"),
+		MaxItems: 1,
+		Body: &schema.BodySchema{
+			Blocks: map[string]*schema.BlockSchema{
+				"filesystem_mirror": {
+					Description: lang.Markdown("Install providers from a directory on the local filesystem"),
+					Body: &schema.BodySchema{
+						Attributes: installationMethodAttributes(map[string]*schema.AttributeSchema{
+							"path": {
+								Constraint:  schema.LiteralType{Type: cty.String},
+								IsRequired:  true,
+								Description: lang.Markdown("Directory with providers in the packed or unpacked layout"),
+							},
+						}),
+						HoverURL: cliConfigDocsURL,
+					},
+				},
+				"network_mirror": {
+					Description: lang.Markdown("Install providers from a network mirror over HTTPS"),
+					Body: &schema.BodySchema{
+						Attributes: installationMethodAttributes(map[string]*schema.AttributeSchema{
+							"url": {
+								Constraint:  schema.LiteralType{Type: cty.String},
+								IsRequired:  true,
+								Description: lang.Markdown("Base URL of the mirror, which must use `https:`"),
+							},
+						}),
+						HoverURL: cliConfigDocsURL,
+					},
+				},
+				"direct": {
+					Description: lang.Markdown("Install providers directly from their origin registry"),
+					MaxItems:    1,
+					Body: &schema.BodySchema{
+						Attributes: installationMethodAttributes(map[string]*schema.AttributeSchema{}),
+						HoverURL:   cliConfigDocsURL,
+					},
+				},
+				"dev_overrides": {
+					Description: lang.Markdown("Local directories to use for providers under development, " +
+						"bypassing version and checksum verification"),
+					MaxItems: 1,
+					Body: &schema.BodySchema{
+						AnyAttribute: &schema.AttributeSchema{
+							Constraint:  schema.LiteralType{Type: cty.String},
+							IsOptional:  true,
+							Description: lang.Markdown("Directory with the provider plugin"),
+						},
+						HoverURL: cliConfigDocsURL,
+					},
+				},
+			},
+			HoverURL: cliConfigDocsURL,
+		},
+	}
+}
+
+// installationMethodAttributes returns the given attributes
+// along with those common to all provider installation methods
+func installationMethodAttributes(attrs map[string]*schema.AttributeSchema) map[string]*schema.AttributeSchema {
+	for _, name := range []string{"include", "exclude"} {
+		attrs[name] = &schema.AttributeSchema{
+			Constraint: schema.List{Elem: schema.LiteralType{Type: cty.String}},
+			IsOptional: true,
+			Description: lang.Markdown(fmt.Sprintf("Provider address patterns (e.g. `example.com/*/*`) "+
+				"to %s for this installation method", name)),
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,165 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/cliconfig/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+// cliConfigPathDecoder returns a decoder of a CLI configuration file
+// with the given source
+func cliConfigPathDecoder(t *testing.T, src string) *decoder.PathDecoder {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err := state.NewCLIConfigStore(globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := t.TempDir()
+	err = cs.Add(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, diags := hclsyntax.ParseConfig([]byte(src), ".tofurc", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	err = cs.UpdateParsedFiles(configPath, ast.CLIConfigFiles{".tofurc": f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader: cs,
+	})
+	pd, err := d.Path(lang.Path{
+		Path:       configPath,
+		LanguageID: ilsp.OpenTofuCLIConfig.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pd
+}
+
+func TestDecoder_cliConfigValidation(t *testing.T) {
+	mirrorDir := t.TempDir()
+	missingDir := filepath.Join(mirrorDir, "missing")
+
+	src := fmt.Sprintf(`plugin_cache_dir = %q
+
+provider_installation {
+  filesystem_mirror {
+    path    = %q
+    include = ["example.com/*/*"]
+  }
+  network_mirror {
+    url = "http://mirror.example.com/"
+  }
+  direct {
+    exclude = ["example.com/*/*"]
+  }
+}
+
+credentials "app.example.com" {
+  token = "xxxxxx.atlasv1.zzzzzzzzzzzzz"
+  type  = "bearer"
+}
+`, missingDir, mirrorDir)
+	pd := cliConfigPathDecoder(t, src)
+
+	diagsMap, err := pd.Validate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pathLen := len(fmt.Sprintf("%q", missingDir))
+	urlStart := strings.Index(src, `"http://`)
+	typeStart := strings.Index(src, `type`)
+	expectedDiags := lang.DiagnosticsMap{
+		".tofurc": hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Directory not found",
+				Detail:   fmt.Sprintf("The directory %q does not exist", missingDir),
+				Subject: &hcl.Range{
+					Filename: ".tofurc",
+					Start:    hcl.Pos{Line: 1, Column: 20, Byte: 19},
+					End:      hcl.Pos{Line: 1, Column: 20 + pathLen, Byte: 19 + pathLen},
+				},
+			},
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid network mirror URL",
+				Detail:   `The URL "http://mirror.example.com/" must be an absolute https: URL`,
+				Subject: &hcl.Range{
+					Filename: ".tofurc",
+					Start:    hcl.Pos{Line: 9, Column: 11, Byte: urlStart},
+					End:      hcl.Pos{Line: 9, Column: 39, Byte: urlStart + 28},
+				},
+			},
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Unexpected attribute",
+				Detail:   `An attribute named "type" is not expected here`,
+				Subject: &hcl.Range{
+					Filename: ".tofurc",
+					Start:    hcl.Pos{Line: 18, Column: 3, Byte: typeStart},
+					End:      hcl.Pos{Line: 18, Column: 19, Byte: typeStart + 16},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diagsMap); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
+
+func TestDecoder_cliConfigCompletion(t *testing.T) {
+	src := `provider_installation {
+
+}
+`
+	pd := cliConfigPathDecoder(t, src)
+
+	candidates, err := pd.CompletionAtPos(context.Background(), ".tofurc", hcl.Pos{
+		Line:   2,
+		Column: 1,
+		Byte:   24,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := make([]string, 0, len(candidates.List))
+	for _, candidate := range candidates.List {
+		labels = append(labels, candidate.Label)
+	}
+	expectedLabels := []string{"dev_overrides", "direct", "filesystem_mirror", "network_mirror"}
+	if diff := cmp.Diff(expectedLabels, labels); diff != "" {
+		t.Fatalf("unexpected candidates: %s", diff)
+	}
+}
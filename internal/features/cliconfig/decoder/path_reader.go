@@ -0,0 +1,60 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+)
+
+type StateReader interface {
+	List() ([]*state.CLIConfigRecord, error)
+	RecordByPath(path string) (*state.CLIConfigRecord, error)
+}
+
+type PathReader struct {
+	StateReader StateReader
+}
+
+var _ decoder.PathReader = &PathReader{}
+
+func (pr *PathReader) Paths(ctx context.Context) []lang.Path {
+	paths := make([]lang.Path, 0)
+
+	cliConfigRecords, err := pr.StateReader.List()
+	if err != nil {
+		return paths
+	}
+
+	for _, record := range cliConfigRecords {
+		paths = append(paths, lang.Path{
+			Path:       record.Path(),
+			LanguageID: ilsp.OpenTofuCLIConfig.String(),
+		})
+	}
+
+	return paths
+}
+
+// PathContext returns a PathContext for the given path based on the language ID.
+func (pr *PathReader) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	record, err := pr.StateReader.RecordByPath(path.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch path.LanguageID {
+	case ilsp.OpenTofuCLIConfig.String():
+		return cliConfigPathContext(record)
+	}
+
+	return nil, fmt.Errorf("unknown language ID: %q", path.LanguageID)
+}
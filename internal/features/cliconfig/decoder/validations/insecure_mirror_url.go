@@ -0,0 +1,65 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/schemacontext"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// InsecureMirrorURL reports network mirror URLs which OpenTofu
+// rejects, as the mirror protocol is only supported over HTTPS
+type InsecureMirrorURL struct{}
+
+func (v InsecureMirrorURL) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	block, ok := node.(*hclsyntax.Block)
+	if !ok || block.Type != "network_mirror" {
+		return ctx, diags
+	}
+	nestingLvl, ok := schemacontext.BlockNestingLevel(ctx)
+	if !ok || nestingLvl != 1 {
+		return ctx, diags
+	}
+
+	attr, ok := block.Body.Attributes["url"]
+	if !ok {
+		return ctx, diags
+	}
+	val, vDiags := attr.Expr.Value(nil)
+	if vDiags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || !val.Type().Equals(cty.String) {
+		return ctx, diags
+	}
+
+	u, err := url.Parse(val.AsString())
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid network mirror URL",
+			Detail:   fmt.Sprintf("The URL %q cannot be parsed: %s", val.AsString(), err),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+		return ctx, diags
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid network mirror URL",
+			Detail:   fmt.Sprintf("The URL %q must be an absolute https: URL", val.AsString()),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+
+	return ctx, diags
+}
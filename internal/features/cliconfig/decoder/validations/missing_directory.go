@@ -0,0 +1,105 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/schemacontext"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// MissingDirectory reports directories which do not exist on disk,
+// such as the path of a filesystem mirror or the plugin cache directory
+type MissingDirectory struct{}
+
+func (v MissingDirectory) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	nestingLvl, ok := schemacontext.BlockNestingLevel(ctx)
+	if !ok {
+		return ctx, diags
+	}
+
+	switch nodeType := node.(type) {
+	case *hclsyntax.Attribute:
+		if nodeType.Name == "plugin_cache_dir" && nestingLvl == 0 {
+			diags = append(diags, missingDirectoryDiags(nodeType)...)
+		}
+	case *hclsyntax.Block:
+		if nodeType.Type == "filesystem_mirror" && nestingLvl == 1 {
+			if attr, ok := nodeType.Body.Attributes["path"]; ok {
+				diags = append(diags, missingDirectoryDiags(attr)...)
+			}
+		}
+	}
+
+	return ctx, diags
+}
+
+func missingDirectoryDiags(attr *hclsyntax.Attribute) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	dir, ok := directoryPath(attr.Expr)
+	if !ok {
+		return diags
+	}
+
+	fi, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Directory not found",
+				Detail:   fmt.Sprintf("The directory %q does not exist", dir),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+		return diags
+	}
+	if !fi.IsDir() {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Not a directory",
+			Detail:   fmt.Sprintf("The path %q is not a directory", dir),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+
+	return diags
+}
+
+// directoryPath returns the absolute path the given expression
+// evaluates to, with environment variables expanded as OpenTofu does.
+//
+// Relative paths are resolved against the working directory of
+// OpenTofu, which we do not know, so they are not returned.
+func directoryPath(expr hclsyntax.Expression) (string, bool) {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || !val.Type().Equals(cty.String) {
+		return "", false
+	}
+
+	unsetVar := false
+	dir := os.Expand(val.AsString(), func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			unsetVar = true
+		}
+		return value
+	})
+	if unsetVar || !filepath.IsAbs(dir) {
+		return "", false
+	}
+
+	return dir, true
+}
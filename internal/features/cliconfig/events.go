@@ -0,0 +1,27 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"context"
+
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/jobs"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+func (f *CLIConfigFeature) eventHooks() filestore.Hooks {
+	return filestore.Hooks{
+		Parse: func(ctx context.Context, path string) error {
+			return jobs.ParseCLIConfig(ctx, f.fs, f.store, path)
+		},
+		ParseType: op.OpTypeParseCLIConfig,
+		Validate: func(ctx context.Context, path string) error {
+			return jobs.SchemaCLIConfigValidation(ctx, f.store, path)
+		},
+		ValidateType: op.OpTypeSchemaCLIConfigValidation,
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/parser"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/state"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+)
+
+// ParseCLIConfig parses the CLI configuration,
+// i.e. turns bytes of `.tofurc` or `*.tfrc` files into AST ([*hcl.File]).
+func ParseCLIConfig(ctx context.Context, fs ReadOnlyFS, cliConfigStore *state.CLIConfigStore, cliConfigPath string) error {
+	return filestore.ParseFiles(ctx, fs, cliConfigStore, parser.CLIConfig, cliConfigPath)
+}
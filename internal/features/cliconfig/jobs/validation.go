@@ -0,0 +1,93 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"path"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/cliconfig/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// SchemaCLIConfigValidation does schema-based validation
+// of CLI configuration files (.tofurc, *.tfrc) and produces
+// diagnostics associated with any "invalid" parts of code.
+//
+// It relies on previously parsed AST (via [ParseCLIConfig]).
+func SchemaCLIConfigValidation(ctx context.Context, cliConfigStore *state.CLIConfigStore, cliConfigPath string) error {
+	record, err := cliConfigStore.RecordByPath(cliConfigPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid validation if it is already in progress or already finished
+	if record.DiagnosticsState[globalAst.SchemaValidationSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(cliConfigPath)}
+	}
+
+	err = cliConfigStore.SetDiagnosticsState(cliConfigPath, globalAst.SchemaValidationSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader: cliConfigStore,
+	})
+	d.SetContext(idecoder.DecoderContext(ctx))
+
+	cliConfigDecoder, err := d.Path(lang.Path{
+		Path:       cliConfigPath,
+		LanguageID: ilsp.OpenTofuCLIConfig.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var rErr error
+	rpcContext := lsctx.DocumentContext(ctx)
+	if rpcContext.Method == "textDocument/didChange" && ilsp.IsValidCLIConfigLanguage(rpcContext.LanguageID) {
+		filename := path.Base(rpcContext.URI)
+		// We only revalidate a single file that changed
+		var fileDiags hcl.Diagnostics
+		fileDiags, rErr = cliConfigDecoder.ValidateFile(ctx, filename)
+
+		cliConfigDiags, ok := record.Diagnostics[globalAst.SchemaValidationSource]
+		if !ok {
+			cliConfigDiags = make(ast.CLIConfigDiags)
+		} else {
+			cliConfigDiags = cliConfigDiags.Copy()
+		}
+		cliConfigDiags[ast.CLIConfigFilename(filename)] = fileDiags
+
+		sErr := cliConfigStore.UpdateDiagnostics(cliConfigPath, globalAst.SchemaValidationSource, cliConfigDiags)
+		if sErr != nil {
+			return sErr
+		}
+	} else {
+		// We validate the whole directory, e.g. on open
+		var diags lang.DiagnosticsMap
+		diags, rErr = cliConfigDecoder.Validate(ctx)
+
+		sErr := cliConfigStore.UpdateDiagnostics(cliConfigPath, globalAst.SchemaValidationSource, ast.CLIConfigDiagsFromMap(diags))
+		if sErr != nil {
+			return sErr
+		}
+	}
+
+	return rErr
+}
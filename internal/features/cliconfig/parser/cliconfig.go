@@ -0,0 +1,23 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/ast"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/tofu/parser"
+)
+
+// CLIConfig recognizes and parses CLI configuration files
+var CLIConfig = filestore.Parser[ast.CLIConfigFilename, hcl.File]{
+	NewFilename: ast.NewCLIConfigFilename,
+	ParseFile: func(src []byte, filename ast.CLIConfigFilename) (*hcl.File, hcl.Diagnostics) {
+		return parser.ParseFile(src, filename)
+	},
+	IsValidLanguage: ilsp.IsValidCLIConfigLanguage,
+}
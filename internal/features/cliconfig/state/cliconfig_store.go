@@ -0,0 +1,35 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/cliconfig/ast"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+const (
+	cLIConfigTableName = "cliconfig"
+)
+
+// CLIConfigStore keeps the CLI configuration files of each directory
+type CLIConfigStore = filestore.Store[ast.CLIConfigFilename, hcl.File]
+
+// CLIConfigRecord contains all information about CLI
+// configuration files we have for a certain path
+type CLIConfigRecord = filestore.Record[ast.CLIConfigFilename, hcl.File]
+
+// diagnosticSources are the sources of diagnostics tracked per record
+var diagnosticSources = []globalAst.DiagnosticSource{
+	globalAst.HCLParsingSource,
+	globalAst.SchemaValidationSource,
+}
+
+func NewCLIConfigStore(changeStore *globalState.ChangeStore) (*CLIConfigStore, error) {
+	return filestore.NewStore[ast.CLIConfigFilename, hcl.File](cLIConfigTableName, diagnosticSources, changeStore)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+)
+
+type LockFilename string
+
+// NewLockFilename returns the filename of a dependency lock file
+func NewLockFilename(name string) (LockFilename, bool) {
+	if IsLockFilename(name) {
+		return LockFilename(name), true
+	}
+	return "", false
+}
+
+// lockFilename is the name of the dependency lock file,
+// which is maintained by `tofu init` in the root module
+const lockFilename = ".terraform.lock.hcl"
+
+// IsLockFilename returns true for the dependency lock file
+func IsLockFilename(name string) bool {
+	return name == lockFilename
+}
+
+func (bf LockFilename) String() string {
+	return string(bf)
+}
+
+func (bf LockFilename) IsJSON() bool {
+	// Dependency lock files are always in the native syntax
+	return false
+}
+
+func (bf LockFilename) IsIgnored() bool {
+	// The lock file is a hidden file, which is never ignored
+	return false
+}
+
+type LockFiles = filestore.Files[LockFilename, hcl.File]
+
+type LockDiags = filestore.Diags[LockFilename]
+
+func LockDiagsFromMap(m map[string]hcl.Diagnostics) LockDiags {
+	return filestore.DiagsFromMap[LockFilename](m)
+}
+
+type SourceLockDiags = filestore.SourceDiags[LockFilename]
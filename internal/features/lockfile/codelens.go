@@ -0,0 +1,74 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lockfile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/opentofu/tofu-ls/internal/codelens"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/lockfile/decoder"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+// providerDeclarationsLens returns a code lens for each locked provider,
+// which shows the required_providers entries declaring the provider
+// via the references request (see [LockFileFeature.ProviderDeclarations])
+func providerDeclarationsLens(showReferencesCmdId string, moduleReader fdecoder.ModuleReader) lang.CodeLensFunc {
+	return func(ctx context.Context, path lang.Path, file string) ([]lang.CodeLens, error) {
+		lenses := make([]lang.CodeLens, 0)
+
+		if path.LanguageID != ilsp.OpenTofuLock.String() {
+			return lenses, nil
+		}
+
+		pathCtx, err := decoder.PathCtx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		hclFile, ok := pathCtx.Files[file]
+		if !ok {
+			return lenses, nil
+		}
+
+		lockFiles := ast.LockFiles{ast.LockFilename(file): hclFile}
+		for _, lp := range fdecoder.LockedProviders(lockFiles) {
+			count := 0
+			for _, req := range fdecoder.ModuleRequirements(ctx, moduleReader, path.Path, lp.Addr) {
+				if req.DeclRange != nil {
+					count++
+				}
+			}
+			if count == 0 {
+				continue
+			}
+
+			title := fmt.Sprintf("%d declarations", count)
+			if count == 1 {
+				title = "1 declaration"
+			}
+
+			labelRange := lp.Block.LabelRanges[0]
+			lenses = append(lenses, lang.CodeLens{
+				Range: lp.Block.Range(),
+				Command: lang.Command{
+					Title: title,
+					ID:    showReferencesCmdId,
+					Arguments: []lang.CommandArgument{
+						codelens.Position(ilsp.HCLPosToLSP(labelRange.Start)),
+						codelens.ReferenceContext(lsp.ReferenceContext{}),
+					},
+				},
+			})
+		}
+
+		return lenses, nil
+	}
+}
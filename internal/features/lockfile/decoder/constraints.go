@@ -0,0 +1,48 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/ast"
+)
+
+// UnsatisfiedConstraints reports locked provider versions which do not
+// satisfy the version constraints of modules using the lock file,
+// which would make `tofu init` fail until the lock file is upgraded
+func UnsatisfiedConstraints(ctx context.Context, lockPath string, files ast.LockFiles, moduleReader ModuleReader) lang.DiagnosticsMap {
+	diagsMap := make(lang.DiagnosticsMap)
+
+	for _, lp := range LockedProviders(files) {
+		if lp.Version == nil {
+			continue
+		}
+
+		for _, req := range ModuleRequirements(ctx, moduleReader, lockPath, lp.Addr) {
+			if len(req.Constraints) == 0 {
+				continue
+			}
+			if req.Constraints.Check(lp.Version) {
+				continue
+			}
+
+			diagsMap[lp.Filename] = diagsMap[lp.Filename].Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Locked version does not satisfy constraints",
+				Detail: fmt.Sprintf("Version %s of %s does not satisfy the constraints %q of module %q. "+
+					"Run `tofu init -upgrade` to select a matching version.",
+					lp.Version, lp.Addr.ForDisplay(), req.Constraints, relativeModulePath(lockPath, req.ModulePath)),
+				Subject: lp.VersionRange,
+			})
+		}
+	}
+
+	return diagsMap
+}
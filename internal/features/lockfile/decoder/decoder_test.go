@@ -0,0 +1,193 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/lockfile/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+type ModuleReaderMock struct {
+	requirements map[string]tfmod.ProviderRequirements
+}
+
+func (m ModuleReaderMock) Paths(ctx context.Context) []lang.Path {
+	paths := make([]lang.Path, 0)
+	for modPath := range m.requirements {
+		paths = append(paths, lang.Path{
+			Path:       modPath,
+			LanguageID: ilsp.OpenTofu.String(),
+		})
+	}
+	return paths
+}
+
+func (m ModuleReaderMock) ProviderRequirements(modPath string) (tfmod.ProviderRequirements, error) {
+	return m.requirements[modPath], nil
+}
+
+func (m ModuleReaderMock) RequiredProviderRanges(modPath string) (map[tfaddr.Provider]hcl.Range, error) {
+	return nil, nil
+}
+
+func (m ModuleReaderMock) MetadataReady(dir document.DirHandle) (<-chan struct{}, bool, error) {
+	return nil, true, nil
+}
+
+var testLockFile = `provider "registry.opentofu.org/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+}
+
+provider "registry.opentofu.org/hashicorp/random" {
+  version = "3.6.0"
+}
+`
+
+// lockPathDecoder returns a decoder of the given lock file
+// of a root module with a child module in a subdirectory
+func lockPathDecoder(t *testing.T, src string, moduleReader fdecoder.ModuleReader, lockPath string) *decoder.PathDecoder {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls, err := state.NewLockFileStore(globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ls.Add(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, diags := hclsyntax.ParseConfig([]byte(src), ".terraform.lock.hcl", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	err = ls.UpdateParsedFiles(lockPath, ast.LockFiles{".terraform.lock.hcl": f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:  ls,
+		ModuleReader: moduleReader,
+	})
+	pd, err := d.Path(lang.Path{
+		Path:       lockPath,
+		LanguageID: ilsp.OpenTofuLock.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pd
+}
+
+func testModuleReader(lockPath string) ModuleReaderMock {
+	aws := tfaddr.MustParseProviderSource("hashicorp/aws")
+	return ModuleReaderMock{
+		requirements: map[string]tfmod.ProviderRequirements{
+			lockPath: {
+				aws: version.MustConstraints(version.NewConstraint("~> 5.0")),
+			},
+			filepath.Join(lockPath, "modules", "network"): {
+				aws: version.MustConstraints(version.NewConstraint(">= 5.40")),
+			},
+			// Another root module with its own lock file
+			filepath.Join(filepath.Dir(lockPath), "other"): {
+				aws: version.MustConstraints(version.NewConstraint(">= 6.0")),
+			},
+		},
+	}
+}
+
+func TestDecoder_lockFileHover(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "root")
+	pd := lockPathDecoder(t, testLockFile, testModuleReader(lockPath), lockPath)
+
+	hoverData, err := pd.HoverAtPos(context.Background(), ".terraform.lock.hcl", hcl.Pos{
+		Line:   1,
+		Column: 20,
+		Byte:   19,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The other root module is not listed as it does not use the lock file
+	expectedContent := lang.Markdown("`registry.opentofu.org/hashicorp/aws` source address\n\n" +
+		"Required by:\n\n" +
+		"- `.` (`~> 5.0`)\n" +
+		"- `modules/network` (`>= 5.40`)")
+	if diff := cmp.Diff(expectedContent, hoverData.Content); diff != "" {
+		t.Fatalf("unexpected hover content: %s", diff)
+	}
+
+	hoverData, err = pd.HoverAtPos(context.Background(), ".terraform.lock.hcl", hcl.Pos{
+		Line:   6,
+		Column: 20,
+		Byte:   121,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedContent = lang.Markdown("`registry.opentofu.org/hashicorp/random` source address\n\n" +
+		"Not required by any module using the lock file")
+	if diff := cmp.Diff(expectedContent, hoverData.Content); diff != "" {
+		t.Fatalf("unexpected hover content: %s", diff)
+	}
+}
+
+func TestUnsatisfiedConstraints(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "root")
+	f, diags := hclsyntax.ParseConfig([]byte(testLockFile), ".terraform.lock.hcl", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	diagsMap := fdecoder.UnsatisfiedConstraints(context.Background(), lockPath,
+		ast.LockFiles{".terraform.lock.hcl": f}, testModuleReader(lockPath))
+
+	// The constraints of the other root module are not checked
+	// as it does not use the lock file
+	expectedDiags := lang.DiagnosticsMap{
+		".terraform.lock.hcl": hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Locked version does not satisfy constraints",
+				Detail: `Version 5.31.0 of hashicorp/aws does not satisfy the constraints ">= 5.40" ` +
+					"of module \"modules/network\". Run `tofu init -upgrade` to select a matching version.",
+				Subject: &hcl.Range{
+					Filename: ".terraform.lock.hcl",
+					Start:    hcl.Pos{Line: 2, Column: 17, Byte: 65},
+					End:      hcl.Pos{Line: 2, Column: 25, Byte: 73},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diagsMap); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
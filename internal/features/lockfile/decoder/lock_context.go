@@ -0,0 +1,29 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/state"
+)
+
+func lockPathContext(record *state.LockFileRecord, moduleReader ModuleReader) (*decoder.PathContext, error) {
+	pathCtx := &decoder.PathContext{
+		Schema:           lockFileSchema(record.Path(), LockedProviders(record.ParsedFiles), moduleReader),
+		ReferenceOrigins: make(reference.Origins, 0),
+		ReferenceTargets: make(reference.Targets, 0),
+		Files:            make(map[string]*hcl.File),
+		Validators:       lockFileValidators,
+	}
+
+	for name, f := range record.ParsedFiles {
+		pathCtx.Files[name.String()] = f
+	}
+
+	return pathCtx, nil
+}
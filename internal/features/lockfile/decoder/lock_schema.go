@@ -0,0 +1,90 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const lockFileDocsURL = "https://opentofu.org/docs/language/files/dependency-lock/"
+
+// lockFileSchema returns the schema of the dependency lock file, where
+// the description of each locked provider lists the modules requiring it
+func lockFileSchema(lockPath string, providers []LockedProvider, moduleReader ModuleReader) *schema.BodySchema {
+	dependentBody := make(map[schema.SchemaKey]*schema.BodySchema, len(providers))
+	for _, lp := range providers {
+		key := schema.NewSchemaKey(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: 0, Value: lp.Label},
+			},
+		})
+		requirements := ModuleRequirements(context.Background(), moduleReader, lockPath, lp.Addr)
+		dependentBody[key] = &schema.BodySchema{
+			Description: lang.Markdown(requirementsDescription(lockPath, requirements)),
+		}
+	}
+
+	return &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"provider": {
+				Labels: []*schema.LabelSchema{
+					{
+						Name:        "source address",
+						Description: lang.PlainText("Fully-qualified source address of the provider"),
+						IsDepKey:    true,
+					},
+				},
+				Description: lang.Markdown("Provider version selected by `tofu init` " +
+					"along with checksums of its packages"),
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"version": {
+							Constraint:  schema.LiteralType{Type: cty.String},
+							IsRequired:  true,
+							Description: lang.Markdown("Selected version of the provider"),
+						},
+						"constraints": {
+							Constraint:  schema.LiteralType{Type: cty.String},
+							IsOptional:  true,
+							Description: lang.Markdown("Version constraints of all modules the version was selected for"),
+						},
+						"hashes": {
+							Constraint:  schema.List{Elem: schema.LiteralType{Type: cty.String}},
+							IsOptional:  true,
+							Description: lang.Markdown("Checksums of the provider packages considered valid"),
+						},
+					},
+					HoverURL: lockFileDocsURL,
+				},
+				DependentBody: dependentBody,
+			},
+		},
+		HoverURL: lockFileDocsURL,
+	}
+}
+
+func requirementsDescription(lockPath string, requirements []ModuleRequirement) string {
+	if len(requirements) == 0 {
+		return "Not required by any module using the lock file"
+	}
+
+	lines := make([]string, 0, len(requirements))
+	for _, req := range requirements {
+		constraints := "any version"
+		if len(req.Constraints) > 0 {
+			constraints = fmt.Sprintf("`%s`", req.Constraints)
+		}
+		lines = append(lines, fmt.Sprintf("- `%s` (%s)", relativeModulePath(lockPath, req.ModulePath), constraints))
+	}
+
+	return "Required by:\n\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,72 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+)
+
+type StateReader interface {
+	List() ([]*state.LockFileRecord, error)
+	RecordByPath(path string) (*state.LockFileRecord, error)
+}
+
+type ModuleReader interface {
+	Paths(ctx context.Context) []lang.Path
+	ProviderRequirements(modPath string) (tfmod.ProviderRequirements, error)
+	RequiredProviderRanges(modPath string) (map[tfaddr.Provider]hcl.Range, error)
+	MetadataReady(dir document.DirHandle) (<-chan struct{}, bool, error)
+}
+
+type PathReader struct {
+	StateReader  StateReader
+	ModuleReader ModuleReader
+}
+
+var _ decoder.PathReader = &PathReader{}
+
+func (pr *PathReader) Paths(ctx context.Context) []lang.Path {
+	paths := make([]lang.Path, 0)
+
+	lockFileRecords, err := pr.StateReader.List()
+	if err != nil {
+		return paths
+	}
+
+	for _, record := range lockFileRecords {
+		paths = append(paths, lang.Path{
+			Path:       record.Path(),
+			LanguageID: ilsp.OpenTofuLock.String(),
+		})
+	}
+
+	return paths
+}
+
+// PathContext returns a PathContext for the given path based on the language ID.
+func (pr *PathReader) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	record, err := pr.StateReader.RecordByPath(path.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch path.LanguageID {
+	case ilsp.OpenTofuLock.String():
+		return lockPathContext(record, pr.ModuleReader)
+	}
+
+	return nil, fmt.Errorf("unknown language ID: %q", path.LanguageID)
+}
@@ -0,0 +1,168 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/ast"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// LockedProvider represents a provider block of the lock file
+type LockedProvider struct {
+	Filename string
+	Addr     tfaddr.Provider
+	Label    string
+	Block    *hclsyntax.Block
+
+	// Version is the locked version, if it is valid
+	Version      *version.Version
+	VersionRange *hcl.Range
+}
+
+// LockedProviders returns all provider blocks of the given lock files
+// with a valid source address, in the order they appear in the files
+func LockedProviders(files ast.LockFiles) []LockedProvider {
+	providers := make([]LockedProvider, 0)
+
+	for name, file := range files {
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "provider" || len(block.Labels) != 1 {
+				continue
+			}
+			pAddr, err := tfaddr.ParseProviderSource(block.Labels[0])
+			if err != nil {
+				continue
+			}
+
+			lp := LockedProvider{
+				Filename: name.String(),
+				Addr:     pAddr,
+				Label:    block.Labels[0],
+				Block:    block,
+			}
+
+			if attr, ok := block.Body.Attributes["version"]; ok {
+				val, diags := attr.Expr.Value(nil)
+				if !diags.HasErrors() && val.IsWhollyKnown() && !val.IsNull() && val.Type().Equals(cty.String) {
+					v, err := version.NewVersion(val.AsString())
+					if err == nil {
+						lp.Version = v
+						lp.VersionRange = attr.Expr.Range().Ptr()
+					}
+				}
+			}
+
+			providers = append(providers, lp)
+		}
+	}
+
+	sort.SliceStable(providers, func(i, j int) bool {
+		if providers[i].Filename != providers[j].Filename {
+			return providers[i].Filename < providers[j].Filename
+		}
+		return providers[i].Block.Range().Start.Byte < providers[j].Block.Range().Start.Byte
+	})
+
+	return providers
+}
+
+// ModuleRequirement represents a module which requires a locked provider
+type ModuleRequirement struct {
+	ModulePath  string
+	Constraints version.Constraints
+
+	// DeclRange is the range of the required_providers entry,
+	// if the module declares the provider explicitly
+	DeclRange *hcl.Range
+}
+
+// ModuleRequirements returns all modules using the lock file
+// (see [usesLockFile]) which require the given provider,
+// sorted by their path
+func ModuleRequirements(ctx context.Context, moduleReader ModuleReader, lockPath string, pAddr tfaddr.Provider) []ModuleRequirement {
+	requirements := make([]ModuleRequirement, 0)
+
+	for _, path := range moduleReader.Paths(ctx) {
+		if !usesLockFile(lockPath, path.Path) {
+			continue
+		}
+		pReqs, err := moduleReader.ProviderRequirements(path.Path)
+		if err != nil {
+			continue
+		}
+		declRanges, err := moduleReader.RequiredProviderRanges(path.Path)
+		if err != nil {
+			declRanges = nil
+		}
+
+		for reqAddr, constraints := range pReqs {
+			if !normalizedProvider(reqAddr).Equals(pAddr) {
+				continue
+			}
+
+			req := ModuleRequirement{
+				ModulePath:  path.Path,
+				Constraints: constraints,
+			}
+			if rng, ok := declRanges[reqAddr]; ok {
+				req.DeclRange = rng.Ptr()
+			}
+			requirements = append(requirements, req)
+		}
+	}
+
+	sort.SliceStable(requirements, func(i, j int) bool {
+		return requirements[i].ModulePath < requirements[j].ModulePath
+	})
+
+	return requirements
+}
+
+// normalizedProvider returns the address OpenTofu installs
+// a required provider from, which is recorded in the lock file.
+// Providers without an explicit source are implied to be
+// in the hashicorp namespace of the default registry.
+func normalizedProvider(pAddr tfaddr.Provider) tfaddr.Provider {
+	if pAddr.IsLegacy() {
+		return tfaddr.NewProvider(tfaddr.DefaultProviderRegistryHost, "hashicorp", pAddr.Type)
+	}
+	return pAddr
+}
+
+// usesLockFile returns true if the module is the root module which the
+// lock file belongs to or any module within its directory, e.g. local
+// or installed child modules, which are all installed together
+func usesLockFile(lockPath, modPath string) bool {
+	rel, err := filepath.Rel(lockPath, modPath)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// relativeModulePath returns the path of the module for display,
+// relative to the directory of the lock file when possible
+func relativeModulePath(lockPath, modPath string) string {
+	rel, err := filepath.Rel(lockPath, modPath)
+	if err != nil {
+		return modPath
+	}
+	return filepath.ToSlash(rel)
+}
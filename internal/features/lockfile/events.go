@@ -0,0 +1,39 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lockfile
+
+import (
+	"context"
+
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/jobs"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	"github.com/opentofu/tofu-ls/internal/job"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+func (f *LockFileFeature) eventHooks() filestore.Hooks {
+	return filestore.Hooks{
+		Parse: func(ctx context.Context, path string) error {
+			return jobs.ParseLockFiles(ctx, f.fs, f.store, path)
+		},
+		ParseType: op.OpTypeParseLockFiles,
+		Validate: func(ctx context.Context, path string) error {
+			return jobs.SchemaLockFileValidation(ctx, f.store, f.moduleFeature, path)
+		},
+		ValidateType: op.OpTypeSchemaLockFileValidation,
+		Index:        f.indexRootModule,
+	}
+}
+
+// indexRootModule makes sure the root module, which may not have
+// any open files, gets indexed before the lock file is decoded
+func (f *LockFileFeature) indexRootModule(ctx context.Context, path string) job.IDs {
+	ids, err := f.moduleFeature.IndexModule(ctx, path)
+	if err != nil {
+		f.logger.Printf("failed to index root module for %q: %s", path, err)
+	}
+	return ids
+}
@@ -0,0 +1,20 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/parser"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/state"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+)
+
+// ParseLockFiles parses the dependency lock file,
+// i.e. turns bytes of `.terraform.lock.hcl` into AST ([*hcl.File]).
+func ParseLockFiles(ctx context.Context, fs ReadOnlyFS, lockFileStore *state.LockFileStore, lockPath string) error {
+	return filestore.ParseFiles(ctx, fs, lockFileStore, parser.LockFiles, lockPath)
+}
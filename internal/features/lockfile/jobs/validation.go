@@ -0,0 +1,95 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/lockfile/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// SchemaLockFileValidation does schema-based validation
+// of the dependency lock file (.terraform.lock.hcl) and produces
+// diagnostics associated with any "invalid" parts of code.
+//
+// It relies on previously parsed AST (via [ParseLockFiles]) and
+// also checks the locked versions against the version constraints
+// of the root module and its child modules.
+func SchemaLockFileValidation(ctx context.Context, lockFileStore *state.LockFileStore, moduleFeature fdecoder.ModuleReader, lockPath string) error {
+	record, err := lockFileStore.RecordByPath(lockPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid validation if it is already in progress or already finished
+	if record.DiagnosticsState[globalAst.SchemaValidationSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(lockPath)}
+	}
+
+	err = lockFileStore.SetDiagnosticsState(lockPath, globalAst.SchemaValidationSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	// We only wait a short period for the root module to become ready
+	// If we have to cancel the validation, we will just run it after the next change.
+	timer := time.NewTimer(2 * time.Second)
+	defer timer.Stop()
+	wCh, moduleReady, err := moduleFeature.MetadataReady(document.DirHandleFromPath(lockPath))
+	if err == nil && !moduleReady {
+		select {
+		// Wait for module to be ready
+		case <-wCh:
+		// or for the remaining time to pass
+		case <-timer.C:
+		// or context cancellation
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:  lockFileStore,
+		ModuleReader: moduleFeature,
+	})
+	d.SetContext(idecoder.DecoderContext(ctx))
+
+	lockDecoder, err := d.Path(lang.Path{
+		Path:       lockPath,
+		LanguageID: ilsp.OpenTofuLock.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// There is only a single lock file per directory,
+	// so we always validate the whole directory
+	diags, rErr := lockDecoder.Validate(ctx)
+	if diags == nil {
+		diags = make(lang.DiagnosticsMap)
+	}
+	for filename, fileDiags := range fdecoder.UnsatisfiedConstraints(ctx, lockPath, record.ParsedFiles, moduleFeature) {
+		diags[filename] = diags[filename].Extend(fileDiags)
+	}
+
+	sErr := lockFileStore.UpdateDiagnostics(lockPath, globalAst.SchemaValidationSource, ast.LockDiagsFromMap(diags))
+	if sErr != nil {
+		return sErr
+	}
+
+	return rErr
+}
@@ -0,0 +1,182 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lockfile
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/lockfile/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/jobs"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/parser"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/state"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+// ModuleFeature provides access to the modules requiring the locked providers.
+// The root module is indexed as soon as its lock file is opened.
+type ModuleFeature interface {
+	fdecoder.ModuleReader
+	IndexModule(ctx context.Context, modPath string) (job.IDs, error)
+}
+
+// LockFileFeature groups everything related to dependency lock files.
+// Its internal state keeps track of all such files in the workspace.
+type LockFileFeature struct {
+	store    *state.LockFileStore
+	eventbus *eventbus.EventBus
+	stopFunc context.CancelFunc
+	logger   *log.Logger
+	events   *filestore.Events[ast.LockFilename, hcl.File]
+
+	moduleFeature ModuleFeature
+	stateStore    *globalState.StateStore
+	fs            jobs.ReadOnlyFS
+}
+
+func NewLockFileFeature(eventbus *eventbus.EventBus, stateStore *globalState.StateStore, fs jobs.ReadOnlyFS, moduleFeature ModuleFeature) (*LockFileFeature, error) {
+	store, err := state.NewLockFileStore(stateStore.ChangeStore)
+	if err != nil {
+		return nil, err
+	}
+	discardLogger := log.New(io.Discard, "", 0)
+
+	f := &LockFileFeature{
+		store:         store,
+		eventbus:      eventbus,
+		stopFunc:      func() {},
+		logger:        discardLogger,
+		moduleFeature: moduleFeature,
+		stateStore:    stateStore,
+		fs:            fs,
+	}
+	f.events = filestore.NewEvents("dependency lock files", store, stateStore, parser.LockFiles, f.eventHooks())
+
+	return f, nil
+}
+
+func (f *LockFileFeature) SetLogger(logger *log.Logger) {
+	f.logger = logger
+	f.store.SetLogger(logger)
+	f.events.SetLogger(logger)
+}
+
+// Start starts the features separate goroutine.
+// It listens to various events from the EventBus and performs corresponding actions.
+func (f *LockFileFeature) Start(ctx context.Context) {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	f.stopFunc = cancelFunc
+
+	f.events.Start(ctx, f.eventbus, "feature.lockfile")
+}
+
+func (f *LockFileFeature) Stop() {
+	f.stopFunc()
+	f.logger.Print("stopped lock file feature")
+}
+
+func (f *LockFileFeature) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	pathReader := &fdecoder.PathReader{
+		StateReader:  f.store,
+		ModuleReader: f.moduleFeature,
+	}
+
+	return pathReader.PathContext(path)
+}
+
+func (f *LockFileFeature) Paths(ctx context.Context) []lang.Path {
+	pathReader := &fdecoder.PathReader{
+		StateReader:  f.store,
+		ModuleReader: f.moduleFeature,
+	}
+
+	return pathReader.Paths(ctx)
+}
+
+// ParsedFile returns the parsed lock file, regardless
+// of whether it could be decoded against the schema
+func (f *LockFileFeature) ParsedFile(lockPath string, filename string) (*hcl.File, bool) {
+	record, err := f.store.RecordByPath(lockPath)
+	if err != nil {
+		return nil, false
+	}
+
+	file, ok := record.ParsedFiles[ast.LockFilename(filename)]
+	return file, ok && file != nil
+}
+
+func (f *LockFileFeature) Diagnostics(path string) diagnostics.Diagnostics {
+	diags := diagnostics.NewDiagnostics()
+
+	record, err := f.store.RecordByPath(path)
+	if err != nil {
+		return diags
+	}
+
+	for source, bd := range record.Diagnostics {
+		diags.Append(source, bd.AsMap())
+	}
+
+	return diags
+}
+
+// ProviderDeclarations returns the required_providers entries declaring
+// the provider locked by the block at the given position
+func (f *LockFileFeature) ProviderDeclarations(ctx context.Context, lockPath string, filename string, pos hcl.Pos) decoder.ReferenceOrigins {
+	origins := make(decoder.ReferenceOrigins, 0)
+
+	record, err := f.store.RecordByPath(lockPath)
+	if err != nil {
+		return origins
+	}
+
+	for _, lp := range fdecoder.LockedProviders(record.ParsedFiles) {
+		if lp.Filename != filename || !lp.Block.Range().ContainsPos(pos) {
+			continue
+		}
+
+		for _, req := range fdecoder.ModuleRequirements(ctx, f.moduleFeature, lockPath, lp.Addr) {
+			if req.DeclRange == nil {
+				continue
+			}
+			origins = append(origins, decoder.ReferenceOrigin{
+				Path: lang.Path{
+					Path:       req.ModulePath,
+					LanguageID: ilsp.OpenTofu.String(),
+				},
+				Range: *req.DeclRange,
+			})
+		}
+	}
+
+	return origins
+}
+
+// AppendCodeLenses appends the code lens linking locked providers
+// to their declarations, if the client can show references
+func (f *LockFileFeature) AppendCodeLenses(ctx context.Context, lenses []lang.CodeLensFunc) []lang.CodeLensFunc {
+	cc, err := ilsp.ClientCapabilities(ctx)
+	if err != nil {
+		return lenses
+	}
+	cmdId, ok := lsp.ExperimentalClientCapabilities(cc.Experimental).ShowReferencesCommandId()
+	if !ok {
+		return lenses
+	}
+
+	return append(lenses, providerDeclarationsLens(cmdId, f.moduleFeature))
+}
@@ -0,0 +1,23 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/ast"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/tofu/parser"
+)
+
+// LockFiles recognizes and parses dependency lock files
+var LockFiles = filestore.Parser[ast.LockFilename, hcl.File]{
+	NewFilename: ast.NewLockFilename,
+	ParseFile: func(src []byte, filename ast.LockFilename) (*hcl.File, hcl.Diagnostics) {
+		return parser.ParseFile(src, filename)
+	},
+	IsValidLanguage: ilsp.IsValidLockLanguage,
+}
@@ -0,0 +1,35 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/lockfile/ast"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+const (
+	lockFileTableName = "lockfile"
+)
+
+// LockFileStore keeps the dependency lock file of each directory
+type LockFileStore = filestore.Store[ast.LockFilename, hcl.File]
+
+// LockFileRecord contains all information about the dependency
+// lock file we have for a certain path
+type LockFileRecord = filestore.Record[ast.LockFilename, hcl.File]
+
+// diagnosticSources are the sources of diagnostics tracked per record
+var diagnosticSources = []globalAst.DiagnosticSource{
+	globalAst.HCLParsingSource,
+	globalAst.SchemaValidationSource,
+}
+
+func NewLockFileStore(changeStore *globalState.ChangeStore) (*LockFileStore, error) {
+	return filestore.NewStore[ast.LockFilename, hcl.File](lockFileTableName, diagnosticSources, changeStore)
+}
@@ -29,7 +29,8 @@ func (mf ModFilename) IsIgnored() bool {
 func IsModuleFilename(name string) bool {
 	return strings.HasSuffix(name, ".tf") ||
 		strings.HasSuffix(name, ".tofu") ||
-		strings.HasSuffix(name, ".tf.json")
+		strings.HasSuffix(name, ".tf.json") ||
+		strings.HasSuffix(name, ".tofu.json")
 }
 
 type ModFiles map[ModFilename]*hcl.File
@@ -50,6 +51,38 @@ func (mf ModFiles) AsMap() map[string]*hcl.File {
 	return m
 }
 
+// ShadowedBy returns the name of the file which shadows the given file,
+// i.e. causes OpenTofu to ignore it, if there is any such file
+func (mf ModFiles) ShadowedBy(name ModFilename) (ModFilename, bool) {
+	shadowingName, ok := globalAst.ShadowingFilename(name.String())
+	if !ok {
+		return "", false
+	}
+	if _, exists := mf[ModFilename(shadowingName)]; !exists {
+		return "", false
+	}
+	return ModFilename(shadowingName), true
+}
+
+// IsShadowed returns true if OpenTofu ignores the given file
+// in favour of a same-named .tofu (or .tofu.json) file
+func (mf ModFiles) IsShadowed(name ModFilename) bool {
+	_, ok := mf.ShadowedBy(name)
+	return ok
+}
+
+// Unshadowed returns only files which are not shadowed,
+// i.e. those which OpenTofu loads as part of the module
+func (mf ModFiles) Unshadowed() ModFiles {
+	m := make(ModFiles, len(mf))
+	for name, file := range mf {
+		if !mf.IsShadowed(name) {
+			m[name] = file
+		}
+	}
+	return m
+}
+
 func (mf ModFiles) Copy() ModFiles {
 	m := make(ModFiles, len(mf))
 	for name, file := range mf {
@@ -6,6 +6,7 @@
 package ast
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -41,3 +42,39 @@ func TestModuleDiags_autoloadedOnly(t *testing.T) {
 		t.Fatalf("unexpected diagnostics: %s", diff)
 	}
 }
+
+func TestModFiles_shadowed(t *testing.T) {
+	mf := ModFiles{
+		"main.tf":          &hcl.File{},
+		"main.tofu":        &hcl.File{},
+		"other.tf":         &hcl.File{},
+		"data.tf.json":     &hcl.File{},
+		"data.tofu.json":   &hcl.File{},
+		"outputs.tofu":     &hcl.File{},
+		"outputs.tf.json":  &hcl.File{},
+		"variables.tf.bak": &hcl.File{},
+	}
+
+	shadowingName, ok := mf.ShadowedBy("main.tf")
+	if !ok || shadowingName != "main.tofu" {
+		t.Fatalf("expected main.tf to be shadowed by main.tofu, given %q", shadowingName)
+	}
+
+	unshadowed := make([]string, 0)
+	for name := range mf.Unshadowed() {
+		unshadowed = append(unshadowed, name.String())
+	}
+	sort.Strings(unshadowed)
+
+	expectedNames := []string{
+		"data.tofu.json",
+		"main.tofu",
+		"other.tf",
+		"outputs.tf.json",
+		"outputs.tofu",
+		"variables.tf.bak",
+	}
+	if diff := cmp.Diff(expectedNames, unshadowed); diff != "" {
+		t.Fatalf("unexpected unshadowed files: %s", diff)
+	}
+}
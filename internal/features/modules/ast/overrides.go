@@ -0,0 +1,355 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// IsOverride returns true if the file is an override file,
+// i.e. override.tf or a file with the _override.tf suffix,
+// whose blocks are merged into blocks of the primary files.
+// See https://opentofu.org/docs/language/files/override/
+func (mf ModFilename) IsOverride() bool {
+	name := mf.String()
+	for _, ext := range []string{".tofu.json", ".tf.json", ".tofu", ".tf"} {
+		if strings.HasSuffix(name, ext) {
+			base := strings.TrimSuffix(name, ext)
+			return base == "override" || strings.HasSuffix(base, "_override")
+		}
+	}
+	return false
+}
+
+// Primary returns only primary (i.e. non-override) files
+func (mf ModFiles) Primary() ModFiles {
+	m := make(ModFiles, len(mf))
+	for name, file := range mf {
+		if !name.IsOverride() {
+			m[name] = file
+		}
+	}
+	return m
+}
+
+// OverrideFilenames returns names of override files in the lexical
+// order in which OpenTofu merges them into the primary files
+func (mf ModFiles) OverrideFilenames() []ModFilename {
+	names := make([]ModFilename, 0)
+	for name := range mf {
+		if name.IsOverride() {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// overridableBlocks are top-level block types which can be overridden,
+// mapped to the number of labels identifying the block
+var overridableBlocks = map[string]int{
+	"resource":  2,
+	"data":      2,
+	"module":    1,
+	"variable":  1,
+	"output":    1,
+	"provider":  1,
+	"terraform": 0,
+}
+
+// localType is the type of [BlockOverride] representing
+// a single overriding value of a locals block
+const localType = "local"
+
+// BlockOverride represents a block of an override file
+// which is merged into a block of a primary file.
+//
+// Locals are overridden one by one, so each value of a locals block
+// is represented separately, with the local type and its name as label.
+type BlockOverride struct {
+	Type   string
+	Labels []string
+
+	// Filename is the name of the override file
+	Filename ModFilename
+	// DefRange is the range of the header of the overriding block
+	DefRange hcl.Range
+	// Range is the range of the whole overriding block
+	Range hcl.Range
+
+	// BaseRangePtr is the range of the primary block
+	// which is overridden or nil if there is no such block
+	BaseRangePtr *hcl.Range
+	// BaseDefRangePtr is the range of the header of the primary block
+	BaseDefRangePtr *hcl.Range
+}
+
+// Address returns the block type and labels joined by dots,
+// e.g. resource.aws_instance.foo or local.foo
+func (bo BlockOverride) Address() string {
+	return strings.Join(append([]string{bo.Type}, bo.Labels...), ".")
+}
+
+// AttributeOverride represents an attribute of an override file which
+// takes precedence over the same attribute of a primary file
+type AttributeOverride struct {
+	Name string
+
+	// Range is the range of the overriding attribute
+	Range hcl.Range
+	// BaseRange is the range of the overridden attribute
+	BaseRange hcl.Range
+}
+
+// Overrides represents all overrides of a module,
+// resulting from merging its override files into its primary files
+type Overrides struct {
+	Blocks     []BlockOverride
+	Attributes []AttributeOverride
+}
+
+type blockKey struct {
+	Type   string
+	Labels string
+}
+
+// MergeOverrides models the way OpenTofu merges override files
+// into the primary files of a module. Attributes of an overriding
+// block replace the same attributes of the primary block, locals
+// are overridden one by one and later (in lexical order) override
+// files take precedence over earlier ones.
+//
+// Only native syntax files are considered.
+func MergeOverrides(files ModFiles) Overrides {
+	files = files.Unshadowed()
+	overrides := Overrides{
+		Blocks:     make([]BlockOverride, 0),
+		Attributes: make([]AttributeOverride, 0),
+	}
+
+	bases := make(map[blockKey]*hclsyntax.Block, 0)
+	locals := make(map[string]*hclsyntax.Attribute, 0)
+	for _, file := range files.Primary() {
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type == "locals" {
+				for name, attr := range block.Body.Attributes {
+					locals[name] = attr
+				}
+				continue
+			}
+			nLabels, ok := overridableBlocks[block.Type]
+			if !ok || len(block.Labels) != nLabels {
+				continue
+			}
+			bases[keyForBlock(block)] = block
+		}
+	}
+
+	for _, name := range files.OverrideFilenames() {
+		body, ok := files[name].Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type == "locals" {
+				for _, attrName := range sortedAttributeNames(block.Body.Attributes) {
+					attr := block.Body.Attributes[attrName]
+					bo := BlockOverride{
+						Type:     localType,
+						Labels:   []string{attrName},
+						Filename: name,
+						DefRange: attr.NameRange,
+						Range:    attr.SrcRange,
+					}
+					if baseAttr, ok := locals[attrName]; ok {
+						bo.BaseRangePtr = baseAttr.SrcRange.Ptr()
+						bo.BaseDefRangePtr = baseAttr.NameRange.Ptr()
+						overrides.Attributes = append(overrides.Attributes, AttributeOverride{
+							Name:      attrName,
+							Range:     attr.SrcRange,
+							BaseRange: baseAttr.SrcRange,
+						})
+					}
+					overrides.Blocks = append(overrides.Blocks, bo)
+				}
+				continue
+			}
+			nLabels, ok := overridableBlocks[block.Type]
+			if !ok || len(block.Labels) != nLabels {
+				continue
+			}
+
+			bo := BlockOverride{
+				Type:     block.Type,
+				Labels:   block.Labels,
+				Filename: name,
+				DefRange: block.DefRange(),
+				Range:    block.Range(),
+			}
+
+			base, ok := bases[keyForBlock(block)]
+			if ok {
+				baseRng := base.Range()
+				baseDefRng := base.DefRange()
+				bo.BaseRangePtr = &baseRng
+				bo.BaseDefRangePtr = &baseDefRng
+
+				for _, attrName := range sortedAttributeNames(block.Body.Attributes) {
+					attr := block.Body.Attributes[attrName]
+					baseAttr, ok := base.Body.Attributes[attrName]
+					if !ok {
+						continue
+					}
+					overrides.Attributes = append(overrides.Attributes, AttributeOverride{
+						Name:      attrName,
+						Range:     attr.SrcRange,
+						BaseRange: baseAttr.SrcRange,
+					})
+				}
+			}
+
+			overrides.Blocks = append(overrides.Blocks, bo)
+		}
+	}
+
+	return overrides
+}
+
+// OverridesOfBlock returns all overrides of the primary block
+// at the given range, in the order in which they are applied
+func (o Overrides) OverridesOfBlock(rng hcl.Range) []BlockOverride {
+	blocks := make([]BlockOverride, 0)
+	for _, bo := range o.Blocks {
+		if bo.BaseRangePtr != nil && rangesEqual(*bo.BaseRangePtr, rng) {
+			blocks = append(blocks, bo)
+		}
+	}
+	return blocks
+}
+
+// EffectiveAttribute returns the attribute override which
+// determines the effective value of the primary attribute
+// at the given position, if the attribute is overridden
+func (o Overrides) EffectiveAttribute(filename string, pos hcl.Pos) (AttributeOverride, bool) {
+	var effective AttributeOverride
+	found := false
+	for _, ao := range o.Attributes {
+		if ao.BaseRange.Filename == filename && ao.BaseRange.ContainsPos(pos) {
+			// later overrides take precedence
+			effective = ao
+			found = true
+		}
+	}
+	return effective, found
+}
+
+// OverriddenAttribute returns the attribute override
+// at the given position of an override file
+func (o Overrides) OverriddenAttribute(filename string, pos hcl.Pos) (AttributeOverride, bool) {
+	for _, ao := range o.Attributes {
+		if ao.Range.Filename == filename && ao.Range.ContainsPos(pos) {
+			return ao, true
+		}
+	}
+	return AttributeOverride{}, false
+}
+
+// MissingBaseDiags returns diagnostics for each block of override files
+// which has no primary block to override, as OpenTofu reports them
+func (o Overrides) MissingBaseDiags() ModDiags {
+	diags := make(ModDiags, 0)
+	for _, bo := range o.Blocks {
+		// settings of terraform blocks are merged
+		// regardless of any primary terraform block
+		if bo.BaseRangePtr != nil || bo.Type == "terraform" {
+			continue
+		}
+
+		diag := &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Missing base %s block to override", bo.Type),
+			Detail: fmt.Sprintf("There is no %s block %q defined in a primary configuration file. "+
+				"An override file can only override a block which is defined in a primary configuration file.",
+				bo.Type, strings.Join(bo.Labels, ".")),
+			Subject: bo.DefRange.Ptr(),
+			Extra:   missingBaseExtra{},
+		}
+		if bo.Type == localType {
+			diag.Summary = "Missing base local value definition to override"
+			diag.Detail = fmt.Sprintf("There is no local value named %q defined in a primary configuration file. "+
+				"An override file can only override a local value which is defined in a primary configuration file.",
+				bo.Labels[0])
+		}
+		diags[bo.Filename] = append(diags[bo.Filename], diag)
+	}
+	return diags
+}
+
+// missingBaseExtra marks diagnostics created via [Overrides.MissingBaseDiags],
+// so that they can be replaced when the overrides change
+type missingBaseExtra struct{}
+
+// IsMissingBaseDiag returns true for diagnostics
+// created via [Overrides.MissingBaseDiags]
+func IsMissingBaseDiag(diag *hcl.Diagnostic) bool {
+	_, ok := hcl.DiagnosticExtra[missingBaseExtra](diag)
+	return ok
+}
+
+// keyForBlock returns the key identifying the block among the blocks
+// it may override, i.e. the type and labels, and the alias in case
+// of provider blocks as each alias configures a separate provider
+func keyForBlock(block *hclsyntax.Block) blockKey {
+	labels := block.Labels
+	if block.Type == "provider" {
+		if alias, ok := providerAlias(block); ok {
+			labels = append(slices.Clone(labels), alias)
+		}
+	}
+
+	return blockKey{
+		Type:   block.Type,
+		Labels: strings.Join(labels, "\x00"),
+	}
+}
+
+func providerAlias(block *hclsyntax.Block) (string, bool) {
+	attr, ok := block.Body.Attributes["alias"]
+	if !ok {
+		return "", false
+	}
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !val.IsKnown() || val.IsNull() || !val.Type().Equals(cty.String) {
+		return "", false
+	}
+	return val.AsString(), true
+}
+
+func sortedAttributeNames(attrs hclsyntax.Attributes) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func rangesEqual(a, b hcl.Range) bool {
+	return a.Filename == b.Filename && a.Start.Byte == b.Start.Byte && a.End.Byte == b.End.Byte
+}
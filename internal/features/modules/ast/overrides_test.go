@@ -0,0 +1,228 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestModFilename_isOverride(t *testing.T) {
+	testCases := map[string]bool{
+		"override.tf":          true,
+		"override.tofu":        true,
+		"override.tf.json":     true,
+		"network_override.tf":  true,
+		"net_override.tofu":    true,
+		"main.tf":              false,
+		"overrides.tf":         false,
+		"override_network.tf":  false,
+		"override.tfvars":      false,
+		"main_override.tf.bak": false,
+	}
+
+	for name, expected := range testCases {
+		if given := ModFilename(name).IsOverride(); given != expected {
+			t.Errorf("%s: expected IsOverride to be %t, given %t", name, expected, given)
+		}
+	}
+}
+
+func TestMergeOverrides(t *testing.T) {
+	files := ModFiles{
+		"main.tf": parseTestFile(t, "main.tf", `resource "aws_instance" "web" {
+  ami           = "ami-1"
+  instance_type = "t2.micro"
+}
+
+locals {
+  name = "web"
+}
+`),
+		"a_override.tf": parseTestFile(t, "a_override.tf", `resource "aws_instance" "web" {
+  ami = "ami-2"
+}
+`),
+		"override.tf": parseTestFile(t, "override.tf", `resource "aws_instance" "web" {
+  ami = "ami-3"
+}
+
+resource "aws_instance" "db" {
+  ami = "ami-4"
+}
+
+locals {
+  name = "db"
+}
+`),
+	}
+
+	overrides := MergeOverrides(files)
+
+	if len(overrides.Blocks) != 4 {
+		t.Fatalf("expected 4 block overrides, %d given", len(overrides.Blocks))
+	}
+	if overrides.Blocks[0].Filename != "a_override.tf" {
+		t.Fatalf("expected overrides to be applied in lexical order, first given: %q", overrides.Blocks[0].Filename)
+	}
+
+	diags := overrides.MissingBaseDiags()
+	if len(diags) != 1 || len(diags["override.tf"]) != 1 {
+		t.Fatalf("expected one diagnostic for override.tf, given %#v", diags)
+	}
+	if !IsMissingBaseDiag(diags["override.tf"][0]) {
+		t.Fatal("expected diagnostic to be recognized as missing base diagnostic")
+	}
+
+	// ami = "ami-1"
+	ao, ok := overrides.EffectiveAttribute("main.tf", hcl.Pos{Line: 2, Column: 5, Byte: 37})
+	if !ok {
+		t.Fatal("expected ami to be overridden")
+	}
+	if ao.Range.Filename != "override.tf" {
+		t.Fatalf("expected effective value from override.tf, given %q", ao.Range.Filename)
+	}
+
+	// instance_type = "t2.micro"
+	_, ok = overrides.EffectiveAttribute("main.tf", hcl.Pos{Line: 3, Column: 5, Byte: 63})
+	if ok {
+		t.Fatal("expected instance_type not to be overridden")
+	}
+
+	// name = "web"
+	ao, ok = overrides.EffectiveAttribute("main.tf", hcl.Pos{Line: 7, Column: 5, Byte: 104})
+	if !ok || ao.Name != "name" {
+		t.Fatalf("expected local to be overridden, given %#v", ao)
+	}
+}
+
+func TestMergeOverrides_providerAndTerraform(t *testing.T) {
+	files := ModFiles{
+		"main.tf": parseTestFile(t, "main.tf", `terraform {
+  required_version = ">= 1.6"
+}
+
+provider "aws" {
+  region = "eu-west-1"
+}
+
+provider "aws" {
+  alias  = "east"
+  region = "us-east-1"
+}
+`),
+		"override.tf": parseTestFile(t, "override.tf", `terraform {
+  required_version = ">= 1.8"
+}
+
+provider "aws" {
+  alias  = "east"
+  region = "us-east-2"
+}
+
+provider "aws" {
+  alias  = "west"
+  region = "us-west-1"
+}
+`),
+	}
+
+	overrides := MergeOverrides(files)
+
+	if len(overrides.Blocks) != 3 {
+		t.Fatalf("expected 3 block overrides, %d given", len(overrides.Blocks))
+	}
+
+	// provider "aws" { alias = "east" }
+	blocks := overrides.OverridesOfBlock(hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 9, Column: 1, Byte: 88},
+		End:      hcl.Pos{Line: 12, Column: 2, Byte: 147},
+	})
+	if len(blocks) != 1 || blocks[0].DefRange.Start.Line != 5 {
+		t.Fatalf("expected aliased provider to be overridden by provider of the same alias, given %#v", blocks)
+	}
+
+	// region = "us-east-1"
+	ao, ok := overrides.EffectiveAttribute("main.tf", hcl.Pos{Line: 11, Column: 5, Byte: 129})
+	if !ok || ao.Range.Start.Line != 7 {
+		t.Fatalf("expected region of aliased provider to be overridden, given %#v", ao)
+	}
+	// region = "eu-west-1"
+	_, ok = overrides.EffectiveAttribute("main.tf", hcl.Pos{Line: 6, Column: 5, Byte: 68})
+	if ok {
+		t.Fatal("expected region of default provider not to be overridden")
+	}
+	// required_version = ">= 1.6"
+	ao, ok = overrides.EffectiveAttribute("main.tf", hcl.Pos{Line: 2, Column: 5, Byte: 16})
+	if !ok || ao.Name != "required_version" {
+		t.Fatalf("expected required_version to be overridden, given %#v", ao)
+	}
+
+	diags := overrides.MissingBaseDiags()
+	if len(diags["override.tf"]) != 1 {
+		t.Fatalf("expected one diagnostic for override.tf, given %#v", diags)
+	}
+	expectedSummary := "Missing base provider block to override"
+	if diags["override.tf"][0].Summary != expectedSummary {
+		t.Fatalf("expected summary %q, given %q", expectedSummary, diags["override.tf"][0].Summary)
+	}
+}
+
+func TestMergeOverrides_locals(t *testing.T) {
+	files := ModFiles{
+		"main.tf": parseTestFile(t, "main.tf", `locals {
+  name = "web"
+  env  = "prod"
+}
+`),
+		"override.tf": parseTestFile(t, "override.tf", `locals {
+  env    = "dev"
+  region = "eu"
+}
+`),
+	}
+
+	overrides := MergeOverrides(files)
+
+	if len(overrides.Blocks) != 2 {
+		t.Fatalf("expected 2 local overrides, %d given", len(overrides.Blocks))
+	}
+	for _, bo := range overrides.Blocks {
+		if bo.Type != "local" {
+			t.Fatalf("expected local override, given %q", bo.Type)
+		}
+	}
+
+	// env = "prod"
+	blocks := overrides.OverridesOfBlock(hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 3, Column: 3, Byte: 26},
+		End:      hcl.Pos{Line: 3, Column: 16, Byte: 39},
+	})
+	if len(blocks) != 1 || blocks[0].Address() != "local.env" {
+		t.Fatalf("expected local.env to be overridden, given %#v", blocks)
+	}
+
+	diags := overrides.MissingBaseDiags()
+	if len(diags["override.tf"]) != 1 {
+		t.Fatalf("expected one diagnostic for override.tf, given %#v", diags)
+	}
+	expectedSummary := "Missing base local value definition to override"
+	if diags["override.tf"][0].Summary != expectedSummary {
+		t.Fatalf("expected summary %q, given %q", expectedSummary, diags["override.tf"][0].Summary)
+	}
+}
+
+func parseTestFile(t *testing.T, filename, src string) *hcl.File {
+	f, diags := hclsyntax.ParseConfig([]byte(src), filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	return f
+}
@@ -0,0 +1,43 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// RequiredProviderRanges returns ranges of the entries of required_providers
+// blocks in the given files, keyed by the local name of the provider
+func RequiredProviderRanges(files ModFiles) map[string]hcl.Range {
+	ranges := make(map[string]hcl.Range)
+
+	for _, file := range files.Unshadowed() {
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			// JSON files are not supported yet
+			continue
+		}
+
+		for _, tfBlock := range body.Blocks {
+			if tfBlock.Type != "terraform" {
+				continue
+			}
+			for _, rpBlock := range tfBlock.Body.Blocks {
+				if rpBlock.Type != "required_providers" {
+					continue
+				}
+				for name, attr := range rpBlock.Body.Attributes {
+					if _, ok := ranges[name]; !ok {
+						ranges[name] = attr.SrcRange
+					}
+				}
+			}
+		}
+	}
+
+	return ranges
+}
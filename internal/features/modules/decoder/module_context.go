@@ -32,13 +32,20 @@ func modulePathContext(mod *state.ModuleRecord, stateReader CombinedReader) (*de
 		Validators:       moduleValidators,
 	}
 
+	// Files shadowed by .tofu files are ignored by OpenTofu,
+	// so they do not contribute any references to the module
+	isShadowed := func(filename string) bool {
+		return mod.ParsedModuleFiles.IsShadowed(ast.ModFilename(filename))
+	}
+
 	for _, origin := range mod.RefOrigins {
-		if ast.IsModuleFilename(origin.OriginRange().Filename) {
+		filename := origin.OriginRange().Filename
+		if ast.IsModuleFilename(filename) && !isShadowed(filename) {
 			pathCtx.ReferenceOrigins = append(pathCtx.ReferenceOrigins, origin)
 		}
 	}
 	for _, target := range mod.RefTargets {
-		if target.RangePtr != nil && ast.IsModuleFilename(target.RangePtr.Filename) {
+		if target.RangePtr != nil && ast.IsModuleFilename(target.RangePtr.Filename) && !isShadowed(target.RangePtr.Filename) {
 			pathCtx.ReferenceTargets = append(pathCtx.ReferenceTargets, target)
 		} else if target.RangePtr == nil {
 			pathCtx.ReferenceTargets = append(pathCtx.ReferenceTargets, target)
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/hcl-lang/schemacontext"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
 )
 
 type MissingRequiredAttribute struct{}
@@ -32,6 +33,11 @@ func (mra MissingRequiredAttribute) Visit(ctx context.Context, node hclsyntax.No
 		if nodeType.Type == "provider" && (nestingOk && nestingLvl == 0) {
 			ctx = WithUnknownRequiredAttributes(ctx)
 		}
+		// Blocks in override files are merged into the primary blocks,
+		// so they only need to declare the attributes being overridden
+		if ast.ModFilename(nodeType.Range().Filename).IsOverride() && (nestingOk && nestingLvl == 0) {
+			ctx = WithUnknownRequiredAttributes(ctx)
+		}
 	case *hclsyntax.Body:
 		if nodeSchema == nil {
 			return ctx, diags
@@ -12,8 +12,6 @@ import (
 	"path/filepath"
 
 	"github.com/hashicorp/go-multierror"
-	tfmod "github.com/opentofu/opentofu-schema/module"
-	tfaddr "github.com/opentofu/registry-address"
 	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
@@ -76,9 +74,36 @@ func (f *ModulesFeature) didChange(ctx context.Context, dir document.DirHandle)
 		return job.IDs{}, nil
 	}
 
+	// Diagnostics produced by tofu validate and tofu plan reflect the
+	// content as it was when the command ran, so they go stale on any change.
+	err := f.clearCLIDiagnostics(dir.Path())
+	if err != nil {
+		f.logger.Printf("failed to clear tofu validate and plan diagnostics for %q: %s", dir.Path(), err)
+	}
+
 	return f.decodeModule(ctx, dir, true, true)
 }
 
+func (f *ModulesFeature) clearCLIDiagnostics(modPath string) error {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
+	for _, source := range []globalAst.DiagnosticSource{globalAst.TofuValidateSource, globalAst.TofuPlanSource} {
+		if mod.ModuleDiagnostics[source].Count() == 0 {
+			continue
+		}
+
+		err = f.Store.UpdateModuleDiagnostics(modPath, source, ast.ModDiags{})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (f *ModulesFeature) didChangeWatched(ctx context.Context, rawPath string, changeType protocol.FileChangeType, isDir bool) (job.IDs, error) {
 	ids := make(job.IDs, 0)
 
@@ -188,27 +213,8 @@ func (f *ModulesFeature) decodeDeclaredModuleCalls(ctx context.Context, dir docu
 	var errs *multierror.Error
 
 	for _, mc := range declared {
-		var mcPath string
-		switch source := mc.SourceAddr.(type) {
-		// For local module sources, we can construct the path directly from the configuration
-		case tfmod.LocalSourceAddr:
-			mcPath = filepath.Join(dir.Path(), filepath.FromSlash(source.String()))
-		// For registry modules, we need to find the local installation path (if installed)
-		case tfaddr.Module:
-			installedDir, ok := f.rootFeature.InstalledModulePath(dir.Path(), source.String())
-			if !ok {
-				continue
-			}
-			mcPath = filepath.Join(dir.Path(), filepath.FromSlash(installedDir))
-		// For other remote modules, we need to find the local installation path (if installed)
-		case tfmod.RemoteSourceAddr:
-			installedDir, ok := f.rootFeature.InstalledModulePath(dir.Path(), source.String())
-			if !ok {
-				continue
-			}
-			mcPath = filepath.Join(dir.Path(), filepath.FromSlash(installedDir))
-		default:
-			// Unknown source address, we can't resolve the path
+		mcPath, ok := f.moduleCallPath(dir.Path(), mc)
+		if !ok {
 			continue
 		}
 
@@ -0,0 +1,154 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-ls/internal/document"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/datadir"
+	"github.com/opentofu/tofu-ls/internal/tofu/module"
+)
+
+// LoadPlan reads the plan at planPath, which is either a saved plan
+// file or the output of tofu show -json, and records planned changes
+// of all resource instances for the root module at modPath,
+// replacing any previously loaded plan.
+//
+// Changes of resources in child modules are associated with the
+// directories the modules were installed to by tofu init.
+//
+// Saved plan files are read using Tofu CLI. Relative paths
+// are resolved relative to the root module.
+func LoadPlan(ctx context.Context, plannedChanges *globalState.PlannedChangeStore, modPath, planPath string) error {
+	if !filepath.IsAbs(planPath) {
+		planPath = filepath.Join(modPath, planPath)
+	}
+
+	src, err := os.ReadFile(planPath)
+	if err != nil {
+		return err
+	}
+
+	var plan *tfjson.Plan
+	if bytes.HasPrefix(bytes.TrimSpace(src), []byte("{")) {
+		plan = &tfjson.Plan{}
+		err = json.Unmarshal(src, plan)
+		if err != nil {
+			return fmt.Errorf("failed to parse plan %q: %w", planPath, err)
+		}
+	} else {
+		tfExec, err := module.TofuExecutorForModule(ctx, modPath)
+		if err != nil {
+			return err
+		}
+		plan, err = tfExec.ShowPlanFile(ctx, planPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	modDirs, err := installedModuleDirs(modPath)
+	if err != nil {
+		return err
+	}
+
+	dir := document.DirHandleFromPath(modPath)
+	changes := make([]*globalState.PlannedChange, 0, len(plan.ResourceChanges))
+	for _, rc := range plan.ResourceChanges {
+		// Deposed objects are not represented in the configuration
+		if rc.Change == nil || rc.DeposedKey != "" {
+			continue
+		}
+		modDir := dir
+		if rc.ModuleAddress != "" {
+			modDir = modDirs[moduleKey(rc.ModuleAddress)]
+		}
+		changes = append(changes, &globalState.PlannedChange{
+			Dir:           dir,
+			Address:       rc.Address,
+			ModuleAddress: rc.ModuleAddress,
+			ModuleDir:     modDir,
+			Mode:          rc.Mode,
+			Type:          rc.Type,
+			Name:          rc.Name,
+			Index:         rc.Index,
+			Change:        rc.Change,
+		})
+	}
+
+	return plannedChanges.ReplacePlannedChanges(dir, changes)
+}
+
+// installedModuleDirs returns directories of all modules installed
+// for the root module at modPath, keyed by their key in the module
+// manifest, e.g. "network.subnets" for module.network.module.subnets
+func installedModuleDirs(modPath string) (map[string]document.DirHandle, error) {
+	dirs := make(map[string]document.DirHandle, 0)
+
+	manifestPath := filepath.Join(modPath, datadir.DataDirName, "modules", "modules.json")
+	mm, err := datadir.ParseModuleManifestFromFile(manifestPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// No modules were installed
+			return dirs, nil
+		}
+		return nil, fmt.Errorf("failed to read module manifest %q: %w", manifestPath, err)
+	}
+
+	for _, record := range mm.Records {
+		if record.IsRoot() {
+			continue
+		}
+		dirs[record.Key] = document.DirHandleFromPath(filepath.Join(modPath, record.Dir))
+	}
+
+	return dirs, nil
+}
+
+// moduleKey turns the address of a module instance into the key
+// of the module in the module manifest, which does not distinguish
+// instances, e.g. module.network["a"].module.subnets becomes
+// network.subnets
+func moduleKey(address string) string {
+	traversal, diags := hclsyntax.ParseTraversalAbs([]byte(address), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return ""
+	}
+
+	names := make([]string, 0)
+	for i := 0; i+1 < len(traversal); i++ {
+		var name string
+		switch step := traversal[i].(type) {
+		case hcl.TraverseRoot:
+			name = step.Name
+		case hcl.TraverseAttr:
+			name = step.Name
+		}
+		if name != "module" {
+			// skip instance keys
+			continue
+		}
+		if next, ok := traversal[i+1].(hcl.TraverseAttr); ok {
+			names = append(names, next.Name)
+			i++
+		}
+	}
+
+	return strings.Join(names, ".")
+}
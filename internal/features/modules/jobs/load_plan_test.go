@@ -0,0 +1,169 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-ls/internal/document"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	exec_mock "github.com/opentofu/tofu-ls/internal/tofu/exec/mock"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoadPlan_json(t *testing.T) {
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	modPath, err := filepath.Abs(filepath.Join("testdata", "plan-module"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = LoadPlan(context.Background(), gs.PlannedChanges, modPath, "plan.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := gs.PlannedChanges.PlannedChanges(document.DirHandleFromPath(modPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addresses := make([]string, 0)
+	for _, pc := range changes {
+		addresses = append(addresses, pc.Address)
+	}
+	// deposed objects are skipped
+	expectedAddresses := []string{
+		"module.app[\"blue\"].random_id.suffix",
+		"module.db.random_password.main",
+		"random_id.server[0]",
+		"random_id.server[1]",
+		"random_pet.name",
+	}
+	if diff := cmp.Diff(expectedAddresses, addresses); diff != "" {
+		t.Fatalf("unexpected planned changes: %s", diff)
+	}
+
+	pc, err := gs.PlannedChanges.PlannedChange(document.DirHandleFromPath(modPath), "random_pet.name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pc.Change.Actions.DestroyBeforeCreate() {
+		t.Fatalf("expected replacement, given %q", pc.Change.Actions)
+	}
+	if pc.Mode != tfjson.ManagedResourceMode || pc.Type != "random_pet" || pc.Name != "name" {
+		t.Fatalf("unexpected resource: %#v", pc)
+	}
+
+	// changes of child modules are associated with their installed directories
+	expectedModuleAddresses := map[string][]string{
+		modPath:                                  {"random_id.server[0]", "random_id.server[1]", "random_pet.name"},
+		filepath.Join(modPath, "db"):             {"module.db.random_password.main"},
+		filepath.Join(modPath, "modules", "app"): {"module.app[\"blue\"].random_id.suffix"},
+	}
+	for path, expectedAddresses := range expectedModuleAddresses {
+		changes, err := gs.PlannedChanges.ModulePlannedChanges(document.DirHandleFromPath(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		addresses := make([]string, 0)
+		for _, pc := range changes {
+			addresses = append(addresses, pc.Address)
+		}
+		if diff := cmp.Diff(expectedAddresses, addresses); diff != "" {
+			t.Fatalf("unexpected planned changes of %q: %s", path, diff)
+		}
+	}
+}
+
+func TestModuleKey(t *testing.T) {
+	testCases := map[string]string{
+		"module.db":                       "db",
+		"module.network.module.subnets":   "network.subnets",
+		`module.app["blue"]`:              "app",
+		`module.app["a.b"].module.web[0]`: "app.web",
+		"module.module":                   "module",
+		"module.module.module.nested":     "module.nested",
+	}
+
+	for address, expectedKey := range testCases {
+		if key := moduleKey(address); key != expectedKey {
+			t.Errorf("%s: expected key %q, given %q", address, expectedKey, key)
+		}
+	}
+}
+
+func TestLoadPlan_planFile(t *testing.T) {
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	modPath := t.TempDir()
+	planPath := filepath.Join(modPath, "tfplan")
+	// saved plans are zip archives
+	err = os.WriteFile(planPath, []byte("PK\x03\x04"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := exec.WithExecutorOpts(context.Background(), &exec.ExecutorOpts{ExecPath: "tofu"})
+	ctx = exec.WithExecutorFactory(ctx, func(wd, execPath string) (exec.TofuExecutor, error) {
+		me := &exec_mock.Executor{}
+		me.On("ShowPlanFile", mock.Anything, planPath).Return(&tfjson.Plan{
+			FormatVersion: "1.2",
+			ResourceChanges: []*tfjson.ResourceChange{
+				{
+					Address: "random_pet.name",
+					Mode:    tfjson.ManagedResourceMode,
+					Type:    "random_pet",
+					Name:    "name",
+					Change: &tfjson.Change{
+						Actions: tfjson.Actions{tfjson.ActionDelete},
+					},
+				},
+			},
+		}, nil)
+		return me, nil
+	})
+
+	err = LoadPlan(ctx, gs.PlannedChanges, modPath, planPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pc, err := gs.PlannedChanges.PlannedChange(document.DirHandleFromPath(modPath), "random_pet.name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pc.Change.Actions.Delete() {
+		t.Fatalf("expected deletion, given %q", pc.Change.Actions)
+	}
+
+	// loading another plan replaces previously loaded changes
+	err = os.WriteFile(filepath.Join(modPath, "plan.json"), []byte(`{"format_version": "1.2"}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadPlan(ctx, gs.PlannedChanges, modPath, "plan.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := gs.PlannedChanges.PlannedChanges(document.DirHandleFromPath(modPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("expected no planned changes, given %d", len(changes))
+	}
+}
@@ -40,7 +40,7 @@ func LoadModuleMetadata(ctx context.Context, modStore *state.ModuleStore, modPat
 	}
 
 	var mErr error
-	meta, diags := earlydecoder.LoadModule(mod.Path(), mod.ParsedModuleFiles.AsMap())
+	meta, diags := earlydecoder.LoadModule(mod.Path(), mod.ParsedModuleFiles.Unshadowed().AsMap())
 	if len(diags) > 0 {
 		mErr = diags
 	}
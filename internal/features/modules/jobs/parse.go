@@ -7,8 +7,10 @@ package jobs
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
 
+	"github.com/hashicorp/hcl/v2"
 	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
@@ -23,6 +25,9 @@ import (
 
 // ParseModuleConfiguration parses the module configuration,
 // i.e. turns bytes of `*.tf` files into AST ([*hcl.File]).
+//
+// Files shadowed by a same-named `*.tofu` file are parsed too,
+// but flagged with an informational diagnostic, as OpenTofu ignores them.
 func ParseModuleConfiguration(ctx context.Context, fs ReadOnlyFS, modStore *state.ModuleStore, modPath string) error {
 	mod, err := modStore.ModuleRecordByPath(modPath)
 	if err != nil {
@@ -88,10 +93,44 @@ func ParseModuleConfiguration(ctx context.Context, fs ReadOnlyFS, modStore *stat
 		return sErr
 	}
 
-	sErr = modStore.UpdateModuleDiagnostics(modPath, globalAst.HCLParsingSource, diags)
+	sErr = modStore.UpdateModuleDiagnostics(modPath, globalAst.HCLParsingSource, withShadowedFileDiags(files, diags))
 	if sErr != nil {
 		return sErr
 	}
 
 	return err
 }
+
+const shadowedFileSummary = "Shadowed file"
+
+// withShadowedFileDiags returns the diagnostics with an informational
+// diagnostic added to each file which is shadowed by a .tofu file
+// (and hence ignored by OpenTofu), replacing any previous ones
+func withShadowedFileDiags(files ast.ModFiles, diags ast.ModDiags) ast.ModDiags {
+	newDiags := make(ast.ModDiags, len(diags))
+	for name, fileDiags := range diags {
+		newFileDiags := make(hcl.Diagnostics, 0, len(fileDiags))
+		for _, diag := range fileDiags {
+			if diag.Summary == shadowedFileSummary && globalAst.IsInformational(diag) {
+				continue
+			}
+			newFileDiags = append(newFileDiags, diag)
+		}
+
+		if shadowingName, ok := files.ShadowedBy(name); ok {
+			newFileDiags = append(newFileDiags, globalAst.NewInformationalDiagnostic(
+				shadowedFileSummary,
+				fmt.Sprintf("%s is ignored by OpenTofu, because %s exists in the same directory", name, shadowingName),
+				&hcl.Range{
+					Filename: name.String(),
+					Start:    hcl.InitialPos,
+					End:      hcl.InitialPos,
+				},
+			))
+		}
+
+		newDiags[name] = newFileDiags
+	}
+
+	return newDiags
+}
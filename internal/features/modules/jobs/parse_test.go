@@ -174,3 +174,83 @@ func TestParseModuleConfiguration_ignore_tfvars(t *testing.T) {
 		t.Fatal("there should be no diags for tfvars files right now")
 	}
 }
+
+func TestParseModuleConfiguration_shadowedFiles(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFs := filesystem.NewFilesystem(gs.DocumentStore)
+
+	modPath := filepath.Join(testData, "shadowed-files-module")
+
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseModuleConfiguration(ctx, testFs, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reparse the shadowed file, as if it was changed
+	ctx = job.WithIgnoreState(ctx, true)
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{
+		Method:     "textDocument/didChange",
+		LanguageID: ilsp.OpenTofu.String(),
+		URI:        uri.FromPath(filepath.Join(modPath, "main.tofu")),
+	})
+	err = ParseModuleConfiguration(ctx, testFs, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the shadowed file is still parsed, e.g. for folding ranges
+	if _, ok := mod.ParsedModuleFiles["main.tf"]; !ok {
+		t.Fatal("expected main.tf to be parsed")
+	}
+
+	diags := mod.ModuleDiagnostics[ast.HCLParsingSource]
+	if len(diags["main.tf"]) != 1 {
+		t.Fatalf("expected exactly one diagnostic for main.tf, given %d: %#v", len(diags["main.tf"]), diags["main.tf"])
+	}
+	if !ast.IsInformational(diags["main.tf"][0]) {
+		t.Fatalf("expected informational diagnostic, given %#v", diags["main.tf"][0])
+	}
+	if len(diags["main.tofu"]) != 0 || len(diags["other.tf"]) != 0 {
+		t.Fatalf("expected no diagnostics for unshadowed files, given %#v", diags)
+	}
+
+	err = LoadModuleMetadata(ctx, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err = ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mod.Meta.Variables) != 2 {
+		t.Fatalf("expected 2 variables, given %d", len(mod.Meta.Variables))
+	}
+	if mod.Meta.Variables["foo"].DefaultValue.AsString() != "tofu" {
+		t.Fatalf("expected variable foo to be declared in main.tofu, given default %#v", mod.Meta.Variables["foo"].DefaultValue)
+	}
+}
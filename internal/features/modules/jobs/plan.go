@@ -0,0 +1,185 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	"github.com/opentofu/tofu-exec/tfexec"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
+	"github.com/opentofu/tofu-ls/internal/features/modules/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/module"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// TofuPlan uses Tofu CLI to run a plan without refreshing
+// state or acquiring the state lock and turns the provided (JSON)
+// diagnostics into diagnostics associated with the code, catching
+// errors which only surface during planning.
+//
+// The plan runs in a scratch working directory mirroring the module,
+// where configuration files reflect the (possibly unsaved) content
+// of open documents, so nothing is written to the module itself.
+func TofuPlan(ctx context.Context, fs ReadOnlyFS, modStore *state.ModuleStore, modPath string) error {
+	mod, err := modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid planning if it is already in progress or already finished
+	if mod.ModuleDiagnosticsState[globalAst.TofuPlanSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(modPath)}
+	}
+
+	err = modStore.SetModuleDiagnosticsState(modPath, globalAst.TofuPlanSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	workDir, cleanup, err := scratchWorkDir(fs, modPath, parentDepth(mod.Meta.ModuleCalls))
+	if err != nil {
+		modStore.SetModuleDiagnosticsState(modPath, globalAst.TofuPlanSource, op.OpStateUnknown)
+		return err
+	}
+	defer cleanup()
+
+	// The executable is chosen for the module itself,
+	// e.g. to satisfy its required_version
+	tfExec, err := module.TofuExecutorForWorkDir(ctx, workDir, modPath)
+	if err != nil {
+		modStore.SetModuleDiagnosticsState(modPath, globalAst.TofuPlanSource, op.OpStateUnknown)
+		return err
+	}
+
+	jsonDiags, err := tfExec.Plan(ctx, tfexec.Refresh(false), tfexec.Lock(false))
+	if err != nil {
+		modStore.SetModuleDiagnosticsState(modPath, globalAst.TofuPlanSource, op.OpStateUnknown)
+		return err
+	}
+	planDiags := diagnostics.HCLDiagsFromJSON(jsonDiags)
+
+	return modStore.UpdateModuleDiagnostics(modPath, globalAst.TofuPlanSource, ast.ModDiagsFromMap(planDiags))
+}
+
+// scratchWorkDir creates a temporary directory mirroring the module.
+// Configuration and variable files are written from fs, so they reflect
+// open documents, while any other file or directory (such as the data
+// directory or the lock file) is linked to the original.
+//
+// Local modules are resolved relative to the working directory, so
+// the given number of parent directories are mirrored as well.
+func scratchWorkDir(fs ReadOnlyFS, modPath string, depth int) (string, func(), error) {
+	rootDir, err := os.MkdirTemp("", "tofu-ls-plan-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		os.RemoveAll(rootDir)
+	}
+
+	// Path segments from the outermost mirrored parent down to the module
+	segments := make([]string, 0, depth)
+	srcDir := modPath
+	for i := 0; i < depth && filepath.Dir(srcDir) != srcDir; i++ {
+		segments = append([]string{filepath.Base(srcDir)}, segments...)
+		srcDir = filepath.Dir(srcDir)
+	}
+
+	dstDir := rootDir
+	for _, segment := range segments {
+		err = linkEntries(srcDir, dstDir, segment)
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+		srcDir = filepath.Join(srcDir, segment)
+		dstDir = filepath.Join(dstDir, segment)
+		err = os.Mkdir(dstDir, 0o755)
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+
+	entries, err := fs.ReadDir(modPath)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !isPlanInputFile(name) {
+			err = os.Symlink(filepath.Join(modPath, name), filepath.Join(dstDir, name))
+		} else {
+			var src []byte
+			src, err = fs.ReadFile(filepath.Join(modPath, name))
+			if err == nil {
+				err = os.WriteFile(filepath.Join(dstDir, name), src, 0o644)
+			}
+		}
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+
+	return dstDir, cleanup, nil
+}
+
+// linkEntries links all entries of srcDir, except the one named skip, into dstDir
+func linkEntries(srcDir, dstDir, skip string) error {
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == skip {
+			continue
+		}
+		err = os.Symlink(filepath.Join(srcDir, entry.Name()), filepath.Join(dstDir, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isPlanInputFile(name string) bool {
+	return ast.IsModuleFilename(name) ||
+		strings.HasSuffix(name, ".tfvars") ||
+		strings.HasSuffix(name, ".tfvars.json")
+}
+
+// parentDepth returns the maximum number of parent
+// directories local module calls refer to
+func parentDepth(calls map[string]tfmod.DeclaredModuleCall) int {
+	depth := 0
+	for _, call := range calls {
+		localAddr, ok := call.SourceAddr.(tfmod.LocalSourceAddr)
+		if !ok {
+			continue
+		}
+		d := 0
+		for _, segment := range strings.Split(filepath.ToSlash(filepath.Clean(string(localAddr))), "/") {
+			if segment != ".." {
+				break
+			}
+			d++
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
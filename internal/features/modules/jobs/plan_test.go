@@ -0,0 +1,215 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/modules/state"
+	"github.com/opentofu/tofu-ls/internal/filesystem"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	exec_mock "github.com/opentofu/tofu-ls/internal/tofu/exec/mock"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTofuPlan(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks requires elevated privileges on Windows")
+	}
+
+	rootDir := t.TempDir()
+	modPath := filepath.Join(rootDir, "app")
+	for _, dir := range []string{filepath.Join(modPath, ".terraform"), filepath.Join(rootDir, "shared")} {
+		err := os.MkdirAll(dir, 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := os.WriteFile(filepath.Join(modPath, "main.tf"), []byte(`# saved content`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(rootDir, "shared", "main.tf"), []byte(`variable "name" {}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The plan reflects unsaved content of open documents
+	unsavedConfig := `module "shared" {
+  source = "../shared"
+  name   = { for k in ["a", "a"] : k => k }
+}
+`
+	err = gs.DocumentStore.OpenDocument(document.HandleFromPath(filepath.Join(modPath, "main.tf")), ilsp.OpenTofu.String(), 0, []byte(unsavedConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := filesystem.NewFilesystem(gs.DocumentStore)
+	ctx := lsctx.WithDocumentContext(context.Background(), lsctx.Document{})
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadModuleMetadata(ctx, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var workDir string
+	ctx = exec.WithExecutorOpts(ctx, &exec.ExecutorOpts{ExecPath: "tofu"})
+	ctx = exec.WithExecutorFactory(ctx, func(wd, execPath string) (exec.TofuExecutor, error) {
+		workDir = wd
+
+		src, err := os.ReadFile(filepath.Join(wd, "main.tf"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(src) != unsavedConfig {
+			t.Fatalf("expected unsaved content in scratch directory, given: %q", src)
+		}
+		for _, path := range []string{".terraform", filepath.Join("..", "shared", "main.tf")} {
+			_, err = os.Stat(filepath.Join(wd, path))
+			if err != nil {
+				t.Fatalf("expected %s to be mirrored: %s", path, err)
+			}
+		}
+
+		me := &exec_mock.Executor{}
+		me.On("Plan", mock.Anything, mock.Anything, mock.Anything).Return([]tfjson.Diagnostic{
+			{
+				Severity: tfjson.DiagnosticSeverityError,
+				Summary:  "Duplicate object key",
+				Detail:   `Two different items produced the key "a" in this 'for' expression.`,
+				Range: &tfjson.Range{
+					Filename: "main.tf",
+					Start:    tfjson.Pos{Line: 3, Column: 12, Byte: 51},
+					End:      tfjson.Pos{Line: 3, Column: 43, Byte: 82},
+				},
+			},
+		}, nil)
+		return me, nil
+	})
+
+	err = TofuPlan(ctx, fs, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if workDir == "" || workDir == modPath {
+		t.Fatalf("expected plan to run in a scratch directory, given %q", workDir)
+	}
+	if _, err := os.Stat(workDir); !os.IsNotExist(err) {
+		t.Fatalf("expected scratch directory to be removed: %s", err)
+	}
+
+	mod, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	planDiags := mod.ModuleDiagnostics[ast.TofuPlanSource]
+	if planDiags.Count() != 1 {
+		t.Fatalf("expected 1 tofu plan diagnostic, given: %#v", planDiags)
+	}
+	diag := planDiags["main.tf"][0]
+	if diag.Summary != "Duplicate object key" || diag.Subject.Start.Line != 3 {
+		t.Fatalf("unexpected diagnostic: %#v", diag)
+	}
+}
+
+func TestTofuPlan_execPathForModule(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks requires elevated privileges on Windows")
+	}
+
+	modPath := t.TempDir()
+	err := os.WriteFile(filepath.Join(modPath, "main.tf"), []byte(`terraform {
+  required_version = "~> 1.8.0"
+}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := filesystem.NewFilesystem(gs.DocumentStore)
+	ctx := lsctx.WithDocumentContext(context.Background(), lsctx.Document{})
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadModuleMetadata(ctx, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The executable is resolved for the module, not the scratch directory
+	var execPathModPath, workDir, usedExecPath string
+	ctx = exec.WithExecutorOpts(ctx, &exec.ExecutorOpts{
+		ExecPathFunc: func(ctx context.Context, modPath string) (string, error) {
+			execPathModPath = modPath
+			return "/cache/1.8.5/tofu", nil
+		},
+	})
+	ctx = exec.WithExecutorFactory(ctx, func(wd, execPath string) (exec.TofuExecutor, error) {
+		workDir = wd
+		usedExecPath = execPath
+
+		me := &exec_mock.Executor{}
+		me.On("Plan", mock.Anything, mock.Anything, mock.Anything).Return([]tfjson.Diagnostic{}, nil)
+		return me, nil
+	})
+
+	err = TofuPlan(ctx, fs, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if execPathModPath != modPath {
+		t.Fatalf("expected exec path to be resolved for %q, given %q", modPath, execPathModPath)
+	}
+	if usedExecPath != "/cache/1.8.5/tofu" {
+		t.Fatalf("expected resolved exec path to be used, given %q", usedExecPath)
+	}
+	if workDir == "" || workDir == modPath {
+		t.Fatalf("expected plan to run in a scratch directory, given %q", workDir)
+	}
+}
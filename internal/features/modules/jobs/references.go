@@ -7,15 +7,19 @@ package jobs
 
 import (
 	"context"
+	"slices"
 
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
 	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
 	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
 	fdecoder "github.com/opentofu/tofu-ls/internal/features/modules/decoder"
 	"github.com/opentofu/tofu-ls/internal/features/modules/state"
 	"github.com/opentofu/tofu-ls/internal/job"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/rename"
 	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
 )
 
@@ -59,10 +63,15 @@ func DecodeReferenceTargets(ctx context.Context, modStore *state.ModuleStore, ro
 		return err
 	}
 	targets, rErr := pd.CollectReferenceTargets()
+	targets = mergeOverrideTargets(targets)
 
 	targets = append(targets, builtinReferences(modPath)...)
 
-	sErr := modStore.UpdateReferenceTargets(modPath, targets, rErr)
+	// Labels changed since the last decoding are compared
+	// to the previous targets, to offer recording them as moves
+	moves := rename.UpdateMoves(mod.Moves, mod.ParsedModuleFiles.Unshadowed().AsMap(), mod.RefTargets, targets)
+
+	sErr := modStore.UpdateReferenceTargets(modPath, targets, moves, rErr)
 	if sErr != nil {
 		return sErr
 	}
@@ -70,6 +79,33 @@ func DecodeReferenceTargets(ctx context.Context, modStore *state.ModuleStore, ro
 	return rErr
 }
 
+// mergeOverrideTargets removes targets declared in override files
+// which are already declared in primary files, as OpenTofu merges
+// such blocks into the primary ones instead of declaring them again.
+// Overriding blocks remain reachable via [ast.MergeOverrides].
+func mergeOverrideTargets(targets reference.Targets) reference.Targets {
+	isOverride := func(target reference.Target) bool {
+		return target.RangePtr != nil && ast.ModFilename(target.RangePtr.Filename).IsOverride()
+	}
+
+	primaryAddrs := make([]lang.Address, 0)
+	for _, target := range targets {
+		if !isOverride(target) && len(target.Addr) > 0 {
+			primaryAddrs = append(primaryAddrs, target.Addr)
+		}
+	}
+
+	merged := make(reference.Targets, 0, len(targets))
+	for _, target := range targets {
+		if isOverride(target) && len(target.Addr) > 0 && slices.ContainsFunc(primaryAddrs, target.Addr.Equals) {
+			continue
+		}
+		merged = append(merged, target)
+	}
+
+	return merged
+}
+
 // DecodeReferenceOrigins collects reference origins,
 // using previously parsed AST (via [ParseModuleConfiguration]),
 // core schema of appropriate version (as obtained via [GetTofuVersion])
@@ -0,0 +1,50 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+)
+
+func TestMergeOverrideTargets(t *testing.T) {
+	target := func(filename, root, attr string) reference.Target {
+		return reference.Target{
+			Addr: lang.Address{
+				lang.RootStep{Name: root},
+				lang.AttrStep{Name: attr},
+			},
+			RangePtr: &hcl.Range{Filename: filename},
+		}
+	}
+
+	targets := reference.Targets{
+		target("main.tf", "var", "region"),
+		target("override.tf", "var", "region"),
+		target("region_override.tf", "var", "region"),
+		target("override.tf", "var", "zone"),
+		target("main.tf", "local", "name"),
+		target("override.tf", "local", "name"),
+		target("override.tf", "local", "env"),
+	}
+
+	expectedTargets := reference.Targets{
+		target("main.tf", "var", "region"),
+		target("override.tf", "var", "zone"),
+		target("main.tf", "local", "name"),
+		target("override.tf", "local", "env"),
+	}
+
+	merged := mergeOverrideTargets(targets)
+	if diff := cmp.Diff(expectedTargets, merged, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected targets: %s", diff)
+	}
+}
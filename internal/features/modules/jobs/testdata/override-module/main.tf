@@ -0,0 +1,8 @@
+variable "region" {
+  default = "eu-west-1"
+}
+
+module "network" {
+  source  = "./network"
+  version = "1.0.0"
+}
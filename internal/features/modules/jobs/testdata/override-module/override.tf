@@ -0,0 +1,7 @@
+module "network" {
+  version = "2.0.0"
+}
+
+variable "zone" {
+  default = "a"
+}
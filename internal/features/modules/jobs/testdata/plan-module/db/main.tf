@@ -0,0 +1,3 @@
+resource "random_password" "main" {
+  length = 24
+}
@@ -0,0 +1,18 @@
+resource "random_pet" "name" {
+  length    = 3
+  separator = "_"
+}
+
+resource "random_id" "server" {
+  count       = 2
+  byte_length = 8
+}
+
+module "db" {
+  source = "./db"
+}
+
+module "app" {
+  source   = "./modules/app"
+  for_each = toset(["blue", "green"])
+}
@@ -0,0 +1,3 @@
+variable "foo" {
+  default = "tf"
+}
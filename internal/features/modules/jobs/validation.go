@@ -68,17 +68,21 @@ func SchemaModuleValidation(ctx context.Context, modStore *state.ModuleStore, ro
 	rpcContext := lsctx.DocumentContext(ctx)
 	if rpcContext.Method == "textDocument/didChange" && ilsp.IsValidConfigLanguage(rpcContext.LanguageID) {
 		filename := path.Base(rpcContext.URI)
-		// We only revalidate a single file that changed
+		// We only revalidate a single file that changed,
+		// unless it is shadowed and hence ignored by OpenTofu
 		var fileDiags hcl.Diagnostics
-		fileDiags, rErr = moduleDecoder.ValidateFile(ctx, filename)
+		if !mod.ParsedModuleFiles.IsShadowed(ast.ModFilename(filename)) {
+			fileDiags, rErr = moduleDecoder.ValidateFile(ctx, filename)
+		}
 
 		modDiags, ok := mod.ModuleDiagnostics[globalAst.SchemaValidationSource]
 		if !ok {
 			modDiags = make(ast.ModDiags)
 		}
+		modDiags = modDiags.Copy()
 		modDiags[ast.ModFilename(filename)] = fileDiags
 
-		sErr := modStore.UpdateModuleDiagnostics(modPath, globalAst.SchemaValidationSource, modDiags)
+		sErr := modStore.UpdateModuleDiagnostics(modPath, globalAst.SchemaValidationSource, withOverrideDiags(mod.ParsedModuleFiles, modDiags))
 		if sErr != nil {
 			return sErr
 		}
@@ -86,8 +90,13 @@ func SchemaModuleValidation(ctx context.Context, modStore *state.ModuleStore, ro
 		// We validate the whole module, e.g. on open
 		var diags lang.DiagnosticsMap
 		diags, rErr = moduleDecoder.Validate(ctx)
+		for filename := range diags {
+			if mod.ParsedModuleFiles.IsShadowed(ast.ModFilename(filename)) {
+				delete(diags, filename)
+			}
+		}
 
-		sErr := modStore.UpdateModuleDiagnostics(modPath, globalAst.SchemaValidationSource, ast.ModDiagsFromMap(diags))
+		sErr := modStore.UpdateModuleDiagnostics(modPath, globalAst.SchemaValidationSource, withOverrideDiags(mod.ParsedModuleFiles, ast.ModDiagsFromMap(diags)))
 		if sErr != nil {
 			return sErr
 		}
@@ -96,6 +105,30 @@ func SchemaModuleValidation(ctx context.Context, modStore *state.ModuleStore, ro
 	return rErr
 }
 
+// withOverrideDiags returns the diagnostics with a diagnostic added
+// for each block of override files which has no primary block
+// to override, replacing any previous ones, as any file
+// of the module may have added or removed such a block
+func withOverrideDiags(files ast.ModFiles, diags ast.ModDiags) ast.ModDiags {
+	newDiags := make(ast.ModDiags, len(diags))
+	for name, fileDiags := range diags {
+		newFileDiags := make(hcl.Diagnostics, 0, len(fileDiags))
+		for _, diag := range fileDiags {
+			if ast.IsMissingBaseDiag(diag) {
+				continue
+			}
+			newFileDiags = append(newFileDiags, diag)
+		}
+		newDiags[name] = newFileDiags
+	}
+
+	for name, fileDiags := range ast.MergeOverrides(files).MissingBaseDiags() {
+		newDiags[name] = append(newDiags[name], fileDiags...)
+	}
+
+	return newDiags
+}
+
 // ReferenceValidation does validation based on (mis)matched
 // reference origins and targets, to flag up "orphaned" references.
 //
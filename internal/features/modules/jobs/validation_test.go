@@ -131,3 +131,61 @@ func TestSchemaModuleValidation_SingleFile(t *testing.T) {
 		t.Fatalf("expected %d diagnostics, %d given", expectedCount, diagsCount)
 	}
 }
+
+func TestSchemaModuleValidation_overrides(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	modPath := filepath.Join(testData, "override-module")
+
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := filesystem.NewFilesystem(gs.DocumentStore)
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{
+		Method:     "textDocument/didOpen",
+		LanguageID: ilsp.OpenTofu.String(),
+		URI:        "file:///test/override.tf",
+	})
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SchemaModuleValidation(ctx, ms, RootReaderMock{}, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The overriding module block is expected to lack the source
+	// attribute, but the variable has no primary block to override
+	diags := mod.ModuleDiagnostics[ast.SchemaValidationSource]
+	if diags.Count() != 1 {
+		t.Fatalf("expected 1 diagnostic, %d given: %#v", diags.Count(), diags)
+	}
+	overrideDiags := diags["override.tf"]
+	if len(overrideDiags) != 1 {
+		t.Fatalf("expected 1 diagnostic for override.tf, %d given", len(overrideDiags))
+	}
+	expectedSummary := "Missing base variable block to override"
+	if overrideDiags[0].Summary != expectedSummary {
+		t.Fatalf("expected summary %q, given %q", expectedSummary, overrideDiags[0].Summary)
+	}
+}
@@ -10,20 +10,27 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"path/filepath"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
 	tfmod "github.com/opentofu/opentofu-schema/module"
+	tfaddr "github.com/opentofu/registry-address"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
 	fdecoder "github.com/opentofu/tofu-ls/internal/features/modules/decoder"
 	"github.com/opentofu/tofu-ls/internal/features/modules/hooks"
 	"github.com/opentofu/tofu-ls/internal/features/modules/jobs"
 	"github.com/opentofu/tofu-ls/internal/features/modules/state"
+	"github.com/opentofu/tofu-ls/internal/job"
 	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
 	"github.com/opentofu/tofu-ls/internal/registry"
+	"github.com/opentofu/tofu-ls/internal/rename"
 	globalState "github.com/opentofu/tofu-ls/internal/state"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
 )
 
 // ModulesFeature groups everything related to modules. Its internal
@@ -145,6 +152,26 @@ func (f *ModulesFeature) ProviderRequirements(modPath string) (tfmod.ProviderReq
 	return mod.Meta.ProviderRequirements, nil
 }
 
+// RequiredProviderRanges returns ranges of the required_providers
+// entries declaring each provider required by the module
+func (f *ModulesFeature) RequiredProviderRanges(modPath string) (map[tfaddr.Provider]hcl.Range, error) {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := make(map[tfaddr.Provider]hcl.Range)
+	for localName, rng := range ast.RequiredProviderRanges(mod.ParsedModuleFiles) {
+		pAddr, ok := mod.Meta.ProviderReferences[tfmod.ProviderRef{LocalName: localName}]
+		if !ok {
+			continue
+		}
+		ranges[pAddr] = rng
+	}
+
+	return ranges, nil
+}
+
 func (f *ModulesFeature) CoreRequirements(modPath string) (version.Constraints, error) {
 	mod, err := f.Store.ModuleRecordByPath(modPath)
 	if err != nil {
@@ -154,6 +181,42 @@ func (f *ModulesFeature) CoreRequirements(modPath string) (version.Constraints,
 	return mod.Meta.CoreRequirements, nil
 }
 
+// ModuleBackend returns the backend declared in the terraform block
+// of the module or nil if the module does not declare any
+func (f *ModulesFeature) ModuleBackend(modPath string) (*tfmod.Backend, error) {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return mod.Meta.Backend, nil
+}
+
+// LocalModuleCallers returns paths of the indexed modules
+// declaring a local module call of the module in modPath
+func (f *ModulesFeature) LocalModuleCallers(modPath string) ([]string, error) {
+	mods, err := f.Store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	callers := make([]string, 0)
+	for _, mod := range mods {
+		for _, mc := range mod.Meta.ModuleCalls {
+			localAddr, ok := mc.SourceAddr.(tfmod.LocalSourceAddr)
+			if !ok {
+				continue
+			}
+			if filepath.Clean(filepath.Join(mod.Path(), localAddr.String())) == filepath.Clean(modPath) {
+				callers = append(callers, mod.Path())
+				break
+			}
+		}
+	}
+
+	return callers, nil
+}
+
 func (f *ModulesFeature) ModuleInputs(modPath string) (map[string]tfmod.Variable, error) {
 	mod, err := f.Store.ModuleRecordByPath(modPath)
 	if err != nil {
@@ -163,6 +226,101 @@ func (f *ModulesFeature) ModuleInputs(modPath string) (map[string]tfmod.Variable
 	return mod.Meta.Variables, nil
 }
 
+func (f *ModulesFeature) ModuleOutputs(modPath string) (map[string]tfmod.Output, error) {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return mod.Meta.Outputs, nil
+}
+
+// ModuleCallInputs returns the inputs of the module called
+// by the given name from the module at modPath
+func (f *ModulesFeature) ModuleCallInputs(modPath string, callName string) (map[string]tfmod.Variable, bool) {
+	declared, err := f.Store.DeclaredModuleCalls(modPath)
+	if err != nil {
+		return nil, false
+	}
+
+	mc, ok := declared[callName]
+	if !ok {
+		return nil, false
+	}
+
+	mcPath, ok := f.moduleCallPath(modPath, mc)
+	if !ok {
+		return nil, false
+	}
+
+	inputs, err := f.ModuleInputs(mcPath)
+	if err != nil {
+		return nil, false
+	}
+	return inputs, true
+}
+
+// moduleCallPath returns the path of the module called by the declared
+// module call, which is only known for local and installed modules
+func (f *ModulesFeature) moduleCallPath(modPath string, mc tfmod.DeclaredModuleCall) (string, bool) {
+	switch source := mc.SourceAddr.(type) {
+	// For local module sources, we can construct the path directly from the configuration
+	case tfmod.LocalSourceAddr:
+		return filepath.Join(modPath, filepath.FromSlash(source.String())), true
+	// For registry modules, we need to find the local installation path (if installed)
+	case tfaddr.Module:
+		installedDir, ok := f.rootFeature.InstalledModulePath(modPath, source.String())
+		if !ok {
+			return "", false
+		}
+		return filepath.Join(modPath, filepath.FromSlash(installedDir)), true
+	// For other remote modules, we need to find the local installation path (if installed)
+	case tfmod.RemoteSourceAddr:
+		installedDir, ok := f.rootFeature.InstalledModulePath(modPath, source.String())
+		if !ok {
+			return "", false
+		}
+		return filepath.Join(modPath, filepath.FromSlash(installedDir)), true
+	}
+
+	// Unknown source address, we can't resolve the path
+	return "", false
+}
+
+// ParsedFile returns the parsed file of the module, regardless
+// of whether it could be decoded against the schema
+func (f *ModulesFeature) ParsedFile(modPath string, filename string) (*hcl.File, bool) {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return nil, false
+	}
+
+	file, ok := mod.ParsedModuleFiles[ast.ModFilename(filename)]
+	return file, ok && file != nil
+}
+
+// ParsedFiles returns all parsed files which OpenTofu loads as part
+// of the module, i.e. excluding files shadowed by .tofu files
+func (f *ModulesFeature) ParsedFiles(modPath string) (map[string]*hcl.File, error) {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return mod.ParsedModuleFiles.Unshadowed().AsMap(), nil
+}
+
+// Moves returns managed resources and module calls relabeled in the module
+// which are not recorded in any moved block yet (see [rename.UpdateMoves])
+func (f *ModulesFeature) Moves(modPath string) ([]rename.Move, error) {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return mod.Moves, nil
+}
+
 func (f *ModulesFeature) AppendCompletionHooks(srvCtx context.Context, decoderContext decoder.DecoderContext) {
 	h := hooks.Hooks{
 		ModStore:       f.Store,
@@ -190,6 +348,105 @@ func (f *ModulesFeature) Diagnostics(path string) diagnostics.Diagnostics {
 	return diags
 }
 
+// IsShadowedFile returns true if the given file of the module is
+// ignored by OpenTofu, because a same-named .tofu file exists
+func (f *ModulesFeature) IsShadowedFile(modPath string, filename string) bool {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return false
+	}
+
+	return mod.ParsedModuleFiles.IsShadowed(ast.ModFilename(filename))
+}
+
+// Overrides returns the overrides of the module,
+// resulting from merging its override files into primary files
+func (f *ModulesFeature) Overrides(modPath string) (ast.Overrides, bool) {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return ast.Overrides{}, false
+	}
+
+	return ast.MergeOverrides(mod.ParsedModuleFiles), true
+}
+
+// OverrideHoverNote returns a note explaining where the effective value
+// of the attribute at the given position comes from, if it is overridden
+// by an override file or overrides an attribute of a primary file
+func (f *ModulesFeature) OverrideHoverNote(modPath string, filename string, pos hcl.Pos) (string, hcl.Range, bool) {
+	overrides, ok := f.Overrides(modPath)
+	if !ok {
+		return "", hcl.Range{}, false
+	}
+
+	if ao, ok := overrides.EffectiveAttribute(filename, pos); ok {
+		return fmt.Sprintf("The effective value of `%s` comes from the override file `%s`",
+			ao.Name, ao.Range.Filename), ao.BaseRange, true
+	}
+	if ao, ok := overrides.OverriddenAttribute(filename, pos); ok {
+		return fmt.Sprintf("Overrides the value of `%s` from `%s`",
+			ao.Name, ao.BaseRange.Filename), ao.Range, true
+	}
+
+	return "", hcl.Range{}, false
+}
+
+// PlannedChangeHoverNote returns a note with the planned before and
+// after values of the resource attribute at the given position,
+// if a plan with changes of the resource was loaded (see [ModulesFeature.LoadPlan])
+func (f *ModulesFeature) PlannedChangeHoverNote(modPath string, filename string, pos hcl.Pos) (string, hcl.Range, bool) {
+	changes, err := f.stateStore.PlannedChanges.ModulePlannedChanges(document.DirHandleFromPath(modPath))
+	if err != nil || len(changes) == 0 {
+		return "", hcl.Range{}, false
+	}
+
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return "", hcl.Range{}, false
+	}
+
+	return plannedChangeHoverNote(mod.ParsedModuleFiles[ast.ModFilename(filename)], changes, pos)
+}
+
+// LoadPlan loads the given plan of the root module, replacing any
+// previously loaded plan, so that planned changes are shown alongside
+// the configuration via code lenses and hovers
+func (f *ModulesFeature) LoadPlan(ctx context.Context, modPath, planPath string) error {
+	return jobs.LoadPlan(ctx, f.stateStore.PlannedChanges, modPath, planPath)
+}
+
+// AppendCodeLenses appends the code lens summarizing
+// planned changes of resources from the loaded plan
+func (f *ModulesFeature) AppendCodeLenses(ctx context.Context, lenses []lang.CodeLensFunc) []lang.CodeLensFunc {
+	return append(lenses, plannedChangesLens(f.stateStore.PlannedChanges))
+}
+
+// IndexModule ensures the module at the given path is parsed and decoded,
+// even if none of its files were opened. This is useful for features
+// which need to look beyond open files, such as renaming.
+func (f *ModulesFeature) IndexModule(ctx context.Context, modPath string) (job.IDs, error) {
+	err := f.Store.AddIfNotExists(modPath)
+	if err != nil {
+		return job.IDs{}, err
+	}
+
+	return f.decodeModule(ctx, document.DirHandleFromPath(modPath), false, true)
+}
+
+// PlanModule schedules a plan of the given module, which
+// reports errors only detected during planning (see [jobs.TofuPlan])
+func (f *ModulesFeature) PlanModule(ctx context.Context, modPath string) (job.ID, error) {
+	return f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: document.DirHandleFromPath(modPath),
+		// Cancelling the request (e.g. the tofu.plan command) stops tofu plan
+		Func: job.CancelWith(ctx, func(ctx context.Context) error {
+			return jobs.TofuPlan(ctx, f.fs, f.Store, modPath)
+		}),
+		Type:        op.OpTypeTofuPlan.String(),
+		IgnoreState: true,
+	})
+}
+
 // MetadataReady checks if a given module exists and if it's metadata has been
 // loaded. We need the metadata to enable other features like validation for
 // variables.
@@ -0,0 +1,279 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-ls/internal/document"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+// plannedChangesLens returns a code lens for each instance of a resource
+// with a change in the loaded plan, summarizing the change the same way
+// tofu plan does, e.g. "~ update in-place (3 attributes)". Changes of
+// resources in child modules are shown in the child module, using
+// plans of all root modules calling it.
+func plannedChangesLens(plannedChanges *globalState.PlannedChangeStore) lang.CodeLensFunc {
+	return func(ctx context.Context, path lang.Path, file string) ([]lang.CodeLens, error) {
+		lenses := make([]lang.CodeLens, 0)
+
+		if path.LanguageID != ilsp.OpenTofu.String() {
+			return lenses, nil
+		}
+
+		changes, err := plannedChanges.ModulePlannedChanges(document.DirHandleFromPath(path.Path))
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 {
+			return lenses, nil
+		}
+
+		pathCtx, err := decoder.PathCtx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		hclFile, ok := pathCtx.Files[file]
+		if !ok {
+			return lenses, nil
+		}
+
+		for _, block := range resourceBlocks(hclFile) {
+			instances := resourceChanges(changes, block)
+			for _, pc := range instances {
+				title := plannedChangeSummary(pc.Change)
+				if label := instanceLabel(pc); label != "" {
+					title = fmt.Sprintf("%s %s", label, title)
+				}
+
+				lenses = append(lenses, lang.CodeLens{
+					Range: block.DefRange(),
+					Command: lang.Command{
+						Title: title,
+					},
+				})
+			}
+		}
+
+		return lenses, nil
+	}
+}
+
+// plannedChangeHoverNote returns a note with the planned before and after
+// values of the resource attribute at the given position, for each
+// instance of the resource with a change in the loaded plan
+func plannedChangeHoverNote(file *hcl.File, changes []*globalState.PlannedChange, pos hcl.Pos) (string, hcl.Range, bool) {
+	for _, block := range resourceBlocks(file) {
+		if !block.Range().ContainsPos(pos) {
+			continue
+		}
+
+		for name, attr := range block.Body.Attributes {
+			if !attr.SrcRange.ContainsPos(pos) {
+				continue
+			}
+
+			instances := resourceChanges(changes, block)
+			if len(instances) == 0 {
+				return "", hcl.Range{}, false
+			}
+
+			var note strings.Builder
+			fmt.Fprintf(&note, "Planned changes of `%s`:\n", name)
+			for _, pc := range instances {
+				c := pc.Change
+				fmt.Fprintf(&note, "\n- `%s` (%s): %s → %s", pc.Address, plannedChangeSummary(c),
+					plannedValue(attrValue(c.Before, name), nil, attrValue(c.BeforeSensitive, name)),
+					plannedValue(attrValue(c.After, name), attrValue(c.AfterUnknown, name), attrValue(c.AfterSensitive, name)))
+			}
+
+			return note.String(), attr.SrcRange, true
+		}
+	}
+
+	return "", hcl.Range{}, false
+}
+
+// resourceBlocks returns all resource and data blocks of the file
+func resourceBlocks(file *hcl.File) []*hclsyntax.Block {
+	blocks := make([]*hclsyntax.Block, 0)
+	if file == nil {
+		return blocks
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return blocks
+	}
+
+	for _, block := range body.Blocks {
+		if (block.Type == "resource" || block.Type == "data") && len(block.Labels) == 2 {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// resourceChanges returns planned changes of all instances of the
+// resource declared by the given block, which actually change anything.
+// The changes are expected to be changes of the module declaring the block.
+func resourceChanges(changes []*globalState.PlannedChange, block *hclsyntax.Block) []*globalState.PlannedChange {
+	mode := tfjson.ManagedResourceMode
+	if block.Type == "data" {
+		mode = tfjson.DataResourceMode
+	}
+
+	instances := make([]*globalState.PlannedChange, 0)
+	for _, pc := range changes {
+		if pc.Mode != mode ||
+			pc.Type != block.Labels[0] || pc.Name != block.Labels[1] {
+			continue
+		}
+		if plannedChangeSummary(pc.Change) == "" {
+			continue
+		}
+		instances = append(instances, pc)
+	}
+	return instances
+}
+
+// plannedChangeSummary describes the actions of the change,
+// or returns an empty string if there is nothing to change
+func plannedChangeSummary(change *tfjson.Change) string {
+	var summary string
+	switch actions := change.Actions; {
+	case actions.Create():
+		return "+ create"
+	case actions.Read():
+		return "<= read"
+	case actions.Delete():
+		return "- destroy"
+	case actions.Update():
+		summary = "~ update in-place"
+	case actions.DestroyBeforeCreate():
+		summary = "-/+ destroy and then create replacement"
+	case actions.CreateBeforeDestroy():
+		summary = "+/- create replacement and then destroy"
+	default:
+		return ""
+	}
+
+	switch count := len(changedAttributes(change)); count {
+	case 0:
+		return summary
+	case 1:
+		return summary + " (1 attribute)"
+	default:
+		return fmt.Sprintf("%s (%d attributes)", summary, count)
+	}
+}
+
+// changedAttributes returns names of all attributes
+// which differ between the before and after values
+func changedAttributes(change *tfjson.Change) []string {
+	before, _ := change.Before.(map[string]interface{})
+	after, _ := change.After.(map[string]interface{})
+
+	names := make(map[string]struct{}, 0)
+	for name := range before {
+		names[name] = struct{}{}
+	}
+	for name := range after {
+		names[name] = struct{}{}
+	}
+
+	changed := make([]string, 0)
+	for name := range names {
+		if !reflect.DeepEqual(before[name], after[name]) || isMarked(attrValue(change.AfterUnknown, name)) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// plannedValue renders a value of the plan, unless it is
+// (partially) unknown or sensitive, as indicated by the
+// corresponding after_unknown and *_sensitive values
+func plannedValue(value, unknown, sensitive interface{}) string {
+	if isMarked(sensitive) {
+		return "(sensitive value)"
+	}
+	if isMarked(unknown) {
+		return "(known after apply)"
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "(unknown)"
+	}
+	return fmt.Sprintf("`%s`", b)
+}
+
+func attrValue(value interface{}, name string) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return m[name]
+}
+
+// isMarked reports whether the given value of after_unknown,
+// before_sensitive or after_sensitive marks the corresponding
+// value or any of its nested values
+func isMarked(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case map[string]interface{}:
+		for _, nested := range v {
+			if isMarked(nested) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, nested := range v {
+			if isMarked(nested) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// instanceLabel returns the label distinguishing the resource instance
+// of the change in code lenses, which is its full address for instances
+// in child modules, as the module may be called more than once
+func instanceLabel(pc *globalState.PlannedChange) string {
+	if pc.ModuleAddress != "" {
+		return pc.Address
+	}
+	if pc.Index != nil {
+		return instanceKey(pc.Index)
+	}
+	return ""
+}
+
+// instanceKey formats the index of a resource instance
+// the same way it appears in its address, e.g. [0] or ["a"]
+func instanceKey(index interface{}) string {
+	b, err := json.Marshal(index)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("[%s]", b)
+}
@@ -6,10 +6,13 @@
 package state
 
 import (
+	"slices"
+
 	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl/v2"
 
 	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
+	"github.com/opentofu/tofu-ls/internal/rename"
 	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
 	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
 )
@@ -27,6 +30,11 @@ type ModuleRecord struct {
 	RefTargetsErr   error
 	RefTargetsState op.OpState
 
+	// Moves are managed resources and module calls relabeled
+	// since the module was first decoded, which are not recorded
+	// in any moved block yet
+	Moves []rename.Move
+
 	RefOrigins      reference.Origins
 	RefOriginsErr   error
 	RefOriginsState op.OpState
@@ -55,6 +63,8 @@ func (m *ModuleRecord) Copy() *ModuleRecord {
 		RefTargetsErr:   m.RefTargetsErr,
 		RefTargetsState: m.RefTargetsState,
 
+		Moves: slices.Clone(m.Moves),
+
 		RefOrigins:      m.RefOrigins.Copy(),
 		RefOriginsErr:   m.RefOriginsErr,
 		RefOriginsState: m.RefOriginsState,
@@ -108,6 +118,7 @@ func newModule(modPath string) *ModuleRecord {
 			globalAst.SchemaValidationSource:    op.OpStateUnknown,
 			globalAst.ReferenceValidationSource: op.OpStateUnknown,
 			globalAst.TofuValidateSource:        op.OpStateUnknown,
+			globalAst.TofuPlanSource:            op.OpStateUnknown,
 		},
 	}
 }
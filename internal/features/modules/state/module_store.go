@@ -19,9 +19,11 @@ import (
 	tfaddr "github.com/opentofu/registry-address"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
+	"github.com/opentofu/tofu-ls/internal/rename"
 	globalState "github.com/opentofu/tofu-ls/internal/state"
 	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
 	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type ModuleStore struct {
@@ -501,7 +503,7 @@ func (s *ModuleStore) SetReferenceTargetsState(path string, state op.OpState) er
 	return nil
 }
 
-func (s *ModuleStore) UpdateReferenceTargets(path string, refs reference.Targets, rErr error) error {
+func (s *ModuleStore) UpdateReferenceTargets(path string, refs reference.Targets, moves []rename.Move, rErr error) error {
 	txn := s.db.Txn(true)
 	txn.Defer(func() {
 		s.SetReferenceTargetsState(path, op.OpStateLoaded)
@@ -515,6 +517,7 @@ func (s *ModuleStore) UpdateReferenceTargets(path string, refs reference.Targets
 
 	mod.RefTargets = refs
 	mod.RefTargetsErr = rErr
+	mod.Moves = moves
 
 	err = txn.Insert(s.tableName, mod)
 	if err != nil {
@@ -594,6 +597,9 @@ func (s *ModuleStore) queueModuleChange(oldMod, newMod *ModuleRecord) error {
 		if len(newMod.Meta.ProviderRequirements) > 0 {
 			changes.ProviderRequirements = true
 		}
+		if len(newMod.Meta.Variables) > 0 {
+			changes.Variables = true
+		}
 	// module removed
 	case oldMod != nil && newMod == nil:
 		changes.IsRemoval = true
@@ -610,6 +616,9 @@ func (s *ModuleStore) queueModuleChange(oldMod, newMod *ModuleRecord) error {
 		if len(oldMod.Meta.ProviderRequirements) > 0 {
 			changes.ProviderRequirements = true
 		}
+		if len(oldMod.Meta.Variables) > 0 {
+			changes.Variables = true
+		}
 	// module changed
 	default:
 		if !oldMod.Meta.CoreRequirements.Equals(newMod.Meta.CoreRequirements) {
@@ -624,6 +633,9 @@ func (s *ModuleStore) queueModuleChange(oldMod, newMod *ModuleRecord) error {
 		if !oldMod.Meta.ProviderRequirements.Equals(newMod.Meta.ProviderRequirements) {
 			changes.ProviderRequirements = true
 		}
+		if !variablesEqual(oldMod.Meta.Variables, newMod.Meta.Variables) {
+			changes.Variables = true
+		}
 	}
 
 	oldDiags, newDiags := 0, 0
@@ -666,6 +678,36 @@ func (s *ModuleStore) queueModuleChange(oldMod, newMod *ModuleRecord) error {
 	return s.changeStore.QueueChange(modHandle, changes)
 }
 
+// variablesEqual compares the declared variables
+// as far as they can affect their values
+func variablesEqual(old, new map[string]tfmod.Variable) bool {
+	if len(old) != len(new) {
+		return false
+	}
+
+	for name, oldVar := range old {
+		newVar, ok := new[name]
+		if !ok {
+			return false
+		}
+		if !oldVar.Type.Equals(newVar.Type) || oldVar.IsSensitive != newVar.IsSensitive {
+			return false
+		}
+		oldHasDefault, newHasDefault := oldVar.DefaultValue != cty.NilVal, newVar.DefaultValue != cty.NilVal
+		if oldHasDefault != newHasDefault {
+			return false
+		}
+		if !oldHasDefault {
+			continue
+		}
+		if !oldVar.DefaultValue.RawEquals(newVar.DefaultValue) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (f *ModuleStore) MetadataReady(dir document.DirHandle) (<-chan struct{}, bool, error) {
 	rTxn := f.db.Txn(false)
 
@@ -13,11 +13,14 @@ import (
 	"github.com/hashicorp/go-version"
 	tfmod "github.com/opentofu/opentofu-schema/module"
 	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/eventbus"
 	"github.com/opentofu/tofu-ls/internal/features/rootmodules/jobs"
 	"github.com/opentofu/tofu-ls/internal/features/rootmodules/state"
+	"github.com/opentofu/tofu-ls/internal/job"
 	globalState "github.com/opentofu/tofu-ls/internal/state"
 	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
 )
 
 // RootModulesFeature groups everything related to root modules. Its internal
@@ -154,6 +157,39 @@ func (f *RootModulesFeature) CallersOfModule(modPath string) ([]string, error) {
 	return f.Store.CallersOfModule(modPath)
 }
 
+// IndexModuleManifests ensures the module manifests of all root modules
+// discovered in the workspace are parsed, even if none of their files
+// were opened, so that [RootModulesFeature.CallersOfModule] knows them.
+func (f *RootModulesFeature) IndexModuleManifests(ctx context.Context) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	records, err := f.Store.List()
+	if err != nil {
+		return ids, err
+	}
+
+	for _, record := range records {
+		if record.ModManifestState != op.OpStateUnknown {
+			continue
+		}
+
+		path := record.Path()
+		id, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+			Dir: document.DirHandleFromPath(path),
+			Func: func(ctx context.Context) error {
+				return jobs.ParseModuleManifest(ctx, f.fs, f.Store, path)
+			},
+			Type: op.OpTypeParseModuleManifest.String(),
+		})
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
 // InstalledModulePath checks the installed modules in the given root module
 // for the given normalized source address.
 //
@@ -0,0 +1,56 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+type TemplateFilename string
+
+// NewTemplateFilename returns the filename of a template file
+func NewTemplateFilename(name string) (TemplateFilename, bool) {
+	if IsTemplateFilename(name) {
+		return TemplateFilename(name), true
+	}
+	return "", false
+}
+
+// IsTemplateFilename returns true for template files,
+// as rendered by the templatefile function
+func IsTemplateFilename(name string) bool {
+	return strings.HasSuffix(name, ".tftpl")
+}
+
+func (tf TemplateFilename) String() string {
+	return string(tf)
+}
+
+func (tf TemplateFilename) IsIgnored() bool {
+	return globalAst.IsIgnoredFile(string(tf))
+}
+
+// TemplateFile represents a parsed template. Unlike configuration
+// files, templates have no body, but consist of a single expression.
+type TemplateFile struct {
+	Expr  hclsyntax.Expression
+	Bytes []byte
+}
+
+type TemplateFiles = filestore.Files[TemplateFilename, TemplateFile]
+
+type TemplateDiags = filestore.Diags[TemplateFilename]
+
+func TemplateDiagsFromMap(m map[string]hcl.Diagnostics) TemplateDiags {
+	return filestore.DiagsFromMap[TemplateFilename](m)
+}
+
+type SourceTemplateDiags = filestore.SourceDiags[TemplateFilename]
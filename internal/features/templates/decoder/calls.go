@@ -0,0 +1,173 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ModuleReader provides access to the parsed files of modules,
+// which may render templates via the templatefile function
+type ModuleReader interface {
+	Paths(ctx context.Context) []lang.Path
+	ParsedFiles(modPath string) (map[string]*hcl.File, error)
+}
+
+// TemplateCall represents a call of the templatefile function
+type TemplateCall struct {
+	ModulePath string
+
+	// TemplatePath is the absolute path of the rendered template
+	TemplatePath string
+	Range        hcl.Range
+
+	// Vars maps names of the variables passed to the template
+	// to the ranges of their keys in the object
+	Vars map[string]hcl.Range
+
+	// HasDynamicVars is true if the variables are not passed
+	// as an object literal, so we cannot tell which are passed
+	HasDynamicVars bool
+}
+
+// Callers returns all templatefile calls in the workspace which render
+// the template at the given path, sorted by module path and position
+func Callers(ctx context.Context, moduleReader ModuleReader, templatePath string) []TemplateCall {
+	calls := make([]TemplateCall, 0)
+
+	for _, path := range moduleReader.Paths(ctx) {
+		files, err := moduleReader.ParsedFiles(path.Path)
+		if err != nil {
+			continue
+		}
+
+		for _, call := range TemplateCalls(path.Path, files) {
+			if call.TemplatePath == templatePath {
+				calls = append(calls, call)
+			}
+		}
+	}
+
+	sort.SliceStable(calls, func(i, j int) bool {
+		if calls[i].ModulePath != calls[j].ModulePath {
+			return calls[i].ModulePath < calls[j].ModulePath
+		}
+		if calls[i].Range.Filename != calls[j].Range.Filename {
+			return calls[i].Range.Filename < calls[j].Range.Filename
+		}
+		return calls[i].Range.Start.Byte < calls[j].Range.Start.Byte
+	})
+
+	return calls
+}
+
+// TemplateCalls returns all templatefile calls in the given module files
+// with a template path which can be resolved without evaluation
+func TemplateCalls(modPath string, files map[string]*hcl.File) []TemplateCall {
+	calls := make([]TemplateCall, 0)
+
+	for _, file := range files {
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			// JSON files are not supported yet
+			continue
+		}
+
+		hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+			fce, ok := node.(*hclsyntax.FunctionCallExpr)
+			if !ok || fce.Name != "templatefile" || len(fce.Args) != 2 {
+				return nil
+			}
+
+			tplPath, ok := templatePath(modPath, fce.Args[0])
+			if !ok {
+				return nil
+			}
+
+			call := TemplateCall{
+				ModulePath:   modPath,
+				TemplatePath: tplPath,
+				Range:        fce.Range(),
+				Vars:         make(map[string]hcl.Range),
+			}
+
+			obj, ok := fce.Args[1].(*hclsyntax.ObjectConsExpr)
+			if !ok {
+				call.HasDynamicVars = true
+				calls = append(calls, call)
+				return nil
+			}
+			for _, item := range obj.Items {
+				val, diags := item.KeyExpr.Value(nil)
+				if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || !val.Type().Equals(cty.String) {
+					call.HasDynamicVars = true
+					continue
+				}
+				call.Vars[val.AsString()] = item.KeyExpr.Range()
+			}
+			calls = append(calls, call)
+
+			return nil
+		})
+	}
+
+	return calls
+}
+
+// templatePath returns the absolute path of the template if it is
+// given as a literal path or a path relative to path.module.
+//
+// Relative literal paths are resolved from the working directory
+// of OpenTofu, which is assumed to be the directory of the module.
+func templatePath(modPath string, expr hclsyntax.Expression) (string, bool) {
+	tplExpr, ok := expr.(*hclsyntax.TemplateExpr)
+	if !ok {
+		return "", false
+	}
+
+	var b strings.Builder
+	for i, part := range tplExpr.Parts {
+		switch p := part.(type) {
+		case *hclsyntax.LiteralValueExpr:
+			if !p.Val.Type().Equals(cty.String) || p.Val.IsNull() {
+				return "", false
+			}
+			b.WriteString(p.Val.AsString())
+		case *hclsyntax.ScopeTraversalExpr:
+			if i != 0 || !isPathModule(p.Traversal) {
+				return "", false
+			}
+			b.WriteString(".")
+		default:
+			return "", false
+		}
+	}
+
+	path := filepath.FromSlash(b.String())
+	if path == "" {
+		return "", false
+	}
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), true
+	}
+	return filepath.Join(modPath, path), true
+}
+
+func isPathModule(traversal hcl.Traversal) bool {
+	if len(traversal) != 2 || traversal.RootName() != "path" {
+		return false
+	}
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	return ok && attr.Name == "module"
+}
@@ -0,0 +1,261 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder_test
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/tofu-ls/internal/features/templates/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/templates/decoder"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+type ModuleReaderMock struct {
+	files map[string]map[string]*hcl.File
+}
+
+func (m ModuleReaderMock) Paths(ctx context.Context) []lang.Path {
+	paths := make([]lang.Path, 0)
+	for modPath := range m.files {
+		paths = append(paths, lang.Path{
+			Path:       modPath,
+			LanguageID: ilsp.OpenTofu.String(),
+		})
+	}
+	return paths
+}
+
+func (m ModuleReaderMock) ParsedFiles(modPath string) (map[string]*hcl.File, error) {
+	files, ok := m.files[modPath]
+	if !ok {
+		return nil, &globalState.RecordNotFoundError{Source: modPath}
+	}
+	return files, nil
+}
+
+var testModuleConfig = `locals {
+  user_data = templatefile("${path.module}/templates/init.tftpl", {
+    hostname = "web"
+    "port"   = 8080
+  })
+  other = templatefile("templates/init.tftpl", { hostname = "db", user = "admin" })
+  dynamic = templatefile("${path.module}/templates/dynamic.tftpl", local.vars)
+  unknown = templatefile("${path.module}/${var.name}.tftpl", {})
+}
+`
+
+var testTemplate = `#!/bin/sh
+hostnamectl set-hostname ${hostname}
+%{ for p in ports ~}
+listen ${p}:${port}
+%{ endfor ~}
+echo ${host
+`
+
+func testModuleReader(t *testing.T, modPath string) ModuleReaderMock {
+	f, diags := hclsyntax.ParseConfig([]byte(testModuleConfig), "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	return ModuleReaderMock{
+		files: map[string]map[string]*hcl.File{
+			modPath: {"main.tf": f},
+		},
+	}
+}
+
+func testTemplateFile(t *testing.T, src string) *ast.TemplateFile {
+	expr, _ := hclsyntax.ParseTemplate([]byte(src), "init.tftpl", hcl.InitialPos)
+	return &ast.TemplateFile{
+		Expr:  expr,
+		Bytes: []byte(src),
+	}
+}
+
+func TestCallers(t *testing.T) {
+	modPath := t.TempDir()
+	moduleReader := testModuleReader(t, modPath)
+
+	calls := fdecoder.Callers(context.Background(), moduleReader, filepath.Join(modPath, "templates", "init.tftpl"))
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, %d given", len(calls))
+	}
+
+	expectedVars := map[string]hcl.Range{
+		"hostname": {
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 3, Column: 5, Byte: 81},
+			End:      hcl.Pos{Line: 3, Column: 13, Byte: 89},
+		},
+		"port": {
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 4, Column: 5, Byte: 102},
+			End:      hcl.Pos{Line: 4, Column: 11, Byte: 108},
+		},
+	}
+	if diff := cmp.Diff(expectedVars, calls[0].Vars); diff != "" {
+		t.Fatalf("unexpected variables: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"hostname", "user"}, sortedKeys(calls[1].Vars)); diff != "" {
+		t.Fatalf("unexpected variables: %s", diff)
+	}
+
+	calls = fdecoder.Callers(context.Background(), moduleReader, filepath.Join(modPath, "templates", "dynamic.tftpl"))
+	if len(calls) != 1 || !calls[0].HasDynamicVars {
+		t.Fatalf("expected a single call with dynamic variables: %#v", calls)
+	}
+}
+
+func TestUndefinedVariables(t *testing.T) {
+	modPath := t.TempDir()
+	templatesPath := filepath.Join(modPath, "templates")
+
+	files := ast.TemplateFiles{
+		"init.tftpl":    testTemplateFile(t, testTemplate),
+		"dynamic.tftpl": testTemplateFile(t, "${anything}"),
+		"unused.tftpl":  testTemplateFile(t, "${anything}"),
+	}
+
+	diags := fdecoder.UndefinedVariables(context.Background(), templatesPath, files, testModuleReader(t, modPath))
+
+	// Variables declared by the for directive are not reported
+	expectedDiags := lang.DiagnosticsMap{
+		"init.tftpl": hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Undefined template variable",
+				Detail:   `None of the templatefile calls rendering this template passes a variable named "ports".`,
+				Subject: &hcl.Range{
+					Filename: "init.tftpl",
+					Start:    hcl.Pos{Line: 3, Column: 13, Byte: 59},
+					End:      hcl.Pos{Line: 3, Column: 18, Byte: 64},
+				},
+			},
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Undefined template variable",
+				Detail:   `None of the templatefile calls rendering this template passes a variable named "host".`,
+				Subject: &hcl.Range{
+					Filename: "init.tftpl",
+					Start:    hcl.Pos{Line: 6, Column: 8, Byte: 108},
+					End:      hcl.Pos{Line: 6, Column: 12, Byte: 112},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diags); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
+
+func TestVariableCandidates(t *testing.T) {
+	modPath := t.TempDir()
+	templatePath := filepath.Join(modPath, "templates", "init.tftpl")
+	moduleReader := testModuleReader(t, modPath)
+	file := testTemplateFile(t, testTemplate)
+
+	offset := strings.Index(testTemplate, "${host") + len("${host")
+	candidates := fdecoder.VariableCandidates(context.Background(), moduleReader, templatePath, file, hcl.Pos{
+		Line:   6,
+		Column: 13,
+		Byte:   offset,
+	})
+
+	editRange := hcl.Range{
+		Filename: "init.tftpl",
+		Start:    hcl.Pos{Line: 6, Column: 9, Byte: offset - 4},
+		End:      hcl.Pos{Line: 6, Column: 13, Byte: offset},
+	}
+	expectedCandidates := lang.CompleteCandidates([]lang.Candidate{
+		{
+			Label:  "hostname",
+			Detail: "template variable",
+			Kind:   lang.ReferenceCandidateKind,
+			TextEdit: lang.TextEdit{
+				Range:   editRange,
+				NewText: "hostname",
+				Snippet: "hostname",
+			},
+		},
+	})
+	if diff := cmp.Diff(expectedCandidates, candidates); diff != "" {
+		t.Fatalf("unexpected candidates: %s", diff)
+	}
+
+	// No candidates outside of interpolations
+	candidates = fdecoder.VariableCandidates(context.Background(), moduleReader, templatePath, file, hcl.Pos{
+		Line:   2,
+		Column: 5,
+		Byte:   14,
+	})
+	if len(candidates.List) != 0 {
+		t.Fatalf("expected no candidates, %d given", len(candidates.List))
+	}
+}
+
+func TestVariableDefinitions(t *testing.T) {
+	modPath := t.TempDir()
+	templatePath := filepath.Join(modPath, "templates", "init.tftpl")
+	file := testTemplateFile(t, testTemplate)
+
+	targets := fdecoder.VariableDefinitions(context.Background(), testModuleReader(t, modPath), templatePath, file, hcl.Pos{
+		Line:   2,
+		Column: 30,
+		Byte:   39,
+	})
+
+	originRange := hcl.Range{
+		Filename: "init.tftpl",
+		Start:    hcl.Pos{Line: 2, Column: 28, Byte: 37},
+		End:      hcl.Pos{Line: 2, Column: 36, Byte: 45},
+	}
+	path := lang.Path{
+		Path:       modPath,
+		LanguageID: ilsp.OpenTofu.String(),
+	}
+	expectedTargets := decoder.ReferenceTargets{
+		{
+			OriginRange: originRange,
+			Path:        path,
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 3, Column: 5, Byte: 81},
+				End:      hcl.Pos{Line: 3, Column: 13, Byte: 89},
+			},
+		},
+		{
+			OriginRange: originRange,
+			Path:        path,
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 6, Column: 50, Byte: 172},
+				End:      hcl.Pos{Line: 6, Column: 58, Byte: 180},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedTargets, targets); diff != "" {
+		t.Fatalf("unexpected targets: %s", diff)
+	}
+}
+
+func sortedKeys(m map[string]hcl.Range) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,211 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/tofu-ls/internal/features/templates/ast"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+)
+
+// referencedVariables returns references to variables in the template.
+// Symbols declared within the template, e.g. by for directives, are excluded.
+func referencedVariables(file *ast.TemplateFile) []hcl.Traversal {
+	if file == nil || file.Expr == nil {
+		return []hcl.Traversal{}
+	}
+	return hclsyntax.Variables(file.Expr)
+}
+
+// passedVariables returns the names of all variables passed
+// to the template by any of the calls, or false if the calls
+// don't tell us which variables are passed
+func passedVariables(calls []TemplateCall) (map[string]bool, bool) {
+	if len(calls) == 0 {
+		return nil, false
+	}
+
+	names := make(map[string]bool)
+	for _, call := range calls {
+		if call.HasDynamicVars {
+			return nil, false
+		}
+		for name := range call.Vars {
+			names[name] = true
+		}
+	}
+
+	return names, true
+}
+
+// UndefinedVariables reports references to variables which none of
+// the templatefile calls rendering the template passes to it.
+//
+// Templates without any calls, or with calls passing variables
+// which cannot be determined statically, are not checked.
+func UndefinedVariables(ctx context.Context, dirPath string, files ast.TemplateFiles, moduleReader ModuleReader) lang.DiagnosticsMap {
+	diagsMap := make(lang.DiagnosticsMap)
+
+	for filename, file := range files {
+		calls := Callers(ctx, moduleReader, filepath.Join(dirPath, filename.String()))
+		names, ok := passedVariables(calls)
+		if !ok {
+			continue
+		}
+
+		for _, traversal := range referencedVariables(file) {
+			name := traversal.RootName()
+			if names[name] {
+				continue
+			}
+
+			diagsMap[filename.String()] = diagsMap[filename.String()].Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Undefined template variable",
+				Detail: fmt.Sprintf("None of the templatefile calls rendering this template passes a variable named %q.",
+					name),
+				Subject: traversal[0].SourceRange().Ptr(),
+			})
+		}
+	}
+
+	return diagsMap
+}
+
+// VariableCandidates returns completion candidates for variables passed
+// to the template, if the position is within an interpolation or directive
+func VariableCandidates(ctx context.Context, moduleReader ModuleReader, templatePath string, file *ast.TemplateFile, pos hcl.Pos) lang.Candidates {
+	candidates := lang.ZeroCandidates()
+	if file == nil || pos.Byte > len(file.Bytes) {
+		return candidates
+	}
+
+	prefix, ok := variablePrefix(file.Bytes[:pos.Byte])
+	if !ok {
+		return candidates
+	}
+
+	calls := Callers(ctx, moduleReader, templatePath)
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, call := range calls {
+		for name := range call.Vars {
+			if seen[name] || !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	editRange := hcl.Range{
+		Filename: filepath.Base(templatePath),
+		Start: hcl.Pos{
+			Line:   pos.Line,
+			Column: pos.Column - len(prefix),
+			Byte:   pos.Byte - len(prefix),
+		},
+		End: pos,
+	}
+	for _, name := range names {
+		candidates.List = append(candidates.List, lang.Candidate{
+			Label:  name,
+			Detail: "template variable",
+			Kind:   lang.ReferenceCandidateKind,
+			TextEdit: lang.TextEdit{
+				Range:   editRange,
+				NewText: name,
+				Snippet: name,
+			},
+		})
+	}
+
+	return candidates
+}
+
+// variablePrefix returns the part of a variable name preceding the end
+// of the given source, if it ends within an interpolation or directive
+func variablePrefix(src []byte) (string, bool) {
+	start := len(src)
+	for start > 0 && isNameByte(src[start-1]) {
+		start--
+	}
+	prefix := string(src[start:])
+	if prefix != "" && !hclsyntax.ValidIdentifier(prefix) {
+		return "", false
+	}
+
+	// Attributes of variables are not known
+	before := bytes.TrimRight(src[:start], " \t\r\n")
+	if bytes.HasSuffix(before, []byte(".")) {
+		return "", false
+	}
+
+	// We only look for the nearest opening sequence here, which is not
+	// followed by a closing brace, rather than parsing the template,
+	// as the template is usually incomplete when completion is requested
+	open := max(bytes.LastIndex(src[:start], []byte("${")), bytes.LastIndex(src[:start], []byte("%{")))
+	if open < 0 {
+		return "", false
+	}
+	if open > 0 && src[open-1] == src[open] {
+		// Escaped sequences, i.e. $${ and %%{, are literal
+		return "", false
+	}
+	if bytes.IndexByte(src[open:start], '}') >= 0 {
+		return "", false
+	}
+
+	return prefix, true
+}
+
+func isNameByte(b byte) bool {
+	return b == '_' || b == '-' ||
+		(b >= 'a' && b <= 'z') ||
+		(b >= 'A' && b <= 'Z') ||
+		(b >= '0' && b <= '9')
+}
+
+// VariableDefinitions returns the keys of objects passing the variable
+// at the given position to the template in templatefile calls
+func VariableDefinitions(ctx context.Context, moduleReader ModuleReader, templatePath string, file *ast.TemplateFile, pos hcl.Pos) decoder.ReferenceTargets {
+	targets := make(decoder.ReferenceTargets, 0)
+
+	for _, traversal := range referencedVariables(file) {
+		originRange := traversal[0].SourceRange()
+		if !originRange.ContainsPos(pos) {
+			continue
+		}
+
+		for _, call := range Callers(ctx, moduleReader, templatePath) {
+			keyRange, ok := call.Vars[traversal.RootName()]
+			if !ok {
+				continue
+			}
+			targets = append(targets, &decoder.ReferenceTarget{
+				OriginRange: originRange,
+				Path: lang.Path{
+					Path:       call.ModulePath,
+					LanguageID: ilsp.OpenTofu.String(),
+				},
+				Range: keyRange,
+			})
+		}
+	}
+
+	return targets
+}
@@ -0,0 +1,133 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package templates
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/templates/jobs"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/lsp"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+func (f *TemplatesFeature) eventHooks() filestore.Hooks {
+	return filestore.Hooks{
+		Parse: func(ctx context.Context, path string) error {
+			return jobs.ParseTemplateFiles(ctx, f.fs, f.store, path)
+		},
+		ParseType: op.OpTypeParseTemplateFiles,
+		Validate: func(ctx context.Context, path string) error {
+			return jobs.TemplateVariablesValidation(ctx, f.store, f.moduleFeature, path)
+		},
+		ValidateType:    op.OpTypeTemplateVariablesValidation,
+		Index:           f.indexCallers,
+		DocumentChanged: f.moduleChanged,
+	}
+}
+
+// moduleChanged handles opened or changed modules, which may render
+// templates in their directory or any of its subdirectories
+func (f *TemplatesFeature) moduleChanged(ctx context.Context, dir document.DirHandle, languageID string) (job.IDs, bool, error) {
+	if !lsp.IsValidConfigLanguage(languageID) {
+		return job.IDs{}, false, nil
+	}
+
+	ids, err := f.validateTemplatesWithin(ctx, dir)
+	return ids, true, err
+}
+
+// validateTemplatesWithin validates already parsed templates in the
+// directory of a changed module and its subdirectories again, as the
+// module may have changed the variables it passes to them
+func (f *TemplatesFeature) validateTemplatesWithin(ctx context.Context, modDir document.DirHandle) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	validationOptions, err := lsctx.ValidationOptions(ctx)
+	if err != nil {
+		return ids, err
+	}
+	if !validationOptions.EnableEnhancedValidation {
+		return ids, nil
+	}
+
+	records, err := f.store.List()
+	if err != nil {
+		return ids, err
+	}
+
+	// The modules feature has already scheduled parsing of the module
+	modIds, err := f.stateStore.JobStore.ListIncompleteJobsForDir(modDir)
+	if err != nil {
+		return ids, err
+	}
+
+	for _, record := range records {
+		if !isWithinDir(modDir.Path(), record.Path()) {
+			continue
+		}
+
+		dir := document.DirHandleFromPath(record.Path())
+		path := record.Path()
+		tplIds, err := f.stateStore.JobStore.ListIncompleteJobsForDir(dir)
+		if err != nil {
+			return ids, err
+		}
+
+		id, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+			Dir: dir,
+			Func: func(ctx context.Context) error {
+				return jobs.TemplateVariablesValidation(ctx, f.store, f.moduleFeature, path)
+			},
+			Type:        op.OpTypeTemplateVariablesValidation.String(),
+			DependsOn:   append(modIds.Copy(), tplIds...),
+			IgnoreState: true,
+		})
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// indexCallers ensures that all modules which may render templates in the
+// given directory are indexed, i.e. modules in the directory itself or any
+// of its parent directories, and returns IDs of the scheduled jobs
+func (f *TemplatesFeature) indexCallers(ctx context.Context, path string) job.IDs {
+	ids := make(job.IDs, 0)
+
+	for _, modPath := range f.moduleFeature.Paths(ctx) {
+		if !isWithinDir(modPath.Path, path) {
+			continue
+		}
+
+		modIds, err := f.moduleFeature.IndexModule(ctx, modPath.Path)
+		if err != nil {
+			f.logger.Printf("failed to index module calling templates in %q: %s", path, err)
+			continue
+		}
+		ids = append(ids, modIds...)
+	}
+
+	return ids
+}
+
+// isWithinDir returns true if the path is the given directory
+// or any of its subdirectories
+func isWithinDir(dirPath, path string) bool {
+	rel, err := filepath.Rel(dirPath, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,20 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+
+	"github.com/opentofu/tofu-ls/internal/features/templates/parser"
+	"github.com/opentofu/tofu-ls/internal/features/templates/state"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+)
+
+// ParseTemplateFiles parses all template files in the given directory,
+// i.e. turns bytes of `*.tftpl` files into template expressions.
+func ParseTemplateFiles(ctx context.Context, fs ReadOnlyFS, templateStore *state.TemplateStore, dirPath string) error {
+	return filestore.ParseFiles(ctx, fs, templateStore, parser.Templates, dirPath)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/templates/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/templates/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/templates/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// TemplateVariablesValidation checks references to variables in all
+// templates of the given directory against the variables passed to them
+// by templatefile calls and produces diagnostics for undefined ones.
+//
+// It relies on previously parsed templates (via [ParseTemplateFiles])
+// and on the modules calling templatefile being parsed.
+func TemplateVariablesValidation(ctx context.Context, templateStore *state.TemplateStore, moduleFeature fdecoder.ModuleReader, dirPath string) error {
+	record, err := templateStore.RecordByPath(dirPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid validation if it is already in progress or already finished
+	if record.DiagnosticsState[globalAst.ReferenceValidationSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(dirPath)}
+	}
+
+	err = templateStore.SetDiagnosticsState(dirPath, globalAst.ReferenceValidationSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	diags := fdecoder.UndefinedVariables(ctx, dirPath, record.ParsedFiles, moduleFeature)
+
+	return templateStore.UpdateDiagnostics(dirPath, globalAst.ReferenceValidationSource, ast.TemplateDiagsFromMap(diags))
+}
@@ -0,0 +1,33 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/tofu-ls/internal/features/templates/ast"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+)
+
+// Templates recognizes and parses template files
+var Templates = filestore.Parser[ast.TemplateFilename, ast.TemplateFile]{
+	NewFilename:     ast.NewTemplateFilename,
+	ParseFile:       parseTemplate,
+	IsValidLanguage: ilsp.IsValidTemplateLanguage,
+}
+
+// parseTemplate parses the template the same way the templatefile
+// function does. The parser always returns an expression, even
+// if the template is invalid, so that it can be partially analyzed.
+func parseTemplate(src []byte, filename ast.TemplateFilename) (*ast.TemplateFile, hcl.Diagnostics) {
+	expr, diags := hclsyntax.ParseTemplate(src, filename.String(), hcl.InitialPos)
+
+	return &ast.TemplateFile{
+		Expr:  expr,
+		Bytes: src,
+	}, diags
+}
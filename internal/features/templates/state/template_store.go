@@ -0,0 +1,34 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"github.com/opentofu/tofu-ls/internal/features/templates/ast"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+const (
+	templateTableName = "templates"
+)
+
+// TemplateStore keeps the template files of each directory
+type TemplateStore = filestore.Store[ast.TemplateFilename, ast.TemplateFile]
+
+// TemplateRecord contains all information about the
+// template files we have for a certain path
+type TemplateRecord = filestore.Record[ast.TemplateFilename, ast.TemplateFile]
+
+// diagnosticSources are the sources of diagnostics tracked per record
+var diagnosticSources = []globalAst.DiagnosticSource{
+	globalAst.HCLParsingSource,
+	globalAst.ReferenceValidationSource,
+}
+
+func NewTemplateStore(changeStore *globalState.ChangeStore) (*TemplateStore, error) {
+	return filestore.NewStore[ast.TemplateFilename, ast.TemplateFile](templateTableName, diagnosticSources, changeStore)
+}
@@ -0,0 +1,155 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package templates
+
+import (
+	"context"
+	"io"
+	"log"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/features/templates/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/templates/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/templates/jobs"
+	"github.com/opentofu/tofu-ls/internal/features/templates/parser"
+	"github.com/opentofu/tofu-ls/internal/features/templates/state"
+	"github.com/opentofu/tofu-ls/internal/filestore"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+// ModuleFeature provides access to the modules calling templatefile.
+// Modules which may render templates are indexed as soon as a template is opened.
+type ModuleFeature interface {
+	fdecoder.ModuleReader
+	IndexModule(ctx context.Context, modPath string) (job.IDs, error)
+}
+
+// TemplatesFeature groups everything related to template files.
+// Its internal state keeps track of all such files in the workspace.
+type TemplatesFeature struct {
+	store    *state.TemplateStore
+	eventbus *eventbus.EventBus
+	stopFunc context.CancelFunc
+	logger   *log.Logger
+	events   *filestore.Events[ast.TemplateFilename, ast.TemplateFile]
+
+	moduleFeature ModuleFeature
+	stateStore    *globalState.StateStore
+	fs            jobs.ReadOnlyFS
+}
+
+func NewTemplatesFeature(eventbus *eventbus.EventBus, stateStore *globalState.StateStore, fs jobs.ReadOnlyFS, moduleFeature ModuleFeature) (*TemplatesFeature, error) {
+	store, err := state.NewTemplateStore(stateStore.ChangeStore)
+	if err != nil {
+		return nil, err
+	}
+	discardLogger := log.New(io.Discard, "", 0)
+
+	f := &TemplatesFeature{
+		store:         store,
+		eventbus:      eventbus,
+		stopFunc:      func() {},
+		logger:        discardLogger,
+		moduleFeature: moduleFeature,
+		stateStore:    stateStore,
+		fs:            fs,
+	}
+	f.events = filestore.NewEvents("template files", store, stateStore, parser.Templates, f.eventHooks())
+
+	return f, nil
+}
+
+func (f *TemplatesFeature) SetLogger(logger *log.Logger) {
+	f.logger = logger
+	f.store.SetLogger(logger)
+	f.events.SetLogger(logger)
+}
+
+// Start starts the features separate goroutine.
+// It listens to various events from the EventBus and performs corresponding actions.
+func (f *TemplatesFeature) Start(ctx context.Context) {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	f.stopFunc = cancelFunc
+
+	f.events.Start(ctx, f.eventbus, "feature.templates")
+}
+
+func (f *TemplatesFeature) Stop() {
+	f.stopFunc()
+	f.logger.Print("stopped templates feature")
+}
+
+func (f *TemplatesFeature) Paths(ctx context.Context) []lang.Path {
+	paths := make([]lang.Path, 0)
+
+	records, err := f.store.List()
+	if err != nil {
+		return paths
+	}
+
+	for _, record := range records {
+		paths = append(paths, lang.Path{
+			Path:       record.Path(),
+			LanguageID: ilsp.OpenTofuTemplate.String(),
+		})
+	}
+
+	return paths
+}
+
+func (f *TemplatesFeature) Diagnostics(path string) diagnostics.Diagnostics {
+	diags := diagnostics.NewDiagnostics()
+
+	record, err := f.store.RecordByPath(path)
+	if err != nil {
+		return diags
+	}
+
+	for source, td := range record.Diagnostics {
+		diags.Append(source, td.AsMap())
+	}
+
+	return diags
+}
+
+// VariableCandidates returns completion candidates for variables
+// passed to the template by templatefile calls
+func (f *TemplatesFeature) VariableCandidates(ctx context.Context, dirPath string, filename string, pos hcl.Pos) lang.Candidates {
+	file, ok := f.parsedFile(dirPath, filename)
+	if !ok {
+		return lang.ZeroCandidates()
+	}
+
+	return fdecoder.VariableCandidates(ctx, f.moduleFeature, filepath.Join(dirPath, filename), file, pos)
+}
+
+// VariableDefinitions returns the object keys of templatefile calls
+// passing the variable at the given position to the template
+func (f *TemplatesFeature) VariableDefinitions(ctx context.Context, dirPath string, filename string, pos hcl.Pos) decoder.ReferenceTargets {
+	file, ok := f.parsedFile(dirPath, filename)
+	if !ok {
+		return decoder.ReferenceTargets{}
+	}
+
+	return fdecoder.VariableDefinitions(ctx, f.moduleFeature, filepath.Join(dirPath, filename), file, pos)
+}
+
+func (f *TemplatesFeature) parsedFile(dirPath string, filename string) (*ast.TemplateFile, bool) {
+	record, err := f.store.RecordByPath(dirPath)
+	if err != nil {
+		return nil, false
+	}
+
+	file, ok := record.ParsedFiles[ast.TemplateFilename(filename)]
+	return file, ok && file != nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+type TestFilename string
+
+// NewTestFilename returns the filename of a test file or a mock file,
+// both of which are managed by the tests feature
+func NewTestFilename(name string) (TestFilename, bool) {
+	if IsTestFilename(name) || IsMockFilename(name) {
+		return TestFilename(name), true
+	}
+	return "", false
+}
+
+// IsTestFilename returns true for files which are picked up by `tofu test`,
+// i.e. both the OpenTofu specific and the Terraform compatible extensions
+func IsTestFilename(name string) bool {
+	return strings.HasSuffix(name, ".tftest.hcl") ||
+		strings.HasSuffix(name, ".tftest.json") ||
+		strings.HasSuffix(name, ".tofutest.hcl") ||
+		strings.HasSuffix(name, ".tofutest.json")
+}
+
+// IsMockFilename returns true for files with mock_resource, mock_data
+// and override blocks, which are loaded via source of mock_provider blocks
+func IsMockFilename(name string) bool {
+	return strings.HasSuffix(name, ".tfmock.hcl") ||
+		strings.HasSuffix(name, ".tfmock.json")
+}
+
+func (tf TestFilename) String() string {
+	return string(tf)
+}
+
+func (tf TestFilename) IsJSON() bool {
+	return strings.HasSuffix(string(tf), ".json")
+}
+
+func (tf TestFilename) IsMock() bool {
+	return IsMockFilename(string(tf))
+}
+
+func (tf TestFilename) IsIgnored() bool {
+	return globalAst.IsIgnoredFile(string(tf))
+}
+
+type TestFiles map[TestFilename]*hcl.File
+
+func TestFilesFromMap(m map[string]*hcl.File) TestFiles {
+	mf := make(TestFiles, len(m))
+	for name, file := range m {
+		mf[TestFilename(name)] = file
+	}
+	return mf
+}
+
+// HasMockFiles returns true if any of the files is a mock file
+func (tf TestFiles) HasMockFiles() bool {
+	for name := range tf {
+		if name.IsMock() {
+			return true
+		}
+	}
+	return false
+}
+
+func (tf TestFiles) Copy() TestFiles {
+	m := make(TestFiles, len(tf))
+	for name, file := range tf {
+		m[name] = file
+	}
+	return m
+}
+
+type TestDiags map[TestFilename]hcl.Diagnostics
+
+func TestDiagsFromMap(m map[string]hcl.Diagnostics) TestDiags {
+	mf := make(TestDiags, len(m))
+	for name, file := range m {
+		mf[TestFilename(name)] = file
+	}
+	return mf
+}
+
+func (td TestDiags) Copy() TestDiags {
+	m := make(TestDiags, len(td))
+	for name, file := range td {
+		m[name] = file
+	}
+	return m
+}
+
+func (td TestDiags) AsMap() map[string]hcl.Diagnostics {
+	m := make(map[string]hcl.Diagnostics, len(td))
+	for name, diags := range td {
+		m[string(name)] = diags
+	}
+	return m
+}
+
+func (td TestDiags) Count() int {
+	count := 0
+	for _, diags := range td {
+		count += len(diags)
+	}
+	return count
+}
+
+type SourceTestDiags map[globalAst.DiagnosticSource]TestDiags
+
+func (std SourceTestDiags) Count() int {
+	count := 0
+	for _, diags := range std {
+		count += diags.Count()
+	}
+	return count
+}
@@ -0,0 +1,56 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"testing"
+)
+
+func TestIsTestFilename(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{"main.tftest.hcl", true},
+		{"main.tofutest.hcl", true},
+		{"main.tftest.json", true},
+		{"main.tofutest.json", true},
+		{"main.tf", false},
+		{"main.tofu", false},
+		{"terraform.tfvars", false},
+		{"main.tftest.hcl.bak", false},
+		{"tftest.hcl.tf", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsTestFilename(tc.name); got != tc.expected {
+				t.Fatalf("expected %t for %q, given %t", tc.expected, tc.name, got)
+			}
+		})
+	}
+}
+
+func TestIsMockFilename(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{"aws.tfmock.hcl", true},
+		{"aws.tfmock.json", true},
+		{"main.tftest.hcl", false},
+		{"main.tf", false},
+		{"aws.tfmock.hcl.bak", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsMockFilename(tc.name); got != tc.expected {
+				t.Fatalf("expected %t for %q, given %t", tc.expected, tc.name, got)
+			}
+		})
+	}
+}
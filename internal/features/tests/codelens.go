@@ -0,0 +1,84 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/codelens"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/tests/decoder"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+// runTestsLens returns a code lens running all tests of a test
+// file and a code lens for each run block reporting just that block
+// via the tofu.test command. tofu test cannot run a single run block,
+// so the latter still runs the whole file, but no other files.
+func runTestsLens() lang.CodeLensFunc {
+	return func(ctx context.Context, path lang.Path, file string) ([]lang.CodeLens, error) {
+		lenses := make([]lang.CodeLens, 0)
+
+		if path.LanguageID != ilsp.OpenTofuTest.String() || !ast.IsTestFilename(file) {
+			return lenses, nil
+		}
+
+		pathCtx, err := decoder.PathCtx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		hclFile, ok := pathCtx.Files[file]
+		if !ok {
+			return lenses, nil
+		}
+		runBlocks := fdecoder.RunBlocks(hclFile)
+		if len(runBlocks) == 0 {
+			return lenses, nil
+		}
+
+		cmdId := cmd.Name("tofu.test")
+		if commandPrefix, ok := lsctx.CommandPrefix(ctx); ok && commandPrefix != "" {
+			cmdId = commandPrefix + "." + cmdId
+		}
+		uriArg := codelens.NewCommandArg("uri", uri.FromPath(filepath.Join(path.Path, file)))
+
+		lenses = append(lenses, lang.CodeLens{
+			Range: hcl.Range{
+				Filename: file,
+				Start:    hcl.InitialPos,
+				End:      hcl.InitialPos,
+			},
+			Command: lang.Command{
+				Title:     "Run all tests",
+				ID:        cmdId,
+				Arguments: []lang.CommandArgument{uriArg},
+			},
+		})
+
+		for _, block := range runBlocks {
+			lenses = append(lenses, lang.CodeLens{
+				Range: block.DefRange,
+				Command: lang.Command{
+					Title: "Run test",
+					ID:    cmdId,
+					Arguments: []lang.CommandArgument{
+						uriArg,
+						codelens.NewCommandArg("run", block.Labels[0]),
+					},
+				},
+			})
+		}
+
+		return lenses, nil
+	}
+}
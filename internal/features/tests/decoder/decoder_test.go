@@ -0,0 +1,284 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	tfschema "github.com/opentofu/opentofu-schema/schema"
+	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/tests/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ModuleReaderMock reads a module under test with the given provider
+// requirements, or no module at all if there are none
+type ModuleReaderMock struct {
+	requirements tfmod.ProviderRequirements
+}
+
+func (m ModuleReaderMock) ModuleInputs(modPath string) (map[string]tfmod.Variable, error) {
+	return nil, nil
+}
+
+func (m ModuleReaderMock) ModuleOutputs(modPath string) (map[string]tfmod.Output, error) {
+	return nil, nil
+}
+
+func (m ModuleReaderMock) ProviderRequirements(modPath string) (tfmod.ProviderRequirements, error) {
+	if m.requirements == nil {
+		return nil, errors.New("module not found")
+	}
+	return m.requirements, nil
+}
+
+func (m ModuleReaderMock) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	return nil, errors.New("module not found")
+}
+
+func (m ModuleReaderMock) MetadataReady(dir document.DirHandle) (<-chan struct{}, bool, error) {
+	return nil, false, errors.New("module not found")
+}
+
+// mockPathDecoder returns a decoder of a mock file with the given
+// source, with a schema of the test provider being known
+func mockPathDecoder(t *testing.T, src string) *decoder.PathDecoder {
+	return mockPathDecoderWithModule(t, src, ModuleReaderMock{})
+}
+
+// mockPathDecoderWithModule returns a decoder of a mock file with the
+// given source, with schemas of the test provider and of an unrelated
+// provider being known
+func mockPathDecoderWithModule(t *testing.T, src string, moduleReader ModuleReaderMock) *decoder.PathDecoder {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = globalStore.ProviderSchemas.AddPreloadedSchema(
+		globalState.NewDefaultProvider("test"),
+		version.Must(version.NewVersion("1.0.0")),
+		&tfschema.ProviderSchema{
+			Resources: map[string]*schema.BodySchema{
+				"test_instance": {
+					Attributes: map[string]*schema.AttributeSchema{
+						"id": {
+							Constraint: schema.AnyExpression{OfType: cty.String},
+							IsComputed: true,
+						},
+					},
+					Blocks: map[string]*schema.BlockSchema{
+						"network": {
+							Type: schema.BlockTypeList,
+							Body: &schema.BodySchema{
+								Attributes: map[string]*schema.AttributeSchema{
+									"ip": {
+										Constraint: schema.AnyExpression{OfType: cty.String},
+										IsComputed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = globalStore.ProviderSchemas.AddPreloadedSchema(
+		tfaddr.NewProvider(tfaddr.DefaultProviderRegistryHost, "other", "test"),
+		version.Must(version.NewVersion("1.0.0")),
+		&tfschema.ProviderSchema{
+			Resources: map[string]*schema.BodySchema{
+				"other_instance": {
+					Attributes: map[string]*schema.AttributeSchema{
+						"id": {
+							Constraint: schema.AnyExpression{OfType: cty.String},
+							IsComputed: true,
+						},
+					},
+				},
+			},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := state.NewTestStore(globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testPath := t.TempDir()
+	err = ts.Add(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, diags := hclsyntax.ParseConfig([]byte(src), "test.tfmock.hcl", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	err = ts.UpdateParsedTestFiles(testPath, ast.TestFiles{"test.tfmock.hcl": f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:  ts,
+		ModuleReader: moduleReader,
+		SchemaReader: globalStore.ProviderSchemas,
+	})
+	pd, err := d.Path(lang.Path{
+		Path:       testPath,
+		LanguageID: ilsp.OpenTofuMock.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pd
+}
+
+func TestDecoder_mockDefaultsValidation(t *testing.T) {
+	src := `mock_resource "test_instance" {
+  defaults = {
+    id      = "i-123"
+    unknown = "foo"
+    network = [
+      { ip = "10.0.0.1", port = 80 },
+    ]
+  }
+}
+
+mock_resource "test_unknown" {
+  defaults = {
+    anything = "goes"
+  }
+}
+`
+	pd := mockPathDecoder(t, src)
+
+	diagsMap, err := pd.Validate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDiags := lang.DiagnosticsMap{
+		"test.tfmock.hcl": hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Unexpected attribute",
+				Detail:   `An attribute named "unknown" is not expected here`,
+				Subject: &hcl.Range{
+					Filename: "test.tfmock.hcl",
+					Start:    hcl.Pos{Line: 4, Column: 5, Byte: 73},
+					End:      hcl.Pos{Line: 4, Column: 12, Byte: 80},
+				},
+			},
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Unexpected attribute",
+				Detail:   `An attribute named "port" is not expected here`,
+				Subject: &hcl.Range{
+					Filename: "test.tfmock.hcl",
+					Start:    hcl.Pos{Line: 6, Column: 26, Byte: 130},
+					End:      hcl.Pos{Line: 6, Column: 30, Byte: 134},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diagsMap); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
+
+func TestDecoder_mockDefaultsValidation_moduleProviders(t *testing.T) {
+	src := `mock_resource "test_instance" {
+  defaults = {
+    id      = "i-123"
+    unknown = "foo"
+  }
+}
+
+mock_resource "other_instance" {
+  defaults = {
+    unknown = "foo"
+  }
+}
+`
+	pd := mockPathDecoderWithModule(t, src, ModuleReaderMock{
+		requirements: tfmod.ProviderRequirements{
+			globalState.NewDefaultProvider("test"): version.MustConstraints(version.NewConstraint(">= 1.0")),
+		},
+	})
+
+	diagsMap, err := pd.Validate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only providers required by the module under test apply
+	expectedDiags := lang.DiagnosticsMap{
+		"test.tfmock.hcl": hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Unexpected attribute",
+				Detail:   `An attribute named "unknown" is not expected here`,
+				Subject: &hcl.Range{
+					Filename: "test.tfmock.hcl",
+					Start:    hcl.Pos{Line: 4, Column: 5, Byte: 73},
+					End:      hcl.Pos{Line: 4, Column: 12, Byte: 80},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diagsMap); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
+
+func TestDecoder_mockDefaultsCompletion(t *testing.T) {
+	src := `mock_resource "test_instance" {
+  defaults = {
+    
+  }
+}
+`
+	pd := mockPathDecoder(t, src)
+
+	candidates, err := pd.CompletionAtPos(context.Background(), "test.tfmock.hcl", hcl.Pos{
+		Line:   3,
+		Column: 5,
+		Byte:   49,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := make([]string, 0, len(candidates.List))
+	for _, candidate := range candidates.List {
+		labels = append(labels, candidate.Label)
+	}
+	expectedLabels := []string{"id", "network"}
+	if diff := cmp.Diff(expectedLabels, labels); diff != "" {
+		t.Fatalf("unexpected candidates: %s", diff)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"sync"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+)
+
+func mockPathContext(record *state.TestRecord, moduleReader ModuleReader, schemaReader SchemaReader) (*decoder.PathContext, error) {
+	modPath := ModuleUnderTestPath(record.Path())
+
+	// Resources of the module under test can be targeted by overrides
+	moduleTargets := make(reference.Targets, 0)
+	modCtx, err := moduleReader.PathContext(lang.Path{
+		Path:       modPath,
+		LanguageID: ilsp.OpenTofu.String(),
+	})
+	if err == nil {
+		moduleTargets = modCtx.ReferenceTargets
+	}
+
+	pathCtx := &decoder.PathContext{
+		Schema:           mockSchema(providerSchemas(record, modPath, moduleReader, schemaReader)),
+		ReferenceOrigins: make(reference.Origins, 0),
+		ReferenceTargets: accessibleModuleTargets(moduleTargets, record.Path(), modPath),
+		Files:            make(map[string]*hcl.File),
+		Validators:       testValidators,
+	}
+
+	for _, origin := range record.RefOrigins {
+		if ast.IsMockFilename(origin.OriginRange().Filename) {
+			pathCtx.ReferenceOrigins = append(pathCtx.ReferenceOrigins, origin)
+		}
+	}
+
+	for name, f := range record.ParsedTestFiles {
+		if !name.IsMock() {
+			continue
+		}
+		pathCtx.Files[name.String()] = f
+	}
+
+	return pathCtx, nil
+}
+
+// resourceSchemas holds the bodies of resources and data sources,
+// keyed by their type, as declared by the provider schemas
+type resourceSchemas struct {
+	Resources   map[string]*schema.BodySchema
+	DataSources map[string]*schema.BodySchema
+}
+
+// providerSchemas collects the schemas of resources and data sources
+// of the providers required by the module under test. As mock files
+// may also live outside of any module, all known provider schemas
+// are used if there is no module under test.
+//
+// Schemas are collected once per record, unless the requirements
+// of the module under test change.
+func providerSchemas(record *state.TestRecord, modPath string, moduleReader ModuleReader, schemaReader SchemaReader) resourceSchemas {
+	if schemaReader == nil {
+		return newResourceSchemas()
+	}
+
+	requirements, reqErr := moduleReader.ProviderRequirements(modPath)
+	if schemas, ok := recordSchemas.get(record, requirements); ok {
+		return schemas
+	}
+
+	schemas := newResourceSchemas()
+	if reqErr == nil {
+		for addr, cons := range requirements {
+			ps, err := schemaReader.ProviderSchema(modPath, addr, cons)
+			if err != nil || ps == nil {
+				continue
+			}
+			schemas.add(ps.Resources, ps.DataSources)
+		}
+	} else {
+		it, err := schemaReader.ListSchemas()
+		if err != nil {
+			return schemas
+		}
+		for ps := it.Next(); ps != nil; ps = it.Next() {
+			if ps.Schema == nil {
+				continue
+			}
+			schemas.add(ps.Schema.Resources, ps.Schema.DataSources)
+		}
+	}
+
+	recordSchemas.set(record, requirements, schemas)
+	return schemas
+}
+
+func newResourceSchemas() resourceSchemas {
+	return resourceSchemas{
+		Resources:   make(map[string]*schema.BodySchema),
+		DataSources: make(map[string]*schema.BodySchema),
+	}
+}
+
+func (rs resourceSchemas) add(resources, dataSources map[string]*schema.BodySchema) {
+	for rType, body := range resources {
+		if _, ok := rs.Resources[rType]; !ok {
+			rs.Resources[rType] = body
+		}
+	}
+	for dsType, body := range dataSources {
+		if _, ok := rs.DataSources[dsType]; !ok {
+			rs.DataSources[dsType] = body
+		}
+	}
+}
+
+// recordSchemas caches the provider schemas collected for test records
+var recordSchemas = &schemaCache{
+	entries: make(map[string]schemaCacheEntry),
+}
+
+// schemaCache holds the provider schemas collected for each test
+// record path. Records are never updated in place, so an entry only
+// applies as long as the record is the same one it was collected for.
+type schemaCache struct {
+	mu      sync.Mutex
+	entries map[string]schemaCacheEntry
+}
+
+type schemaCacheEntry struct {
+	record       *state.TestRecord
+	requirements tfmod.ProviderRequirements
+	schemas      resourceSchemas
+}
+
+func (c *schemaCache) get(record *state.TestRecord, requirements tfmod.ProviderRequirements) (resourceSchemas, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[record.Path()]
+	if !ok || entry.record != record || !requirementsEqual(entry.requirements, requirements) {
+		return resourceSchemas{}, false
+	}
+	return entry.schemas, true
+}
+
+func (c *schemaCache) set(record *state.TestRecord, requirements tfmod.ProviderRequirements, schemas resourceSchemas) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[record.Path()] = schemaCacheEntry{
+		record:       record,
+		requirements: requirements,
+		schemas:      schemas,
+	}
+}
+
+func requirementsEqual(a, b tfmod.ProviderRequirements) bool {
+	if (a == nil) != (b == nil) || len(a) != len(b) {
+		return false
+	}
+	for addr, aCons := range a {
+		bCons, ok := b[addr]
+		if !ok || aCons.String() != bCons.String() {
+			return false
+		}
+	}
+	return true
+}
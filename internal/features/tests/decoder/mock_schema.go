@@ -0,0 +1,175 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// mockSchema returns the schema of mock files, which are loaded
+// by mock_provider blocks of test files via their source
+func mockSchema(schemas resourceSchemas) *schema.BodySchema {
+	return &schema.BodySchema{
+		Blocks:   mockBlockSchemas(schemas),
+		HoverURL: testDocsURL,
+	}
+}
+
+// mockBlockSchemas returns the blocks which may appear both
+// in mock files and in mock_provider blocks of test files
+func mockBlockSchemas(schemas resourceSchemas) map[string]*schema.BlockSchema {
+	return map[string]*schema.BlockSchema{
+		"mock_resource":     mockResourceBlockSchema("resource", schemas.Resources),
+		"mock_data":         mockResourceBlockSchema("data source", schemas.DataSources),
+		"override_resource": overrideBlockSchema("resource", resourceScope, "values"),
+		"override_data":     overrideBlockSchema("data source", dataScope, "values"),
+	}
+}
+
+func mockProviderBlockSchema(schemas resourceSchemas) *schema.BlockSchema {
+	return &schema.BlockSchema{
+		Labels: []*schema.LabelSchema{
+			{
+				Name:        "name",
+				Description: lang.PlainText("Provider Name"),
+			},
+		},
+		Description: lang.Markdown("Mocked provider, which returns generated values for computed attributes " +
+			"instead of creating real infrastructure"),
+		Body: &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"alias": {
+					Constraint:  schema.LiteralType{Type: cty.String},
+					IsOptional:  true,
+					Description: lang.Markdown("Alias for using the same provider with different configurations"),
+				},
+				"source": {
+					Constraint:  schema.LiteralType{Type: cty.String},
+					IsOptional:  true,
+					Description: lang.Markdown("Directory with mock files (`*.tfmock.hcl`) to load"),
+				},
+			},
+			Blocks: mockBlockSchemas(schemas),
+		},
+	}
+}
+
+// mockResourceBlockSchema returns the schema of mock_resource and
+// mock_data blocks, where the attributes of defaults are informed
+// by the provider schema of the resource or data source type
+func mockResourceBlockSchema(kind string, bodies map[string]*schema.BodySchema) *schema.BlockSchema {
+	dependentBody := make(map[schema.SchemaKey]*schema.BodySchema, len(bodies))
+	for typeName, body := range bodies {
+		key := schema.NewSchemaKey(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: 0, Value: typeName},
+			},
+		})
+		dependentBody[key] = &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"defaults": {
+					Constraint: schema.Object{
+						Attributes: objectAttributes(body),
+					},
+					IsOptional:  true,
+					Description: lang.Markdown(fmt.Sprintf("Values to use for computed attributes of the %s", kind)),
+				},
+			},
+		}
+	}
+
+	return &schema.BlockSchema{
+		Labels: []*schema.LabelSchema{
+			{
+				Name:        "type",
+				Description: lang.PlainText(fmt.Sprintf("Type of the %s", kind)),
+				IsDepKey:    true,
+				Completable: true,
+			},
+		},
+		Description: lang.Markdown(fmt.Sprintf("Default values for all instances of a %s type", kind)),
+		Body: &schema.BodySchema{
+			HoverURL: testDocsURL,
+		},
+		DependentBody: dependentBody,
+	}
+}
+
+// overrideBlockSchema returns the schema of blocks which override
+// the given kind of objects with static values
+func overrideBlockSchema(kind string, scopeId lang.ScopeId, valuesName string) *schema.BlockSchema {
+	return &schema.BlockSchema{
+		Description: lang.Markdown(fmt.Sprintf("Override of a %s with static values", kind)),
+		Body: &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"target": {
+					Constraint:  schema.Reference{OfScopeId: scopeId},
+					IsRequired:  true,
+					Description: lang.Markdown(fmt.Sprintf("Address of the %s to override", kind)),
+				},
+				valuesName: {
+					Constraint:  schema.AnyExpression{OfType: cty.DynamicPseudoType},
+					IsOptional:  true,
+					Description: lang.Markdown(fmt.Sprintf("Values of the %s to use instead of the real ones", kind)),
+				},
+			},
+		},
+	}
+}
+
+// objectAttributes turns attributes and nested blocks
+// of a body into attributes of an object
+func objectAttributes(body *schema.BodySchema) schema.ObjectAttributes {
+	attributes := make(schema.ObjectAttributes)
+	if body == nil {
+		return attributes
+	}
+
+	for name, attr := range body.Attributes {
+		constraint := attr.Constraint
+		if constraint == nil {
+			constraint = schema.AnyExpression{OfType: cty.DynamicPseudoType}
+		}
+		attributes[name] = &schema.AttributeSchema{
+			Constraint:   constraint,
+			IsOptional:   true,
+			IsSensitive:  attr.IsSensitive,
+			IsDeprecated: attr.IsDeprecated,
+			Description:  attr.Description,
+		}
+	}
+
+	for name, block := range body.Blocks {
+		elem := schema.Object{
+			Attributes: objectAttributes(block.Body),
+		}
+
+		var constraint schema.Constraint
+		switch block.Type {
+		case schema.BlockTypeObject:
+			constraint = elem
+		case schema.BlockTypeSet:
+			constraint = schema.Set{Elem: elem}
+		case schema.BlockTypeMap:
+			constraint = schema.Map{Elem: elem}
+		default:
+			constraint = schema.List{Elem: elem}
+		}
+
+		attributes[name] = &schema.AttributeSchema{
+			Constraint:   constraint,
+			IsOptional:   true,
+			IsDeprecated: block.IsDeprecated,
+			Description:  block.Description,
+		}
+	}
+
+	return attributes
+}
@@ -0,0 +1,104 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	tfschema "github.com/opentofu/opentofu-schema/schema"
+	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+// testDirName is the name of the directory where
+// `tofu test` looks for test files by default
+const testDirName = "tests"
+
+type StateReader interface {
+	List() ([]*state.TestRecord, error)
+	TestRecordByPath(path string) (*state.TestRecord, error)
+}
+
+type ModuleReader interface {
+	ModuleInputs(modPath string) (map[string]tfmod.Variable, error)
+	ModuleOutputs(modPath string) (map[string]tfmod.Output, error)
+	ProviderRequirements(modPath string) (tfmod.ProviderRequirements, error)
+	PathContext(path lang.Path) (*decoder.PathContext, error)
+	MetadataReady(dir document.DirHandle) (<-chan struct{}, bool, error)
+}
+
+type SchemaReader interface {
+	ProviderSchema(modPath string, addr tfaddr.Provider, vc version.Constraints) (*tfschema.ProviderSchema, error)
+	ListSchemas() (*globalState.ProviderSchemaIterator, error)
+}
+
+type PathReader struct {
+	StateReader  StateReader
+	ModuleReader ModuleReader
+	SchemaReader SchemaReader
+}
+
+var _ decoder.PathReader = &PathReader{}
+
+func (pr *PathReader) Paths(ctx context.Context) []lang.Path {
+	paths := make([]lang.Path, 0)
+
+	testRecords, err := pr.StateReader.List()
+	if err != nil {
+		return paths
+	}
+
+	for _, record := range testRecords {
+		paths = append(paths, lang.Path{
+			Path:       record.Path(),
+			LanguageID: ilsp.OpenTofuTest.String(),
+		})
+		if record.ParsedTestFiles.HasMockFiles() {
+			paths = append(paths, lang.Path{
+				Path:       record.Path(),
+				LanguageID: ilsp.OpenTofuMock.String(),
+			})
+		}
+	}
+
+	return paths
+}
+
+// PathContext returns a PathContext for the given path based on the language ID.
+func (pr *PathReader) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	record, err := pr.StateReader.TestRecordByPath(path.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch path.LanguageID {
+	case ilsp.OpenTofuTest.String():
+		return testPathContext(record, pr.ModuleReader, pr.SchemaReader)
+	case ilsp.OpenTofuMock.String():
+		return mockPathContext(record, pr.ModuleReader, pr.SchemaReader)
+	}
+
+	return nil, fmt.Errorf("unknown language ID: %q", path.LanguageID)
+}
+
+// ModuleUnderTestPath returns the path of the module tested
+// by test files in the given directory, which is either the
+// directory itself, or its parent for the tests directory.
+func ModuleUnderTestPath(testPath string) string {
+	if filepath.Base(testPath) == testDirName {
+		return filepath.Dir(testPath)
+	}
+	return testPath
+}
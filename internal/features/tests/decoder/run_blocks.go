@@ -0,0 +1,33 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+var runHeaderSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "run",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+// RunBlocks returns the run blocks of the given
+// test file in the order they are declared in
+func RunBlocks(file *hcl.File) hcl.Blocks {
+	if file == nil {
+		return hcl.Blocks{}
+	}
+
+	content, _, _ := file.Body.PartialContent(runHeaderSchema)
+	if content == nil {
+		return hcl.Blocks{}
+	}
+	return content.Blocks
+}
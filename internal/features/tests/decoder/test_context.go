@@ -0,0 +1,190 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func testPathContext(record *state.TestRecord, moduleReader ModuleReader, schemaReader SchemaReader) (*decoder.PathContext, error) {
+	modPath := ModuleUnderTestPath(record.Path())
+
+	inputs, _ := moduleReader.ModuleInputs(modPath)
+	outputs, _ := moduleReader.ModuleOutputs(modPath)
+
+	// The module under test provides the provider schemas, functions
+	// and reference targets, such as resources, to the test files
+	var providerBlock *schema.BlockSchema
+	var functions map[string]schema.FunctionSignature
+	moduleTargets := make(reference.Targets, 0)
+	modCtx, err := moduleReader.PathContext(lang.Path{
+		Path:       modPath,
+		LanguageID: ilsp.OpenTofu.String(),
+	})
+	if err == nil {
+		if modCtx.Schema != nil {
+			providerBlock = modCtx.Schema.Blocks["provider"]
+		}
+		functions = modCtx.Functions
+		moduleTargets = modCtx.ReferenceTargets
+	}
+
+	pathCtx := &decoder.PathContext{
+		Schema:           testSchema(inputs, providerBlock, providerSchemas(record, modPath, moduleReader, schemaReader)),
+		ReferenceOrigins: make(reference.Origins, 0),
+		ReferenceTargets: make(reference.Targets, 0),
+		Files:            make(map[string]*hcl.File),
+		Functions:        functions,
+		Validators:       testValidators,
+	}
+
+	for _, origin := range record.RefOrigins {
+		if ast.IsTestFilename(origin.OriginRange().Filename) {
+			pathCtx.ReferenceOrigins = append(pathCtx.ReferenceOrigins, origin)
+		}
+	}
+
+	for _, target := range record.RefTargets {
+		if target.RangePtr == nil || ast.IsTestFilename(target.RangePtr.Filename) {
+			pathCtx.ReferenceTargets = append(pathCtx.ReferenceTargets, target)
+		}
+	}
+	pathCtx.ReferenceTargets = append(pathCtx.ReferenceTargets,
+		accessibleModuleTargets(moduleTargets, record.Path(), modPath)...)
+	pathCtx.ReferenceTargets = append(pathCtx.ReferenceTargets, runTargets(record.ParsedTestFiles, outputs)...)
+
+	for name, f := range record.ParsedTestFiles {
+		if name.IsMock() {
+			continue
+		}
+		pathCtx.Files[name.String()] = f
+	}
+
+	return pathCtx, nil
+}
+
+// runTargets returns targets for run blocks, which are addressable
+// as run.<name> with the outputs of the module under test as attributes
+func runTargets(files ast.TestFiles, outputs map[string]tfmod.Output) reference.Targets {
+	targets := make(reference.Targets, 0)
+
+	outputNames := make([]string, 0, len(outputs))
+	for name := range outputs {
+		outputNames = append(outputNames, name)
+	}
+	sort.Strings(outputNames)
+
+	attrTypes := make(map[string]cty.Type, len(outputs))
+	for _, name := range outputNames {
+		attrTypes[name] = cty.DynamicPseudoType
+	}
+
+	for _, f := range files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "run" || len(block.Labels) != 1 {
+				continue
+			}
+
+			addr := lang.Address{
+				lang.RootStep{Name: "run"},
+				lang.AttrStep{Name: block.Labels[0]},
+			}
+			rng := block.Range()
+			defRng := block.DefRange()
+
+			nestedTargets := make(reference.Targets, 0, len(outputNames))
+			for _, name := range outputNames {
+				nestedTargets = append(nestedTargets, reference.Target{
+					Addr:        append(addr.Copy(), lang.AttrStep{Name: name}),
+					ScopeId:     runScope,
+					RangePtr:    rng.Ptr(),
+					DefRangePtr: defRng.Ptr(),
+					Type:        cty.DynamicPseudoType,
+					Description: lang.PlainText(outputs[name].Description),
+				})
+			}
+
+			targets = append(targets, reference.Target{
+				Addr:          addr,
+				ScopeId:       runScope,
+				RangePtr:      rng.Ptr(),
+				DefRangePtr:   defRng.Ptr(),
+				Type:          cty.Object(attrTypes),
+				Name:          "run",
+				NestedTargets: nestedTargets,
+			})
+		}
+	}
+
+	return targets
+}
+
+// accessibleModuleTargets returns the targets of the module under test
+// which can be referenced from test files, relocated to the test directory
+func accessibleModuleTargets(moduleTargets reference.Targets, testPath, modPath string) reference.Targets {
+	targets := make(reference.Targets, 0, len(moduleTargets))
+	for _, target := range moduleTargets {
+		// Locals of the module are not accessible from tests
+		if len(target.Addr) > 0 && target.Addr[0].String() == "local" {
+			continue
+		}
+		targets = append(targets, relocateTarget(target, testPath, modPath))
+	}
+	return targets
+}
+
+// relocateTarget makes ranges of a target declared in the module
+// under test relative to the directory of the test files,
+// so that they can be resolved from the test files
+func relocateTarget(target reference.Target, testPath, modPath string) reference.Target {
+	if testPath == modPath {
+		return target
+	}
+
+	target = target.Copy()
+	target.RangePtr = relocateRange(target.RangePtr, testPath, modPath)
+	target.DefRangePtr = relocateRange(target.DefRangePtr, testPath, modPath)
+	target.TargetableFromRangePtr = relocateRange(target.TargetableFromRangePtr, testPath, modPath)
+
+	for i, nestedTarget := range target.NestedTargets {
+		target.NestedTargets[i] = relocateTarget(nestedTarget, testPath, modPath)
+	}
+
+	return target
+}
+
+func relocateRange(rng *hcl.Range, testPath, modPath string) *hcl.Range {
+	if rng == nil {
+		return nil
+	}
+
+	filename, err := filepath.Rel(testPath, filepath.Join(modPath, rng.Filename))
+	if err != nil {
+		return rng
+	}
+
+	newRng := *rng
+	newRng.Filename = filename
+	return &newRng
+}
@@ -0,0 +1,265 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const testDocsURL = "https://opentofu.org/docs/cli/commands/test/"
+
+var (
+	dataScope     = lang.ScopeId("data")
+	moduleScope   = lang.ScopeId("module")
+	outputScope   = lang.ScopeId("output")
+	resourceScope = lang.ScopeId("resource")
+	runScope      = lang.ScopeId("run")
+	variableScope = lang.ScopeId("variable")
+)
+
+// testSchema returns the schema of test files, where variables are
+// informed by the inputs of the module under test, provider blocks
+// by the schema of the provider block of the module, if it is known,
+// and mocks by the schemas of resources and data sources
+func testSchema(inputs map[string]tfmod.Variable, providerBlock *schema.BlockSchema, schemas resourceSchemas) *schema.BodySchema {
+	if providerBlock == nil {
+		providerBlock = providerBlockSchema()
+	}
+
+	return &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"run":               runBlockSchema(inputs),
+			"variables":         variablesBlockSchema(inputs, true),
+			"provider":          providerBlock,
+			"mock_provider":     mockProviderBlockSchema(schemas),
+			"override_resource": overrideBlockSchema("resource", resourceScope, "values"),
+			"override_data":     overrideBlockSchema("data source", dataScope, "values"),
+			"override_module":   overrideBlockSchema("module", moduleScope, "outputs"),
+		},
+		HoverURL: testDocsURL,
+	}
+}
+
+func runBlockSchema(inputs map[string]tfmod.Variable) *schema.BlockSchema {
+	return &schema.BlockSchema{
+		Labels: []*schema.LabelSchema{
+			{
+				Name:        "name",
+				Description: lang.PlainText("Run Name"),
+			},
+		},
+		Description: lang.Markdown("Run block executing `tofu plan` or `tofu apply` against the module under test " +
+			"and checking the assertions. Outputs of the run can be referenced in later runs as `run.<name>.<output>`"),
+		Body: &schema.BodySchema{
+			HoverURL: testDocsURL,
+			Attributes: map[string]*schema.AttributeSchema{
+				"command": {
+					Constraint: schema.OneOf{
+						schema.Keyword{
+							Keyword:     "apply",
+							Description: lang.Markdown("Create real infrastructure, as with `tofu apply`"),
+						},
+						schema.Keyword{
+							Keyword:     "plan",
+							Description: lang.Markdown("Only create a plan, as with `tofu plan`"),
+						},
+					},
+					IsOptional:  true,
+					Description: lang.Markdown("Command to run, either `apply` (default) or `plan`"),
+				},
+				"expect_failures": {
+					Constraint: schema.Set{
+						Elem: schema.OneOf{
+							schema.Reference{OfScopeId: resourceScope},
+							schema.Reference{OfScopeId: dataScope},
+							schema.Reference{OfScopeId: moduleScope},
+							schema.Reference{OfScopeId: outputScope},
+							schema.Reference{OfScopeId: variableScope},
+						},
+					},
+					IsOptional: true,
+					Description: lang.Markdown("Checkable objects, such as variables or outputs, which are expected " +
+						"to fail their custom conditions in this run"),
+				},
+				"providers": {
+					Constraint:  schema.Map{Elem: schema.AnyExpression{OfType: cty.DynamicPseudoType}},
+					IsOptional:  true,
+					Description: lang.Markdown("Providers to pass to the module under test, keyed by their names in the module"),
+				},
+			},
+			Blocks: map[string]*schema.BlockSchema{
+				"variables":         variablesBlockSchema(inputs, false),
+				"override_resource": overrideBlockSchema("resource", resourceScope, "values"),
+				"override_data":     overrideBlockSchema("data source", dataScope, "values"),
+				"override_module":   overrideBlockSchema("module", moduleScope, "outputs"),
+				"module": {
+					Description: lang.Markdown("Alternative module to run instead of the module under test, " +
+						"e.g. to set up or verify infrastructure"),
+					MaxItems: 1,
+					Body: &schema.BodySchema{
+						Attributes: map[string]*schema.AttributeSchema{
+							"source": {
+								Constraint:  schema.LiteralType{Type: cty.String},
+								IsRequired:  true,
+								Description: lang.Markdown("Local path or registry address of the module"),
+							},
+							"version": {
+								Constraint:  schema.LiteralType{Type: cty.String},
+								IsOptional:  true,
+								Description: lang.Markdown("Version constraint of a registry module"),
+							},
+						},
+					},
+				},
+				"plan_options": {
+					Description: lang.Markdown("Options of the plan created by this run"),
+					MaxItems:    1,
+					Body: &schema.BodySchema{
+						Attributes: map[string]*schema.AttributeSchema{
+							"mode": {
+								Constraint: schema.OneOf{
+									schema.Keyword{
+										Keyword:     "normal",
+										Description: lang.Markdown("Plan all changes (default)"),
+									},
+									schema.Keyword{
+										Keyword:     "refresh-only",
+										Description: lang.Markdown("Only refresh the state"),
+									},
+								},
+								IsOptional:  true,
+								Description: lang.Markdown("Planning mode, either `normal` (default) or `refresh-only`"),
+							},
+							"refresh": {
+								Constraint:  schema.LiteralType{Type: cty.Bool},
+								IsOptional:  true,
+								Description: lang.Markdown("Whether to refresh the state before planning, defaults to `true`"),
+							},
+							"replace": {
+								Constraint:  targetableReferences(),
+								IsOptional:  true,
+								Description: lang.Markdown("Resources to replace, as with `tofu plan -replace`"),
+							},
+							"target": {
+								Constraint:  targetableReferences(),
+								IsOptional:  true,
+								Description: lang.Markdown("Resources to target, as with `tofu plan -target`"),
+							},
+						},
+					},
+				},
+				"assert": {
+					Description: lang.Markdown("Assertion to check after the run"),
+					Body: &schema.BodySchema{
+						Attributes: map[string]*schema.AttributeSchema{
+							"condition": {
+								Constraint:  schema.AnyExpression{OfType: cty.Bool},
+								IsRequired:  true,
+								Description: lang.Markdown("Condition which must be `true` for the run to pass"),
+							},
+							"error_message": {
+								Constraint:  schema.AnyExpression{OfType: cty.String},
+								IsRequired:  true,
+								Description: lang.Markdown("Error message to show when the condition is not met"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// variablesBlockSchema returns the schema of variables blocks, which may
+// set any inputs of the module under test as well as any other variables.
+// Only the file level variables can be referenced elsewhere as var.<name>.
+func variablesBlockSchema(inputs map[string]tfmod.Variable, addressable bool) *schema.BlockSchema {
+	var address *schema.AttributeAddrSchema
+	if addressable {
+		address = &schema.AttributeAddrSchema{
+			Steps: schema.Address{
+				schema.StaticStep{Name: "var"},
+				schema.AttrNameStep{},
+			},
+			ScopeId:    variableScope,
+			AsExprType: true,
+		}
+	}
+
+	attributes := make(map[string]*schema.AttributeSchema, len(inputs))
+	for name, input := range inputs {
+		varType := input.Type
+		if varType == cty.NilType {
+			varType = cty.DynamicPseudoType
+		}
+
+		attributes[name] = &schema.AttributeSchema{
+			Constraint:  schema.AnyExpression{OfType: varType},
+			IsOptional:  true,
+			IsSensitive: input.IsSensitive,
+			Description: lang.PlainText(input.Description),
+			Address:     address,
+		}
+	}
+
+	var description lang.MarkupContent
+	if addressable {
+		description = lang.Markdown("Variables used by all runs in the file")
+	} else {
+		description = lang.Markdown("Variables used by this run, overriding the variables of the file")
+	}
+
+	return &schema.BlockSchema{
+		Description: description,
+		MaxItems:    1,
+		Body: &schema.BodySchema{
+			Attributes: attributes,
+			AnyAttribute: &schema.AttributeSchema{
+				Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+				IsOptional: true,
+				Address:    address,
+			},
+		},
+	}
+}
+
+// providerBlockSchema is used for provider blocks when the
+// schema of the module under test is not known (yet)
+func providerBlockSchema() *schema.BlockSchema {
+	return &schema.BlockSchema{
+		Labels: []*schema.LabelSchema{
+			{
+				Name:        "name",
+				Description: lang.PlainText("Provider Name"),
+				IsDepKey:    true,
+			},
+		},
+		Description: lang.Markdown("Provider configuration used by the runs in the file"),
+		Body: &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"alias": {
+					Constraint:  schema.LiteralType{Type: cty.String},
+					IsOptional:  true,
+					Description: lang.Markdown("Alias for using the same provider with different configurations"),
+				},
+			},
+		},
+		DependentBody: map[schema.SchemaKey]*schema.BodySchema{},
+	}
+}
+
+func targetableReferences() schema.Constraint {
+	return schema.Set{
+		Elem: schema.OneOf{
+			schema.Reference{OfScopeId: resourceScope},
+			schema.Reference{OfScopeId: dataScope},
+			schema.Reference{OfScopeId: moduleScope},
+		},
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/schemacontext"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// UnexpectedObjectAttribute reports keys of object expressions which
+// are not declared by the object constraint of the attribute, such as
+// unknown attributes in defaults of mock resources
+type UnexpectedObjectAttribute struct{}
+
+func (v UnexpectedObjectAttribute) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	if schemacontext.HasUnknownSchema(ctx) {
+		return ctx, diags
+	}
+
+	attr, ok := node.(*hclsyntax.Attribute)
+	if !ok {
+		return ctx, diags
+	}
+	attrSchema, ok := nodeSchema.(*schema.AttributeSchema)
+	if !ok {
+		return ctx, diags
+	}
+
+	return ctx, unexpectedObjectAttributes(attr.Expr, attrSchema.Constraint)
+}
+
+func unexpectedObjectAttributes(expr hclsyntax.Expression, constraint schema.Constraint) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	switch c := constraint.(type) {
+	case schema.List:
+		return unexpectedElemAttributes(expr, c.Elem)
+	case schema.Set:
+		return unexpectedElemAttributes(expr, c.Elem)
+	}
+
+	obj, ok := constraint.(schema.Object)
+	if !ok {
+		return diags
+	}
+	objExpr, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return diags
+	}
+
+	for _, item := range objExpr.Items {
+		key, keyDiags := item.KeyExpr.Value(nil)
+		if keyDiags.HasErrors() || !key.IsKnown() || key.IsNull() || !key.Type().Equals(cty.String) {
+			// Keys which cannot be evaluated statically are not checked
+			continue
+		}
+		name := key.AsString()
+
+		attrSchema, ok := obj.Attributes[name]
+		if !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unexpected attribute",
+				Detail:   fmt.Sprintf("An attribute named %q is not expected here", name),
+				Subject:  item.KeyExpr.Range().Ptr(),
+			})
+			continue
+		}
+
+		diags = append(diags, unexpectedObjectAttributes(item.ValueExpr, attrSchema.Constraint)...)
+	}
+
+	return diags
+}
+
+func unexpectedElemAttributes(expr hclsyntax.Expression, elem schema.Constraint) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	tupleExpr, ok := expr.(*hclsyntax.TupleConsExpr)
+	if !ok {
+		return diags
+	}
+	for _, elemExpr := range tupleExpr.Exprs {
+		diags = append(diags, unexpectedObjectAttributes(elemExpr, elem)...)
+	}
+
+	return diags
+}
@@ -0,0 +1,290 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/tests/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/tests/jobs"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/protocol"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+func (f *TestsFeature) discover(path string, files []string) error {
+	for _, file := range files {
+		if ast.IsTestFilename(file) || ast.IsMockFilename(file) {
+			f.logger.Printf("discovered test file in %s", path)
+
+			err := f.store.AddIfNotExists(path)
+			if err != nil {
+				return err
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
+
+func (f *TestsFeature) didOpen(ctx context.Context, dir document.DirHandle, languageID string) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+	path := dir.Path()
+
+	// We need to decide if the path is relevant to us. It can be relevant because
+	// a) the walker discovered test files and created a state entry for them
+	// b) the opened file is a test file or a mock file
+	//
+	// Add to state if language ID matches
+	if lsp.IsValidTestLanguage(languageID) || lsp.IsValidMockLanguage(languageID) {
+		err := f.store.AddIfNotExists(path)
+		if err != nil {
+			return ids, err
+		}
+	}
+
+	// Schedule jobs if state entry exists
+	hasTestRecord := f.store.Exists(path)
+	if !hasTestRecord {
+		return ids, nil
+	}
+
+	// The module under test may not have any open files, so we make
+	// sure it gets indexed before the test files are decoded
+	modIds, err := f.moduleFeature.IndexModule(ctx, fdecoder.ModuleUnderTestPath(path))
+	if err != nil {
+		f.logger.Printf("failed to index module under test for %q: %s", path, err)
+	}
+	ids = append(ids, modIds...)
+
+	testIds, err := f.decodeTests(ctx, dir, false, modIds)
+	ids = append(ids, testIds...)
+	return ids, err
+}
+
+func (f *TestsFeature) didChange(ctx context.Context, dir document.DirHandle) (job.IDs, error) {
+	hasTestRecord := f.store.Exists(dir.Path())
+	if !hasTestRecord {
+		return job.IDs{}, nil
+	}
+
+	// Diagnostics produced by tofu test reflect the content
+	// as it was when the command ran, so they go stale on any change.
+	err := f.clearTofuTestDiagnostics(dir.Path())
+	if err != nil {
+		f.logger.Printf("failed to clear tofu test diagnostics for %q: %s", dir.Path(), err)
+	}
+
+	return f.decodeTests(ctx, dir, true, job.IDs{})
+}
+
+func (f *TestsFeature) clearTofuTestDiagnostics(testPath string) error {
+	record, err := f.store.TestRecordByPath(testPath)
+	if err != nil {
+		return err
+	}
+
+	if record.TestDiagnostics[globalAst.TofuTestSource].Count() == 0 {
+		return nil
+	}
+
+	return f.store.UpdateTestDiagnostics(testPath, globalAst.TofuTestSource, ast.TestDiags{})
+}
+
+func (f *TestsFeature) didChangeWatched(ctx context.Context, rawPath string, changeType protocol.FileChangeType, isDir bool) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	if changeType == protocol.Deleted {
+		// We don't know whether file or dir is being deleted
+		// 1st we just blindly try to look it up as a directory
+		hasTestRecord := f.store.Exists(rawPath)
+		if hasTestRecord {
+			f.removeIndexedTests(rawPath)
+			return ids, nil
+		}
+
+		// 2nd we try again assuming it is a file
+		parentDir := filepath.Dir(rawPath)
+		hasTestRecord = f.store.Exists(parentDir)
+		if !hasTestRecord {
+			// Nothing relevant found in the feature state
+			return ids, nil
+		}
+
+		// and check the parent directory still exists
+		fi, err := os.Stat(parentDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// if not, we remove the indexed tests
+				f.removeIndexedTests(rawPath)
+				return ids, nil
+			}
+			f.logger.Printf("error checking existence (%q deleted): %s", parentDir, err)
+			return ids, nil
+		}
+		if !fi.IsDir() {
+			// Should never happen
+			f.logger.Printf("error: %q (deleted) is not a directory", parentDir)
+			return ids, nil
+		}
+
+		// If the parent directory exists, we just need to
+		// check if the there are open documents for the path and the
+		// path is a test path. If so, we need to reparse the test files
+		dir := document.DirHandleFromPath(parentDir)
+		hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(dir)
+		if err != nil {
+			f.logger.Printf("error when checking for open documents in path (%q deleted): %s", rawPath, err)
+		}
+		if !hasOpenDocs {
+			return ids, nil
+		}
+
+		f.decodeTests(ctx, dir, true, job.IDs{})
+	}
+
+	if changeType == protocol.Changed {
+		docHandle := document.HandleFromPath(rawPath)
+		// Check if the there are open documents for the path and the
+		// path is a test path. If so, we need to reparse the test files
+		hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(docHandle.Dir)
+		if err != nil {
+			f.logger.Printf("error when checking for open documents in path (%q changed): %s", rawPath, err)
+		}
+		if !hasOpenDocs {
+			return ids, nil
+		}
+
+		hasTestRecord := f.store.Exists(docHandle.Dir.Path())
+		if !hasTestRecord {
+			return ids, nil
+		}
+
+		f.decodeTests(ctx, docHandle.Dir, true, job.IDs{})
+	}
+
+	if changeType == protocol.Created {
+		var dir document.DirHandle
+		if isDir {
+			dir = document.DirHandleFromPath(rawPath)
+		} else {
+			docHandle := document.HandleFromPath(rawPath)
+			dir = docHandle.Dir
+		}
+
+		// Check if the there are open documents for the path and the
+		// path is a test path. If so, we need to reparse the test files
+		hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(dir)
+		if err != nil {
+			f.logger.Printf("error when checking for open documents in path (%q changed): %s", rawPath, err)
+		}
+		if !hasOpenDocs {
+			return ids, nil
+		}
+
+		hasTestRecord := f.store.Exists(dir.Path())
+		if !hasTestRecord {
+			return ids, nil
+		}
+
+		f.decodeTests(ctx, dir, true, job.IDs{})
+	}
+
+	return ids, nil
+}
+
+func (f *TestsFeature) removeIndexedTests(rawPath string) {
+	modHandle := document.DirHandleFromPath(rawPath)
+
+	err := f.stateStore.JobStore.DequeueJobsForDir(modHandle)
+	if err != nil {
+		f.logger.Printf("failed to dequeue jobs for tests: %s", err)
+		return
+	}
+
+	err = f.store.Remove(rawPath)
+	if err != nil {
+		f.logger.Printf("failed to remove tests from state: %s", err)
+		return
+	}
+}
+
+func (f *TestsFeature) decodeTests(ctx context.Context, dir document.DirHandle, ignoreState bool, dependsOn job.IDs) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+	path := dir.Path()
+
+	parseId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return jobs.ParseTests(ctx, f.fs, f.store, path)
+		},
+		Type:        op.OpTypeParseTests.String(),
+		DependsOn:   dependsOn,
+		IgnoreState: ignoreState,
+	})
+	if err != nil {
+		return ids, err
+	}
+	ids = append(ids, parseId)
+
+	refTargetsId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return jobs.DecodeReferenceTargets(ctx, f.store, f.moduleFeature, f.stateStore.ProviderSchemas, path)
+		},
+		Type:        op.OpTypeDecodeTestReferenceTargets.String(),
+		DependsOn:   job.IDs{parseId},
+		IgnoreState: ignoreState,
+	})
+	if err != nil {
+		return ids, err
+	}
+	ids = append(ids, refTargetsId)
+
+	refOriginsId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return jobs.DecodeReferenceOrigins(ctx, f.store, f.moduleFeature, f.stateStore.ProviderSchemas, path)
+		},
+		Type:        op.OpTypeDecodeTestReferenceOrigins.String(),
+		DependsOn:   job.IDs{parseId},
+		IgnoreState: ignoreState,
+	})
+	if err != nil {
+		return ids, err
+	}
+	ids = append(ids, refOriginsId)
+
+	validationOptions, err := lsctx.ValidationOptions(ctx)
+	if err != nil {
+		return ids, err
+	}
+	if validationOptions.EnableEnhancedValidation {
+		_, err = f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+			Dir: dir,
+			Func: func(ctx context.Context) error {
+				return jobs.SchemaTestValidation(ctx, f.store, f.moduleFeature, f.stateStore.ProviderSchemas, path)
+			},
+			Type:        op.OpTypeSchemaTestValidation.String(),
+			DependsOn:   job.IDs{parseId},
+			IgnoreState: ignoreState,
+		})
+		if err != nil {
+			return ids, err
+		}
+	}
+
+	return ids, nil
+}
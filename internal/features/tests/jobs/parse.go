@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"path/filepath"
+
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	"github.com/opentofu/tofu-ls/internal/features/tests/parser"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+// ParseTests parses the test configuration,
+// i.e. turns bytes of `*.tftest.hcl` and `*.tfmock.hcl` files into AST ([*hcl.File]).
+func ParseTests(ctx context.Context, fs ReadOnlyFS, testStore *state.TestStore, testPath string) error {
+	record, err := testStore.TestRecordByPath(testPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid parsing if it is already in progress or already known
+	if record.TestDiagnosticsState[globalAst.HCLParsingSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(testPath)}
+	}
+
+	var files ast.TestFiles
+	var diags ast.TestDiags
+	rpcContext := lsctx.DocumentContext(ctx)
+	// Only parse the file that's being changed/opened, unless this is 1st-time parsing
+	if record.TestDiagnosticsState[globalAst.HCLParsingSource] == op.OpStateLoaded && rpcContext.IsDidChangeRequest() && (ilsp.IsValidTestLanguage(rpcContext.LanguageID) || ilsp.IsValidMockLanguage(rpcContext.LanguageID)) {
+		// the file has already been parsed, so only examine this file and not the whole directory
+		err = testStore.SetTestDiagnosticsState(testPath, globalAst.HCLParsingSource, op.OpStateLoading)
+		if err != nil {
+			return err
+		}
+
+		filePath, err := uri.PathFromURI(rpcContext.URI)
+		if err != nil {
+			return err
+		}
+		fileName := filepath.Base(filePath)
+
+		f, tDiags, err := parser.ParseTestFile(fs, filePath)
+		if err != nil {
+			return err
+		}
+
+		existingFiles := record.ParsedTestFiles.Copy()
+		existingFiles[ast.TestFilename(fileName)] = f
+		files = existingFiles
+
+		existingDiags, ok := record.TestDiagnostics[globalAst.HCLParsingSource]
+		if !ok {
+			existingDiags = make(ast.TestDiags)
+		} else {
+			existingDiags = existingDiags.Copy()
+		}
+		existingDiags[ast.TestFilename(fileName)] = tDiags
+		diags = existingDiags
+	} else {
+		// this is the first time file is opened so parse the whole directory
+		err = testStore.SetTestDiagnosticsState(testPath, globalAst.HCLParsingSource, op.OpStateLoading)
+		if err != nil {
+			return err
+		}
+
+		files, diags, err = parser.ParseTestFiles(fs, testPath)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	sErr := testStore.UpdateParsedTestFiles(testPath, files, err)
+	if sErr != nil {
+		return sErr
+	}
+
+	sErr = testStore.UpdateTestDiagnostics(testPath, globalAst.HCLParsingSource, diags)
+	if sErr != nil {
+		return sErr
+	}
+
+	return err
+}
@@ -0,0 +1,95 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/filesystem"
+	"github.com/opentofu/tofu-ls/internal/job"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+func TestParseTests(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := state.NewTestStore(gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFs := filesystem.NewFilesystem(gs.DocumentStore)
+
+	testPath := filepath.Join(testData, "single-file-change-module", "tests")
+
+	err = ts.Add(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseTests(ctx, testFs, ts, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := ts.TestRecordByPath(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(before.ParsedTestFiles) != 2 {
+		t.Fatalf("expected 2 parsed files, given %d", len(before.ParsedTestFiles))
+	}
+
+	// ignore job state
+	ctx = job.WithIgnoreState(ctx, true)
+
+	// say we're coming from did_change request
+	filePath := filepath.Join(testPath, "example.tftest.hcl")
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{
+		Method:     "textDocument/didChange",
+		LanguageID: ilsp.OpenTofuTest.String(),
+		URI:        uri.FromPath(filePath),
+	})
+	err = ParseTests(ctx, testFs, ts, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := ts.TestRecordByPath(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// example.tftest.hcl should not be the same as first seen
+	if before.ParsedTestFiles["example.tftest.hcl"] == after.ParsedTestFiles["example.tftest.hcl"] {
+		t.Fatal("file should mismatch")
+	}
+
+	if before.ParsedTestFiles["nochange.tftest.hcl"] != after.ParsedTestFiles["nochange.tftest.hcl"] {
+		t.Fatal("unchanged file should match")
+	}
+
+	afterDiags := after.TestDiagnostics[globalAst.HCLParsingSource]
+	if _, ok := afterDiags[ast.TestFilename("example.tftest.hcl")]; !ok {
+		t.Fatal("expected diagnostics to be tracked for example.tftest.hcl")
+	}
+}
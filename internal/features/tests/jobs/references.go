@@ -0,0 +1,129 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	"github.com/opentofu/tofu-ls/internal/document"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/tests/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// DecodeReferenceTargets collects reference targets declared
+// in test files, using previously parsed AST (via [ParseTests]).
+//
+// For example it tells us that the foo attribute of a file level
+// variables block can be referred to as var.foo.
+func DecodeReferenceTargets(ctx context.Context, testStore *state.TestStore, moduleFeature fdecoder.ModuleReader, schemaReader fdecoder.SchemaReader, testPath string) error {
+	record, err := testStore.TestRecordByPath(testPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid collection if it is already in progress or already done
+	if record.RefTargetsState != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(testPath)}
+	}
+
+	err = testStore.SetReferenceTargetsState(testPath, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:  testStore,
+		ModuleReader: moduleFeature,
+		SchemaReader: schemaReader,
+	})
+	d.SetContext(idecoder.DecoderContext(ctx))
+
+	testDecoder, err := d.Path(lang.Path{
+		Path:       testPath,
+		LanguageID: ilsp.OpenTofuTest.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	targets, rErr := testDecoder.CollectReferenceTargets()
+
+	sErr := testStore.UpdateReferenceTargets(testPath, targets, rErr)
+	if sErr != nil {
+		return sErr
+	}
+
+	return rErr
+}
+
+// DecodeReferenceOrigins collects reference origins within test and mock files,
+// using previously parsed AST (via [ParseTests]).
+//
+// For example it tells us that there is a reference address
+// run.setup.name at a particular LOC, which can be later matched
+// with targets of the test files and the module under test.
+func DecodeReferenceOrigins(ctx context.Context, testStore *state.TestStore, moduleFeature fdecoder.ModuleReader, schemaReader fdecoder.SchemaReader, testPath string) error {
+	record, err := testStore.TestRecordByPath(testPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid collection if it is already in progress or already done
+	if record.RefOriginsState != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(testPath)}
+	}
+
+	err = testStore.SetReferenceOriginsState(testPath, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:  testStore,
+		ModuleReader: moduleFeature,
+		SchemaReader: schemaReader,
+	})
+	d.SetContext(idecoder.DecoderContext(ctx))
+
+	testDecoder, err := d.Path(lang.Path{
+		Path:       testPath,
+		LanguageID: ilsp.OpenTofuTest.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	origins, rErr := testDecoder.CollectReferenceOrigins()
+
+	// Mock files may refer to the module under test via overrides
+	if rErr == nil && record.ParsedTestFiles.HasMockFiles() {
+		mockDecoder, err := d.Path(lang.Path{
+			Path:       testPath,
+			LanguageID: ilsp.OpenTofuMock.String(),
+		})
+		if err != nil {
+			return err
+		}
+
+		var mockOrigins reference.Origins
+		mockOrigins, rErr = mockDecoder.CollectReferenceOrigins()
+		origins = append(origins, mockOrigins...)
+	}
+
+	sErr := testStore.UpdateReferenceOrigins(testPath, origins, rErr)
+	if sErr != nil {
+		return sErr
+	}
+
+	return rErr
+}
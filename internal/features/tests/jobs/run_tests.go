@@ -0,0 +1,266 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/tests/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// TestExecutor runs tofu test for the given test files (relative
+// to the working directory) and writes its machine-readable
+// output to w as it is produced
+type TestExecutor interface {
+	Test(ctx context.Context, w io.Writer, filters ...string) error
+}
+
+// TestStatus is the status of a run block or a test file
+// as reported by tofu test, e.g. "pass" or "fail"
+type TestStatus string
+
+const (
+	TestStatusPending TestStatus = "pending"
+	TestStatusSkip    TestStatus = "skip"
+	TestStatusPass    TestStatus = "pass"
+	TestStatusFail    TestStatus = "fail"
+	TestStatusError   TestStatus = "error"
+)
+
+var testStatusPrecedence = map[TestStatus]int{
+	TestStatusPending: 0,
+	TestStatusSkip:    1,
+	TestStatusPass:    2,
+	TestStatusFail:    3,
+	TestStatusError:   4,
+}
+
+// Merge returns the more severe of both statuses,
+// the same way tofu test aggregates statuses of runs
+func (s TestStatus) Merge(other TestStatus) TestStatus {
+	if testStatusPrecedence[other] > testStatusPrecedence[s] {
+		return other
+	}
+	return s
+}
+
+// TestRun is the result of a single run block
+type TestRun struct {
+	Name   string
+	Status TestStatus
+}
+
+type TestRuns []TestRun
+
+// Status returns the aggregated status of all runs
+func (tr TestRuns) Status() TestStatus {
+	status := TestStatusPending
+	for _, run := range tr {
+		status = status.Merge(run.Status)
+	}
+	return status
+}
+
+// testMessage represents a single line of the
+// machine-readable output of tofu test
+type testMessage struct {
+	Type     string `json:"type"`
+	TestFile string `json:"@testfile"`
+	TestRun  string `json:"@testrun"`
+
+	Run *struct {
+		Path   string     `json:"path"`
+		Run    string     `json:"run"`
+		Status TestStatus `json:"status"`
+	} `json:"test_run"`
+	Diagnostic *tfjson.Diagnostic `json:"diagnostic"`
+	Summary    *struct {
+		Status TestStatus `json:"status"`
+	} `json:"test_summary"`
+}
+
+// testRunExtra is attached to diagnostics produced by tofu test,
+// so results of individual run blocks can be replaced
+type testRunExtra struct {
+	run string
+}
+
+// TofuTest runs tofu test in the module under test of the given
+// directory and records diagnostics reported for the given test file,
+// such as failed assertions, which point to the condition of the assertion.
+// Diagnostics without a location in the file point to the run block.
+//
+// Only the given test file is run. tofu test cannot run a single run block,
+// so if runName is set, the whole file is still run, but only results of that
+// run block are recorded, keeping results of other runs from previous
+// invocations. onRun is called for every finished run.
+func TofuTest(ctx context.Context, testStore *state.TestStore, tfExec TestExecutor, testPath, filename, runName string, onRun func(TestRun)) (TestRuns, error) {
+	record, err := testStore.TestRecordByPath(testPath)
+	if err != nil {
+		return nil, err
+	}
+	file := record.ParsedTestFiles[ast.TestFilename(filename)]
+
+	err = testStore.SetTestDiagnosticsState(testPath, globalAst.TofuTestSource, op.OpStateLoading)
+	if err != nil {
+		return nil, err
+	}
+
+	modPath := fdecoder.ModuleUnderTestPath(testPath)
+	filePath := filepath.Join(testPath, filename)
+	isTestFile := func(testFile string) bool {
+		return testFile != "" && filepath.Join(modPath, filepath.FromSlash(testFile)) == filePath
+	}
+
+	runs := make(TestRuns, 0)
+	diags := make(hcl.Diagnostics, 0)
+	var summary bool
+	var errDiags hcl.Diagnostics
+
+	w := &lineWriter{handle: func(line []byte) {
+		var msg testMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			// Not every line is necessarily a JSON message
+			return
+		}
+
+		switch {
+		case msg.Type == "test_summary" && msg.Summary != nil:
+			summary = true
+		case msg.Type == "test_run" && msg.Run != nil:
+			if !isTestFile(msg.Run.Path) || (runName != "" && msg.Run.Run != runName) {
+				return
+			}
+			if msg.Run.Status == TestStatusPending {
+				return
+			}
+			run := TestRun{Name: msg.Run.Run, Status: msg.Run.Status}
+			runs = append(runs, run)
+			if onRun != nil {
+				onRun(run)
+			}
+		case msg.Type == "diagnostic" && msg.Diagnostic != nil:
+			if !isTestFile(msg.TestFile) {
+				if msg.TestFile == "" && msg.Diagnostic.Severity == tfjson.DiagnosticSeverityError {
+					errDiags = append(errDiags, testDiagnostic(msg.Diagnostic, nil, ""))
+				}
+				return
+			}
+			if runName != "" && msg.TestRun != runName {
+				return
+			}
+			diags = append(diags, testDiagnostic(msg.Diagnostic, testSubject(msg, modPath, filePath, filename, file), msg.TestRun))
+		}
+	}}
+
+	relPath, err := filepath.Rel(modPath, filePath)
+	if err != nil {
+		testStore.SetTestDiagnosticsState(testPath, globalAst.TofuTestSource, op.OpStateUnknown)
+		return nil, err
+	}
+
+	err = tfExec.Test(ctx, w, filepath.ToSlash(relPath))
+	if err != nil && (!summary || ctx.Err() != nil) {
+		// tofu test exits with an error when any test fails, which
+		// is only an actual error if the tests did not finish
+		if errDiags.HasErrors() {
+			err = fmt.Errorf("%s: %w", errDiags.Errs()[0].Error(), err)
+		}
+		testStore.SetTestDiagnosticsState(testPath, globalAst.TofuTestSource, op.OpStateUnknown)
+		return runs, err
+	}
+
+	testDiags := record.TestDiagnostics[globalAst.TofuTestSource].Copy()
+	if runName != "" {
+		for _, diag := range testDiags[ast.TestFilename(filename)] {
+			extra, ok := hcl.DiagnosticExtra[testRunExtra](diag)
+			if !ok || extra.run != runName {
+				diags = append(diags, diag)
+			}
+		}
+	}
+	testDiags[ast.TestFilename(filename)] = diags
+
+	return runs, testStore.UpdateTestDiagnostics(testPath, globalAst.TofuTestSource, testDiags)
+}
+
+func testDiagnostic(d *tfjson.Diagnostic, subject *hcl.Range, runName string) *hcl.Diagnostic {
+	severity := hcl.DiagError
+	if d.Severity == tfjson.DiagnosticSeverityWarning {
+		severity = hcl.DiagWarning
+	}
+
+	return &hcl.Diagnostic{
+		Severity: severity,
+		Summary:  d.Summary,
+		Detail:   d.Detail,
+		Subject:  subject,
+		Extra:    testRunExtra{run: runName},
+	}
+}
+
+// testSubject returns the range of the diagnostic within the test file,
+// or the range of the run block (or the start of the file) if the
+// diagnostic refers to another file, such as the module under test.
+func testSubject(msg testMessage, modPath, filePath, filename string, file *hcl.File) *hcl.Range {
+	d := msg.Diagnostic
+	if d.Range != nil && filepath.Join(modPath, filepath.FromSlash(d.Range.Filename)) == filePath {
+		return &hcl.Range{
+			Filename: filename,
+			Start:    hcl.Pos(d.Range.Start),
+			End:      hcl.Pos(d.Range.End),
+		}
+	}
+
+	if rng, ok := runBlockRange(file, msg.TestRun); ok {
+		return rng.Ptr()
+	}
+
+	return &hcl.Range{
+		Filename: filename,
+		Start:    hcl.InitialPos,
+		End:      hcl.InitialPos,
+	}
+}
+
+func runBlockRange(file *hcl.File, runName string) (hcl.Range, bool) {
+	for _, block := range fdecoder.RunBlocks(file) {
+		if block.Labels[0] == runName {
+			return block.DefRange, true
+		}
+	}
+	return hcl.Range{}, false
+}
+
+// lineWriter calls handle for every complete line written to it
+type lineWriter struct {
+	buf    []byte
+	handle func(line []byte)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.handle(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
@@ -0,0 +1,185 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/hcl/v2"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/filesystem"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+)
+
+func TestTofuTest(t *testing.T) {
+	modPath, ts := testTofuTestStore(t)
+	testPath := filepath.Join(modPath, "tests")
+	tfExec := stubTofuExecutor(t, modPath, filepath.Join(modPath, "test-output.jsonl"))
+
+	ctx := context.Background()
+	reported := make(TestRuns, 0)
+	runs, err := TofuTest(ctx, ts, tfExec, testPath, "main.tftest.hcl", "", func(run TestRun) {
+		reported = append(reported, run)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRuns := TestRuns{
+		{Name: "first", Status: TestStatusPass},
+		{Name: "second", Status: TestStatusFail},
+	}
+	if diff := cmp.Diff(expectedRuns, runs); diff != "" {
+		t.Fatalf("unexpected runs: %s", diff)
+	}
+	if diff := cmp.Diff(expectedRuns, reported); diff != "" {
+		t.Fatalf("unexpected reported runs: %s", diff)
+	}
+	if runs.Status() != TestStatusFail {
+		t.Fatalf("expected status %q, given %q", TestStatusFail, runs.Status())
+	}
+
+	expectedDiags := hcl.Diagnostics{
+		{
+			Severity: hcl.DiagWarning,
+			Summary:  "Deprecated provider",
+			Detail:   "The provider is deprecated.",
+			Subject: &hcl.Range{
+				Filename: "main.tftest.hcl",
+				Start:    hcl.Pos{Line: 5, Column: 1, Byte: 30},
+				End:      hcl.Pos{Line: 5, Column: 12, Byte: 41},
+			},
+		},
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Test assertion failed",
+			Detail:   "unexpected name",
+			Subject: &hcl.Range{
+				Filename: "main.tftest.hcl",
+				Start:    hcl.Pos{Line: 14, Column: 21, Byte: 187},
+				End:      hcl.Pos{Line: 14, Column: 41, Byte: 207},
+			},
+		},
+	}
+	assertTofuTestDiags(t, ts, testPath, expectedDiags)
+
+	// Running a single run block keeps results of other runs
+	runs, err = TofuTest(ctx, ts, tfExec, testPath, "main.tftest.hcl", "second", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRuns = TestRuns{
+		{Name: "second", Status: TestStatusFail},
+	}
+	if diff := cmp.Diff(expectedRuns, runs); diff != "" {
+		t.Fatalf("unexpected runs: %s", diff)
+	}
+	assertTofuTestDiags(t, ts, testPath, expectedDiags)
+}
+
+func TestTofuTest_error(t *testing.T) {
+	modPath, ts := testTofuTestStore(t)
+	testPath := filepath.Join(modPath, "tests")
+	tfExec := stubTofuExecutor(t, modPath, filepath.Join(modPath, "init-error-output.jsonl"))
+
+	_, err := TofuTest(context.Background(), ts, tfExec, testPath, "main.tftest.hcl", "", nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "Inconsistent dependency lock file") {
+		t.Fatalf("expected error to contain the diagnostic, given: %s", err)
+	}
+
+	record, err := ts.TestRecordByPath(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := record.TestDiagnostics[globalAst.TofuTestSource].Count(); count != 0 {
+		t.Fatalf("expected no diagnostics, given %d", count)
+	}
+}
+
+func testTofuTestStore(t *testing.T) (string, *state.TestStore) {
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := state.NewTestStore(gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modPath, err := filepath.Abs(filepath.Join("testdata", "tofu-test-module"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testPath := filepath.Join(modPath, "tests")
+	err = ts.Add(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := lsctx.WithDocumentContext(context.Background(), lsctx.Document{})
+	err = ParseTests(ctx, filesystem.NewFilesystem(gs.DocumentStore), ts, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return modPath, ts
+}
+
+// stubTofuExecutor returns an executor running a stub
+// tofu binary, which prints the given file and fails
+// like tofu test does when any of the tests fail
+func stubTofuExecutor(t *testing.T, workDir, outputPath string) exec.TofuExecutor {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub binary requires a POSIX shell")
+	}
+
+	execPath := filepath.Join(t.TempDir(), "tofu")
+	script := fmt.Sprintf("#!/bin/sh\ncat %q\nexit 1\n", outputPath)
+	err := os.WriteFile(execPath, []byte(script), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := exec.NewExecutor(workDir, execPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func assertTofuTestDiags(t *testing.T, ts *state.TestStore, testPath string, expectedDiags hcl.Diagnostics) {
+	t.Helper()
+
+	record, err := ts.TestRecordByPath(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diags := record.TestDiagnostics[globalAst.TofuTestSource]["main.tftest.hcl"]
+	if diff := cmp.Diff(expectedDiags, diags, cmpopts.IgnoreFields(hcl.Diagnostic{}, "Extra"), sortDiags); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+	if len(record.TestDiagnostics[globalAst.TofuTestSource]) != 1 {
+		t.Fatalf("expected diagnostics for a single file, given: %#v", record.TestDiagnostics[globalAst.TofuTestSource])
+	}
+}
+
+var sortDiags = cmpopts.SortSlices(func(a, b *hcl.Diagnostic) bool {
+	return a.Subject.Start.Byte < b.Subject.Start.Byte
+})
@@ -0,0 +1,3 @@
+run "setup" {
+  command = plan
+}
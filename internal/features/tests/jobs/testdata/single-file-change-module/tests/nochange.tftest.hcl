@@ -0,0 +1,6 @@
+run "check" {
+  assert {
+    condition     = true
+    error_message = "unexpected"
+  }
+}
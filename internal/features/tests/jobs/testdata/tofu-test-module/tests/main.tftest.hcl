@@ -0,0 +1,17 @@
+variables {
+  name = "foo"
+}
+
+run "first" {
+  assert {
+    condition     = output.name == "foo"
+    error_message = "unexpected name"
+  }
+}
+
+run "second" {
+  assert {
+    condition     = output.name == "bar"
+    error_message = "unexpected name"
+  }
+}
@@ -0,0 +1,133 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/tests/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// SchemaTestValidation does schema-based validation
+// of test files (*.tftest.hcl) and mock files (*.tfmock.hcl) and produces diagnostics
+// associated with any "invalid" parts of code.
+//
+// It relies on previously parsed AST (via [ParseTests])
+// and the metadata of the module under test.
+func SchemaTestValidation(ctx context.Context, testStore *state.TestStore, moduleFeature fdecoder.ModuleReader, schemaReader fdecoder.SchemaReader, testPath string) error {
+	record, err := testStore.TestRecordByPath(testPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid validation if it is already in progress or already finished
+	if record.TestDiagnosticsState[globalAst.SchemaValidationSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(testPath)}
+	}
+
+	err = testStore.SetTestDiagnosticsState(testPath, globalAst.SchemaValidationSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	// We only wait a short period for the module under test to become ready
+	// If we have to cancel the validation, we will just run it after the next change.
+	// Tests of an unknown module are validated against the static schema only.
+	timer := time.NewTimer(2 * time.Second)
+	defer timer.Stop()
+	modDir := document.DirHandleFromPath(fdecoder.ModuleUnderTestPath(testPath))
+	wCh, moduleReady, err := moduleFeature.MetadataReady(modDir)
+	if err == nil && !moduleReady {
+		select {
+		// Wait for module to be ready
+		case <-wCh:
+		// or for the remaining time to pass
+		case <-timer.C:
+		// or context cancellation
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:  testStore,
+		ModuleReader: moduleFeature,
+		SchemaReader: schemaReader,
+	})
+	d.SetContext(idecoder.DecoderContext(ctx))
+
+	testDecoder, err := d.Path(lang.Path{
+		Path:       testPath,
+		LanguageID: ilsp.OpenTofuTest.String(),
+	})
+	if err != nil {
+		return err
+	}
+	mockDecoder, err := d.Path(lang.Path{
+		Path:       testPath,
+		LanguageID: ilsp.OpenTofuMock.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var rErr error
+	rpcContext := lsctx.DocumentContext(ctx)
+	if rpcContext.Method == "textDocument/didChange" &&
+		(ilsp.IsValidTestLanguage(rpcContext.LanguageID) || ilsp.IsValidMockLanguage(rpcContext.LanguageID)) {
+		filename := path.Base(rpcContext.URI)
+		// We only revalidate a single file that changed
+		var fileDiags hcl.Diagnostics
+		if ast.IsMockFilename(filename) {
+			fileDiags, rErr = mockDecoder.ValidateFile(ctx, filename)
+		} else {
+			fileDiags, rErr = testDecoder.ValidateFile(ctx, filename)
+		}
+
+		testDiags, ok := record.TestDiagnostics[globalAst.SchemaValidationSource]
+		if !ok {
+			testDiags = make(ast.TestDiags)
+		} else {
+			testDiags = testDiags.Copy()
+		}
+		testDiags[ast.TestFilename(filename)] = fileDiags
+
+		sErr := testStore.UpdateTestDiagnostics(testPath, globalAst.SchemaValidationSource, testDiags)
+		if sErr != nil {
+			return sErr
+		}
+	} else {
+		// We validate the whole directory, e.g. on open
+		var diags lang.DiagnosticsMap
+		diags, rErr = testDecoder.Validate(ctx)
+		if rErr == nil {
+			var mockDiags lang.DiagnosticsMap
+			mockDiags, rErr = mockDecoder.Validate(ctx)
+			diags = diags.Extend(mockDiags)
+		}
+
+		sErr := testStore.UpdateTestDiagnostics(testPath, globalAst.SchemaValidationSource, ast.TestDiagsFromMap(diags))
+		if sErr != nil {
+			return sErr
+		}
+	}
+
+	return rErr
+}
@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// TestRecord contains all information about test files
+// we have for a certain path
+type TestRecord struct {
+	path string
+
+	RefTargets      reference.Targets
+	RefTargetsErr   error
+	RefTargetsState op.OpState
+
+	RefOrigins      reference.Origins
+	RefOriginsErr   error
+	RefOriginsState op.OpState
+
+	ParsedTestFiles ast.TestFiles
+	TestParsingErr  error
+
+	TestDiagnostics      ast.SourceTestDiags
+	TestDiagnosticsState globalAst.DiagnosticSourceState
+}
+
+func (r *TestRecord) Copy() *TestRecord {
+	if r == nil {
+		return nil
+	}
+
+	newRecord := &TestRecord{
+		path: r.path,
+
+		RefTargets:      r.RefTargets.Copy(),
+		RefTargetsErr:   r.RefTargetsErr,
+		RefTargetsState: r.RefTargetsState,
+
+		RefOrigins:      r.RefOrigins.Copy(),
+		RefOriginsErr:   r.RefOriginsErr,
+		RefOriginsState: r.RefOriginsState,
+
+		TestParsingErr: r.TestParsingErr,
+
+		TestDiagnosticsState: r.TestDiagnosticsState.Copy(),
+	}
+
+	if r.ParsedTestFiles != nil {
+		newRecord.ParsedTestFiles = make(ast.TestFiles, len(r.ParsedTestFiles))
+		for name, f := range r.ParsedTestFiles {
+			// hcl.File is practically immutable once it comes out of parser
+			newRecord.ParsedTestFiles[name] = f
+		}
+	}
+
+	if r.TestDiagnostics != nil {
+		newRecord.TestDiagnostics = make(ast.SourceTestDiags, len(r.TestDiagnostics))
+
+		for source, testDiags := range r.TestDiagnostics {
+			newRecord.TestDiagnostics[source] = make(ast.TestDiags, len(testDiags))
+
+			for name, diags := range testDiags {
+				newRecord.TestDiagnostics[source][name] = make(hcl.Diagnostics, len(diags))
+				copy(newRecord.TestDiagnostics[source][name], diags)
+			}
+		}
+	}
+
+	return newRecord
+}
+
+func (r *TestRecord) Path() string {
+	return r.path
+}
+
+func newTestRecord(path string) *TestRecord {
+	return &TestRecord{
+		path:            path,
+		RefTargetsState: op.OpStateUnknown,
+		RefOriginsState: op.OpStateUnknown,
+		TestDiagnosticsState: globalAst.DiagnosticSourceState{
+			globalAst.HCLParsingSource:       op.OpStateUnknown,
+			globalAst.SchemaValidationSource: op.OpStateUnknown,
+			globalAst.TofuTestSource:         op.OpStateUnknown,
+		},
+	}
+}
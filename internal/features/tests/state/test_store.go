@@ -0,0 +1,397 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"log"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+type TestStore struct {
+	db        *memdb.MemDB
+	tableName string
+	logger    *log.Logger
+
+	changeStore *globalState.ChangeStore
+}
+
+func (s *TestStore) SetLogger(logger *log.Logger) {
+	s.logger = logger
+}
+
+func (s *TestStore) Add(path string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	err := s.add(txn, path)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+
+	return nil
+}
+
+func (s *TestStore) add(txn *memdb.Txn, path string) error {
+	obj, err := txn.First(s.tableName, "id", path)
+	if err != nil {
+		return err
+	}
+	if obj != nil {
+		return &globalState.AlreadyExistsError{
+			Idx: path,
+		}
+	}
+
+	record := newTestRecord(path)
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	err = s.queueRecordChange(nil, record)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *TestStore) AddIfNotExists(path string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	_, err := testRecordByPath(txn, path)
+	if err != nil {
+		if globalState.IsRecordNotFound(err) {
+			err := s.add(txn, path)
+			if err != nil {
+				return err
+			}
+			txn.Commit()
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *TestStore) Remove(path string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	oldObj, err := txn.First(s.tableName, "id", path)
+	if err != nil {
+		return err
+	}
+
+	if oldObj == nil {
+		// already removed
+		return nil
+	}
+
+	oldRecord := oldObj.(*TestRecord)
+	err = s.queueRecordChange(oldRecord, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = txn.DeleteAll(s.tableName, "id", path)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *TestStore) List() ([]*TestRecord, error) {
+	txn := s.db.Txn(false)
+
+	it, err := txn.Get(s.tableName, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*TestRecord, 0)
+	for item := it.Next(); item != nil; item = it.Next() {
+		record := item.(*TestRecord)
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func (s *TestStore) Exists(path string) bool {
+	txn := s.db.Txn(false)
+
+	obj, err := txn.First(s.tableName, "id", path)
+	if err != nil {
+		return false
+	}
+
+	return obj != nil
+}
+
+func (s *TestStore) TestRecordByPath(path string) (*TestRecord, error) {
+	txn := s.db.Txn(false)
+
+	record, err := testRecordByPath(txn, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func testRecordByPath(txn *memdb.Txn, path string) (*TestRecord, error) {
+	obj, err := txn.First(testTableName, "id", path)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, &globalState.RecordNotFoundError{
+			Source: path,
+		}
+	}
+	return obj.(*TestRecord), nil
+}
+
+func testRecordCopyByPath(txn *memdb.Txn, path string) (*TestRecord, error) {
+	record, err := testRecordByPath(txn, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return record.Copy(), nil
+}
+
+func (s *TestStore) UpdateParsedTestFiles(path string, tFiles ast.TestFiles, tErr error) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	record, err := testRecordCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record.ParsedTestFiles = tFiles
+	record.TestParsingErr = tErr
+
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *TestStore) UpdateTestDiagnostics(path string, source globalAst.DiagnosticSource, diags ast.TestDiags) error {
+	txn := s.db.Txn(true)
+	txn.Defer(func() {
+		s.SetTestDiagnosticsState(path, source, op.OpStateLoaded)
+	})
+	defer txn.Abort()
+
+	oldRecord, err := testRecordByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record := oldRecord.Copy()
+	if record.TestDiagnostics == nil {
+		record.TestDiagnostics = make(ast.SourceTestDiags)
+	}
+	record.TestDiagnostics[source] = diags
+
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	err = s.queueRecordChange(oldRecord, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *TestStore) SetTestDiagnosticsState(path string, source globalAst.DiagnosticSource, state op.OpState) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	record, err := testRecordCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record.TestDiagnosticsState[source] = state
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *TestStore) SetReferenceTargetsState(path string, state op.OpState) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	record, err := testRecordCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record.RefTargetsState = state
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *TestStore) UpdateReferenceTargets(path string, refs reference.Targets, rErr error) error {
+	txn := s.db.Txn(true)
+	txn.Defer(func() {
+		s.SetReferenceTargetsState(path, op.OpStateLoaded)
+	})
+	defer txn.Abort()
+
+	oldRecord, err := testRecordByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record := oldRecord.Copy()
+	record.RefTargets = refs
+	record.RefTargetsErr = rErr
+
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	err = s.queueRecordChange(oldRecord, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *TestStore) SetReferenceOriginsState(path string, state op.OpState) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	record, err := testRecordCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record.RefOriginsState = state
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *TestStore) UpdateReferenceOrigins(path string, origins reference.Origins, roErr error) error {
+	txn := s.db.Txn(true)
+	txn.Defer(func() {
+		s.SetReferenceOriginsState(path, op.OpStateLoaded)
+	})
+	defer txn.Abort()
+
+	oldRecord, err := testRecordByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record := oldRecord.Copy()
+	record.RefOrigins = origins
+	record.RefOriginsErr = roErr
+
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	err = s.queueRecordChange(oldRecord, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *TestStore) queueRecordChange(oldRecord, newRecord *TestRecord) error {
+	changes := globalState.Changes{}
+
+	oldDiags, newDiags := 0, 0
+	if oldRecord != nil {
+		oldDiags = oldRecord.TestDiagnostics.Count()
+	}
+	if newRecord != nil {
+		newDiags = newRecord.TestDiagnostics.Count()
+	}
+	// Comparing diagnostics accurately could be expensive
+	// so we just treat any non-empty diags as a change
+	if oldDiags > 0 || newDiags > 0 {
+		changes.Diagnostics = true
+	}
+
+	oldOrigins, newOrigins := 0, 0
+	if oldRecord != nil {
+		oldOrigins = len(oldRecord.RefOrigins)
+	}
+	if newRecord != nil {
+		newOrigins = len(newRecord.RefOrigins)
+	}
+	if oldOrigins != newOrigins {
+		changes.ReferenceOrigins = true
+	}
+
+	oldTargets, newTargets := 0, 0
+	if oldRecord != nil {
+		oldTargets = len(oldRecord.RefTargets)
+	}
+	if newRecord != nil {
+		newTargets = len(newRecord.RefTargets)
+	}
+	if oldTargets != newTargets {
+		changes.ReferenceTargets = true
+	}
+
+	var dir document.DirHandle
+	if oldRecord != nil {
+		dir = document.DirHandleFromPath(oldRecord.Path())
+	} else {
+		dir = document.DirHandleFromPath(newRecord.Path())
+	}
+
+	return s.changeStore.QueueChange(dir, changes)
+}
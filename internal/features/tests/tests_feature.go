@@ -0,0 +1,175 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/tests/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/tests/jobs"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+// ModuleFeature provides access to the module under test,
+// which is indexed as soon as any of its test files is opened
+type ModuleFeature interface {
+	fdecoder.ModuleReader
+	IndexModule(ctx context.Context, modPath string) (job.IDs, error)
+}
+
+// TestsFeature groups everything related to tests. Its internal
+// state keeps track of all test files in the workspace.
+type TestsFeature struct {
+	store    *state.TestStore
+	eventbus *eventbus.EventBus
+	stopFunc context.CancelFunc
+	logger   *log.Logger
+
+	moduleFeature ModuleFeature
+	stateStore    *globalState.StateStore
+	fs            jobs.ReadOnlyFS
+}
+
+func NewTestsFeature(eventbus *eventbus.EventBus, stateStore *globalState.StateStore, fs jobs.ReadOnlyFS, moduleFeature ModuleFeature) (*TestsFeature, error) {
+	store, err := state.NewTestStore(stateStore.ChangeStore)
+	if err != nil {
+		return nil, err
+	}
+	discardLogger := log.New(io.Discard, "", 0)
+
+	return &TestsFeature{
+		store:         store,
+		eventbus:      eventbus,
+		stopFunc:      func() {},
+		logger:        discardLogger,
+		moduleFeature: moduleFeature,
+		stateStore:    stateStore,
+		fs:            fs,
+	}, nil
+}
+
+func (f *TestsFeature) SetLogger(logger *log.Logger) {
+	f.logger = logger
+	f.store.SetLogger(logger)
+}
+
+// Start starts the features separate goroutine.
+// It listens to various events from the EventBus and performs corresponding actions.
+func (f *TestsFeature) Start(ctx context.Context) {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	f.stopFunc = cancelFunc
+
+	discover := f.eventbus.OnDiscover("feature.tests", nil)
+
+	didOpenDone := make(chan struct{}, 10)
+	didOpen := f.eventbus.OnDidOpen("feature.tests", didOpenDone)
+
+	didChangeDone := make(chan struct{}, 10)
+	didChange := f.eventbus.OnDidChange("feature.tests", didChangeDone)
+
+	didChangeWatchedDone := make(chan struct{}, 10)
+	didChangeWatched := f.eventbus.OnDidChangeWatched("feature.tests", didChangeWatchedDone)
+
+	go func() {
+		for {
+			select {
+			case discover := <-discover:
+				// TODO? collect errors
+				f.discover(discover.Path, discover.Files)
+			case didOpen := <-didOpen:
+				// TODO? collect errors
+				f.didOpen(didOpen.Context, didOpen.Dir, didOpen.LanguageID)
+				didOpenDone <- struct{}{}
+			case didChange := <-didChange:
+				// TODO? collect errors
+				f.didChange(didChange.Context, didChange.Dir)
+				didChangeDone <- struct{}{}
+			case didChangeWatched := <-didChangeWatched:
+				// TODO? collect errors
+				f.didChangeWatched(didChangeWatched.Context, didChangeWatched.RawPath, didChangeWatched.ChangeType, didChangeWatched.IsDir)
+				didChangeWatchedDone <- struct{}{}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (f *TestsFeature) Stop() {
+	f.stopFunc()
+	f.logger.Print("stopped tests feature")
+}
+
+func (f *TestsFeature) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	pathReader := &fdecoder.PathReader{
+		StateReader:  f.store,
+		ModuleReader: f.moduleFeature,
+		SchemaReader: f.stateStore.ProviderSchemas,
+	}
+
+	return pathReader.PathContext(path)
+}
+
+func (f *TestsFeature) Paths(ctx context.Context) []lang.Path {
+	pathReader := &fdecoder.PathReader{
+		StateReader:  f.store,
+		ModuleReader: f.moduleFeature,
+		SchemaReader: f.stateStore.ProviderSchemas,
+	}
+
+	return pathReader.Paths(ctx)
+}
+
+// ParsedFile returns the parsed test file, regardless
+// of whether it could be decoded against the schema
+func (f *TestsFeature) ParsedFile(testPath string, filename string) (*hcl.File, bool) {
+	record, err := f.store.TestRecordByPath(testPath)
+	if err != nil {
+		return nil, false
+	}
+
+	file, ok := record.ParsedTestFiles[ast.TestFilename(filename)]
+	return file, ok && file != nil
+}
+
+func (f *TestsFeature) Diagnostics(path string) diagnostics.Diagnostics {
+	diags := diagnostics.NewDiagnostics()
+
+	record, err := f.store.TestRecordByPath(path)
+	if err != nil {
+		return diags
+	}
+
+	for source, td := range record.TestDiagnostics {
+		diags.Append(source, td.AsMap())
+	}
+
+	return diags
+}
+
+// AppendCodeLenses appends the code lenses running
+// tests of a test file via the tofu.test command
+func (f *TestsFeature) AppendCodeLenses(ctx context.Context, lenses []lang.CodeLensFunc) []lang.CodeLensFunc {
+	return append(lenses, runTestsLens())
+}
+
+// RunTests runs the tests of the given test file, or just the given
+// run block, via tofu test and records failed assertions and other
+// errors as diagnostics of the file. onRun is called for every finished run.
+func (f *TestsFeature) RunTests(ctx context.Context, tfExec jobs.TestExecutor, testPath, filename, runName string, onRun func(jobs.TestRun)) (jobs.TestRuns, error) {
+	return jobs.TofuTest(ctx, f.store, tfExec, testPath, filename, runName, onRun)
+}
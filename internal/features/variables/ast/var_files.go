@@ -0,0 +1,64 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"path/filepath"
+	"regexp"
+)
+
+// VarFilePattern maps variable definitions files matching a glob
+// pattern to the root module they are passed to via -var-file
+type VarFilePattern struct {
+	// Pattern is an absolute pattern as understood by [filepath.Match]
+	Pattern string
+	// ModulePath is the absolute path of the root module
+	ModulePath string
+}
+
+type VarFilePatterns []VarFilePattern
+
+// ModulePath returns the path of the root module
+// of the first pattern matching the given file path
+func (vp VarFilePatterns) ModulePath(filePath string) (string, bool) {
+	for _, p := range vp {
+		matched, err := filepath.Match(p.Pattern, filePath)
+		if err == nil && matched {
+			return p.ModulePath, true
+		}
+	}
+	return "", false
+}
+
+var moduleCommentRe = regexp.MustCompile(`(?m)^[ \t]*(?:#|//)[ \t]*tofu-ls:[ \t]*module[ \t]*=[ \t]*(\S+)[ \t]*$`)
+
+// ModuleComment returns the path of the root module declared via
+// a comment such as "# tofu-ls: module=../app" in the given source.
+// Relative paths are resolved relative to the directory of the file.
+func ModuleComment(dirPath string, src []byte) (string, bool) {
+	m := moduleCommentRe.FindSubmatch(src)
+	if m == nil {
+		return "", false
+	}
+
+	path := filepath.FromSlash(string(m[1]))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dirPath, path)
+	}
+	return filepath.Clean(path), true
+}
+
+// VarFileModules maps names of variable definitions files
+// to paths of root modules they are passed to via -var-file
+type VarFileModules map[VarsFilename]string
+
+func (vm VarFileModules) Copy() VarFileModules {
+	m := make(VarFileModules, len(vm))
+	for name, modPath := range vm {
+		m[name] = modPath
+	}
+	return m
+}
@@ -91,6 +91,18 @@ func (vd VarsDiags) AutoloadedOnly() VarsDiags {
 	return diags
 }
 
+// LoadedOnly returns diagnostics of files which OpenTofu loads,
+// either automatically or when passed via -var-file
+func (vd VarsDiags) LoadedOnly(modules VarFileModules) VarsDiags {
+	diags := make(VarsDiags)
+	for name, f := range vd {
+		if _, ok := modules[name]; ok || name.IsAutoloaded() {
+			diags[name] = f
+		}
+	}
+	return diags
+}
+
 func (vd VarsDiags) AsMap() map[string]hcl.Diagnostics {
 	m := make(map[string]hcl.Diagnostics, len(vd))
 	for name, diags := range vd {
@@ -42,3 +42,22 @@ func TestVarsDiags_autoloadedOnly(t *testing.T) {
 		t.Fatalf("unexpected diagnostics: %s", diff)
 	}
 }
+
+func TestVarsDiags_loadedOnly(t *testing.T) {
+	vd := VarsDiagsFromMap(map[string]hcl.Diagnostics{
+		"alpha.tfvars":     {},
+		"beta.tfvars":      {},
+		"gama.auto.tfvars": {},
+	})
+	diags := vd.LoadedOnly(VarFileModules{
+		"beta.tfvars": "/path/to/module",
+	}).AsMap()
+	expectedDiags := map[string]hcl.Diagnostics{
+		"beta.tfvars":      {},
+		"gama.auto.tfvars": {},
+	}
+
+	if diff := cmp.Diff(expectedDiags, diags, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
@@ -7,11 +7,13 @@ package decoder
 
 import (
 	"context"
+	"path/filepath"
 
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
 	tfmod "github.com/opentofu/opentofu-schema/module"
 	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/variables/ast"
 	"github.com/opentofu/tofu-ls/internal/features/variables/state"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 )
@@ -53,10 +55,23 @@ func (pr *PathReader) Paths(ctx context.Context) []lang.Path {
 }
 
 // PathContext returns a PathContext for the given path based on the language ID.
+//
+// The path is either a directory, whose context contains all its files,
+// or a file, whose context contains all files decoded against the same
+// root module as that file (see [FileModulePath]).
 func (pr *PathReader) PathContext(path lang.Path) (*decoder.PathContext, error) {
 	mod, err := pr.StateReader.VariableRecordByPath(path.Path)
-	if err != nil {
+	if err == nil {
+		return variablePathContext(mod, pr.ModuleReader, pr.UseAnySchema, mod.Path(), true)
+	}
+
+	name := filepath.Base(path.Path)
+	if !ast.IsVarsFilename(name) {
+		return nil, err
+	}
+	mod, dirErr := pr.StateReader.VariableRecordByPath(filepath.Dir(path.Path))
+	if dirErr != nil {
 		return nil, err
 	}
-	return variablePathContext(mod, pr.ModuleReader, pr.UseAnySchema)
+	return variablePathContext(mod, pr.ModuleReader, pr.UseAnySchema, FileModulePath(mod, ast.VarsFilename(name)), false)
 }
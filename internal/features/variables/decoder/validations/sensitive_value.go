@@ -0,0 +1,49 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// SensitiveValue reports values of sensitive variables in files other
+// than *.auto.tfvars, which are usually committed to version control
+type SensitiveValue struct{}
+
+func (v SensitiveValue) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	attr, ok := node.(*hclsyntax.Attribute)
+	if !ok {
+		return ctx, diags
+	}
+	attrSchema, ok := nodeSchema.(*schema.AttributeSchema)
+	if !ok || !attrSchema.IsSensitive {
+		return ctx, diags
+	}
+
+	filename := attr.SrcRange.Filename
+	if strings.HasSuffix(filename, ".auto.tfvars") || strings.HasSuffix(filename, ".auto.tfvars.json") {
+		return ctx, diags
+	}
+
+	diags = append(diags, &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Value for sensitive variable",
+		Detail: fmt.Sprintf("The variable %q is marked as sensitive. Consider passing its value "+
+			"via a *.auto.tfvars file excluded from version control or the TF_VAR_%s environment variable.",
+			attr.Name, attr.Name),
+		Subject: attr.SrcRange.Ptr(),
+	})
+
+	return ctx, diags
+}
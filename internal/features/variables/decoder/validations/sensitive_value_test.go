@@ -0,0 +1,44 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestSensitiveValue(t *testing.T) {
+	tests := []struct {
+		filename  string
+		sensitive bool
+		wantCount int
+	}{
+		{"prod.tfvars", true, 1},
+		{"terraform.tfvars", true, 1},
+		{"secrets.auto.tfvars", true, 0},
+		{"prod.tfvars", false, 0},
+	}
+
+	for _, tt := range tests {
+		f, diags := hclsyntax.ParseConfig([]byte(`password = "secret"`), tt.filename, hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		attr := f.Body.(*hclsyntax.Body).Attributes["password"]
+
+		_, diags = SensitiveValue{}.Visit(context.Background(), attr, &schema.AttributeSchema{
+			IsSensitive: tt.sensitive,
+		})
+		if len(diags) != tt.wantCount {
+			t.Fatalf("%s (sensitive: %t): expected %d diagnostics, %d given: %s",
+				tt.filename, tt.sensitive, tt.wantCount, len(diags), diags)
+		}
+	}
+}
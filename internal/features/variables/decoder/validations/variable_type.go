@@ -0,0 +1,198 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// VariableType reports values which cannot be converted to the type
+// constraint of the variable. Where possible, the diagnostic points
+// to the offending element nested within the value.
+type VariableType struct{}
+
+func (v VariableType) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	attr, ok := node.(*hclsyntax.Attribute)
+	if !ok {
+		return ctx, diags
+	}
+	attrSchema, ok := nodeSchema.(*schema.AttributeSchema)
+	if !ok {
+		return ctx, diags
+	}
+	constraint, ok := attrSchema.Constraint.(schema.LiteralType)
+	if !ok {
+		return ctx, diags
+	}
+
+	tc := typeChecker{name: attr.Name}
+	return ctx, tc.check(attr.Expr, constraint.Type, cty.Path{})
+}
+
+type typeChecker struct {
+	name string
+}
+
+func (tc typeChecker) check(expr hclsyntax.Expression, ty cty.Type, path cty.Path) hcl.Diagnostics {
+	if ty == cty.NilType || ty.Equals(cty.DynamicPseudoType) {
+		return nil
+	}
+
+	switch {
+	case ty.IsObjectType():
+		if objExpr, ok := expr.(*hclsyntax.ObjectConsExpr); ok {
+			return tc.checkObject(objExpr, ty, path)
+		}
+	case ty.IsMapType():
+		if objExpr, ok := expr.(*hclsyntax.ObjectConsExpr); ok {
+			return tc.checkMap(objExpr, ty.ElementType(), path)
+		}
+	case ty.IsListType() || ty.IsSetType():
+		if tupleExpr, ok := expr.(*hclsyntax.TupleConsExpr); ok {
+			var diags hcl.Diagnostics
+			for i, elemExpr := range tupleExpr.Exprs {
+				diags = append(diags, tc.check(elemExpr, ty.ElementType(), indexPath(path, cty.NumberIntVal(int64(i))))...)
+			}
+			return diags
+		}
+	case ty.IsTupleType():
+		tupleExpr, ok := expr.(*hclsyntax.TupleConsExpr)
+		if ok && len(tupleExpr.Exprs) == len(ty.TupleElementTypes()) {
+			var diags hcl.Diagnostics
+			for i, elemExpr := range tupleExpr.Exprs {
+				diags = append(diags, tc.check(elemExpr, ty.TupleElementType(i), indexPath(path, cty.NumberIntVal(int64(i))))...)
+			}
+			return diags
+		}
+	}
+
+	val, valDiags := expr.Value(nil)
+	if valDiags.HasErrors() {
+		// Values which cannot be evaluated statically are not checked
+		return nil
+	}
+
+	_, err := convert.Convert(val, ty)
+	if err != nil {
+		if pathErr, ok := err.(cty.PathError); ok {
+			return hcl.Diagnostics{tc.diagnostic(expr.Range(), append(path.Copy(), pathErr.Path...), pathErr.Error())}
+		}
+		return hcl.Diagnostics{tc.diagnostic(expr.Range(), path, err.Error())}
+	}
+
+	return nil
+}
+
+func (tc typeChecker) checkObject(expr *hclsyntax.ObjectConsExpr, ty cty.Type, path cty.Path) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	declared := make(map[string]bool)
+	allKeysKnown := true
+	for _, item := range expr.Items {
+		name, ok := objectKey(item)
+		if !ok {
+			allKeysKnown = false
+			continue
+		}
+		declared[name] = true
+
+		if !ty.HasAttribute(name) {
+			// Conversion discards attributes which are not part of the type
+			continue
+		}
+		diags = append(diags, tc.check(item.ValueExpr, ty.AttributeType(name), attrPath(path, name))...)
+	}
+
+	if !allKeysKnown {
+		return diags
+	}
+
+	missing := make([]string, 0)
+	for name := range ty.AttributeTypes() {
+		if !declared[name] && !ty.AttributeOptional(name) {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		diags = append(diags, tc.diagnostic(expr.Range(), path, fmt.Sprintf("attribute %q is required", name)))
+	}
+
+	return diags
+}
+
+func (tc typeChecker) checkMap(expr *hclsyntax.ObjectConsExpr, elemType cty.Type, path cty.Path) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, item := range expr.Items {
+		key, ok := objectKey(item)
+		if !ok {
+			continue
+		}
+		diags = append(diags, tc.check(item.ValueExpr, elemType, indexPath(path, cty.StringVal(key)))...)
+	}
+
+	return diags
+}
+
+func (tc typeChecker) diagnostic(rng hcl.Range, path cty.Path, msg string) *hcl.Diagnostic {
+	if len(path) > 0 {
+		msg = fmt.Sprintf("%s: %s", formatPath(path), msg)
+	}
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid value for input variable",
+		Detail:   fmt.Sprintf("The given value is not suitable for var.%s: %s.", tc.name, msg),
+		Subject:  rng.Ptr(),
+	}
+}
+
+func objectKey(item hclsyntax.ObjectConsItem) (string, bool) {
+	key, diags := item.KeyExpr.Value(nil)
+	if diags.HasErrors() || !key.IsKnown() || key.IsNull() || !key.Type().Equals(cty.String) {
+		return "", false
+	}
+	return key.AsString(), true
+}
+
+func attrPath(path cty.Path, name string) cty.Path {
+	return append(path.Copy(), cty.GetAttrStep{Name: name})
+}
+
+func indexPath(path cty.Path, key cty.Value) cty.Path {
+	return append(path.Copy(), cty.IndexStep{Key: key})
+}
+
+// formatPath formats the path the same way OpenTofu
+// formats paths of values which fail conversion
+func formatPath(path cty.Path) string {
+	steps := make([]string, 0, len(path))
+	for _, step := range path {
+		switch s := step.(type) {
+		case cty.GetAttrStep:
+			steps = append(steps, fmt.Sprintf("attribute %q", s.Name))
+		case cty.IndexStep:
+			switch {
+			case s.Key.Type().Equals(cty.Number):
+				steps = append(steps, fmt.Sprintf("element %s", s.Key.AsBigFloat().Text('f', -1)))
+			case s.Key.Type().Equals(cty.String):
+				steps = append(steps, fmt.Sprintf("element %q", s.Key.AsString()))
+			}
+		}
+	}
+	return strings.Join(steps, ": ")
+}
@@ -0,0 +1,113 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestVariableType(t *testing.T) {
+	serviceType := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"name":  cty.String,
+		"port":  cty.Number,
+		"proto": cty.String,
+	}, []string{"proto"})
+
+	tests := []struct {
+		name string
+		src  string
+		ty   cty.Type
+		want []string
+	}{
+		{
+			name: "valid value",
+			src:  `services = [{ name = "web", port = 80 }]`,
+			ty:   cty.List(serviceType),
+			want: []string{},
+		},
+		{
+			name: "convertible primitive",
+			src:  `port = "8080"`,
+			ty:   cty.Number,
+			want: []string{},
+		},
+		{
+			name: "string instead of list",
+			src:  `services = "web"`,
+			ty:   cty.List(serviceType),
+			want: []string{
+				"1:12-1:17: The given value is not suitable for var.services: list of object required, but have string.",
+			},
+		},
+		{
+			name: "nested element",
+			src:  `services = [{ name = "web", port = 80 }, { name = "db", port = "any" }]`,
+			ty:   cty.List(serviceType),
+			want: []string{
+				`1:64-1:69: The given value is not suitable for var.services: element 1: attribute "port": a number is required.`,
+			},
+		},
+		{
+			name: "missing required attribute",
+			src:  `services = [{ name = "web" }]`,
+			ty:   cty.List(serviceType),
+			want: []string{
+				`1:13-1:29: The given value is not suitable for var.services: element 0: attribute "port" is required.`,
+			},
+		},
+		{
+			name: "map element",
+			src:  `ports = { web = 80, db = [] }`,
+			ty:   cty.Map(cty.Number),
+			want: []string{
+				`1:26-1:28: The given value is not suitable for var.ports: element "db": number required, but have tuple.`,
+			},
+		},
+		{
+			name: "any type",
+			src:  `anything = [1, "two"]`,
+			ty:   cty.DynamicPseudoType,
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tt.src), "test.tfvars", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			body := f.Body.(*hclsyntax.Body)
+
+			got := make([]string, 0)
+			for _, attr := range body.Attributes {
+				_, diags := VariableType{}.Visit(context.Background(), attr, &schema.AttributeSchema{
+					Constraint: schema.LiteralType{Type: tt.ty},
+				})
+				for _, diag := range diags {
+					got = append(got, formatDiag(diag))
+				}
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("unexpected diagnostics: %s", diff)
+			}
+		})
+	}
+}
+
+func formatDiag(diag *hcl.Diagnostic) string {
+	return fmt.Sprintf("%d:%d-%d:%d: %s", diag.Subject.Start.Line, diag.Subject.Start.Column,
+		diag.Subject.End.Line, diag.Subject.End.Column, diag.Detail)
+}
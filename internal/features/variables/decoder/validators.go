@@ -7,9 +7,12 @@ package decoder
 
 import (
 	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/opentofu/tofu-ls/internal/features/variables/decoder/validations"
 )
 
 var varsValidators = []validator.Validator{
 	validator.UnexpectedAttribute{},
 	validator.UnexpectedBlock{},
+	validations.SensitiveValue{},
+	validations.VariableType{},
 }
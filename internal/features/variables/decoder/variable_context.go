@@ -6,23 +6,88 @@
 package decoder
 
 import (
+	"path/filepath"
+	"sort"
+
 	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
 	tfschema "github.com/opentofu/opentofu-schema/schema"
 	"github.com/opentofu/tofu-ls/internal/features/variables/ast"
 	"github.com/opentofu/tofu-ls/internal/features/variables/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 )
 
-func variablePathContext(mod *state.VariableRecord, moduleReader ModuleReader, useAnySchema bool) (*decoder.PathContext, error) {
-	variables, _ := moduleReader.ModuleInputs(mod.Path())
+// FileModulePath returns the path of the root module whose variables
+// the given file of the record is decoded against, i.e. the module
+// the file is mapped to via -var-file or the directory of the file.
+func FileModulePath(mod *state.VariableRecord, name ast.VarsFilename) string {
+	if modPath, ok := mod.VarFileModules[name]; ok {
+		return modPath
+	}
+	return mod.Path()
+}
+
+// ModulePaths returns paths of all root modules
+// the files of the given record are decoded against
+func ModulePaths(mod *state.VariableRecord) []string {
+	seen := map[string]bool{
+		mod.Path(): true,
+	}
+	paths := []string{mod.Path()}
+	for _, modPath := range mod.VarFileModules {
+		if !seen[modPath] {
+			seen[modPath] = true
+			paths = append(paths, modPath)
+		}
+	}
+	sort.Strings(paths[1:])
+	return paths
+}
+
+// ContextPaths returns paths of all files of the given record, whose
+// contexts cover all files, one per root module the files are decoded
+// against, i.e. the path of the first file (in lexical order) of each module
+func ContextPaths(mod *state.VariableRecord) []lang.Path {
+	firstFiles := make(map[string]string)
+	for name := range mod.ParsedVarsFiles {
+		modPath := FileModulePath(mod, name)
+		if first, ok := firstFiles[modPath]; !ok || name.String() < first {
+			firstFiles[modPath] = name.String()
+		}
+	}
+
+	paths := make([]lang.Path, 0, len(firstFiles))
+	for _, modPath := range ModulePaths(mod) {
+		first, ok := firstFiles[modPath]
+		if !ok {
+			continue
+		}
+		paths = append(paths, lang.Path{
+			Path:       filepath.Join(mod.Path(), first),
+			LanguageID: ilsp.OpenTofuVars.String(),
+		})
+	}
+	return paths
+}
+
+// variablePathContext returns the context of files of the record
+// decoded against the given module, or of all files, such as
+// in the context of the directory used for finding references.
+func variablePathContext(mod *state.VariableRecord, moduleReader ModuleReader, useAnySchema bool, modPath string, allFiles bool) (*decoder.PathContext, error) {
+	inContext := func(name ast.VarsFilename) bool {
+		return allFiles || FileModulePath(mod, name) == modPath
+	}
+
+	variables, _ := moduleReader.ModuleInputs(modPath)
 	bodySchema := &schema.BodySchema{}
 	if useAnySchema {
-		bodySchema = tfschema.AnySchemaForVariableCollection(mod.Path())
+		bodySchema = tfschema.AnySchemaForVariableCollection(modPath)
 	} else {
 		var err error
-		bodySchema, err = tfschema.SchemaForVariables(variables, mod.Path())
+		bodySchema, err = tfschema.SchemaForVariables(variables, modPath)
 		if err != nil {
 			return nil, err
 		}
@@ -43,13 +108,16 @@ func variablePathContext(mod *state.VariableRecord, moduleReader ModuleReader, u
 	}
 
 	for _, origin := range mod.VarsRefOrigins {
-		if ast.IsVarsFilename(origin.OriginRange().Filename) {
+		filename := origin.OriginRange().Filename
+		if ast.IsVarsFilename(filename) && inContext(ast.VarsFilename(filename)) {
 			pathCtx.ReferenceOrigins = append(pathCtx.ReferenceOrigins, origin)
 		}
 	}
 
 	for name, f := range mod.ParsedVarsFiles {
-		pathCtx.Files[name.String()] = f
+		if inContext(name) {
+			pathCtx.Files[name.String()] = f
+		}
 	}
 
 	return pathCtx, nil
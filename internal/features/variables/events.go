@@ -63,12 +63,89 @@ func (f *VariablesFeature) didOpen(ctx context.Context, dir document.DirHandle,
 }
 
 func (f *VariablesFeature) didChange(ctx context.Context, dir document.DirHandle) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	// Files mapped to the module elsewhere need to be revalidated
+	// as the module's variables may have changed
+	for _, path := range f.mappedPaths(dir.Path()) {
+		mIds, err := f.decodeVariable(ctx, document.DirHandleFromPath(path), true)
+		if err != nil {
+			f.logger.Printf("error when decoding variables mapped to %q: %s", dir.Path(), err)
+			continue
+		}
+		ids = append(ids, mIds...)
+	}
+
 	hasVariableRecord := f.store.Exists(dir.Path())
 	if !hasVariableRecord {
-		return job.IDs{}, nil
+		return ids, nil
+	}
+
+	dIds, err := f.decodeVariable(ctx, dir, true)
+	if err != nil {
+		return ids, err
+	}
+
+	return append(ids, dIds...), nil
+}
+
+// indexMappedModules ensures that all modules which files in the given
+// directory are mapped to are indexed and returns IDs of the scheduled jobs
+func (f *VariablesFeature) indexMappedModules(ctx context.Context, path string) job.IDs {
+	ids := make(job.IDs, 0)
+
+	record, err := f.store.VariableRecordByPath(path)
+	if err != nil {
+		return ids
+	}
+
+	indexed := make(map[string]bool)
+	for _, modPath := range record.VarFileModules {
+		if modPath == path || indexed[modPath] {
+			continue
+		}
+		indexed[modPath] = true
+
+		fi, err := f.fs.Stat(modPath)
+		if err != nil || !fi.IsDir() {
+			f.logger.Printf("module %q mapped from %q is not a directory", modPath, path)
+			continue
+		}
+
+		modIds, err := f.moduleFeature.IndexModule(ctx, modPath)
+		if err != nil {
+			f.logger.Printf("failed to index module %q mapped from %q: %s", modPath, path, err)
+			continue
+		}
+		ids = append(ids, modIds...)
 	}
 
-	return f.decodeVariable(ctx, dir, true)
+	return ids
+}
+
+// mappedPaths returns paths of directories other than the module
+// itself, which contain files mapped to the module via -var-file
+func (f *VariablesFeature) mappedPaths(modPath string) []string {
+	paths := make([]string, 0)
+
+	records, err := f.store.List()
+	if err != nil {
+		return paths
+	}
+
+	for _, record := range records {
+		if record.Path() == modPath {
+			continue
+		}
+		for _, mappedPath := range record.VarFileModules {
+			if mappedPath == modPath {
+				paths = append(paths, record.Path())
+				break
+			}
+		}
+	}
+
+	return paths
 }
 
 func (f *VariablesFeature) didChangeWatched(ctx context.Context, rawPath string, changeType protocol.FileChangeType, isDir bool) (job.IDs, error) {
@@ -196,10 +273,15 @@ func (f *VariablesFeature) decodeVariable(ctx context.Context, dir document.DirH
 	parseVarsId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
 		Dir: dir,
 		Func: func(ctx context.Context) error {
-			return jobs.ParseVariables(ctx, f.fs, f.store, path)
+			return jobs.ParseVariables(ctx, f.fs, f.store, path, f.varFilePatterns)
 		},
 		Type:        op.OpTypeParseVariables.String(),
 		IgnoreState: ignoreState,
+		Defer: func(ctx context.Context, jobErr error) (job.IDs, error) {
+			// Modules which files are mapped to may not be open
+			// so we ensure they're indexed
+			return f.indexMappedModules(ctx, path), nil
+		},
 	})
 	if err != nil {
 		return ids, err
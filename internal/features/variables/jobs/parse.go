@@ -23,7 +23,10 @@ import (
 
 // ParseVariables parses the variables configuration,
 // i.e. turns bytes of `*.tfvars` files into AST ([*hcl.File]).
-func ParseVariables(ctx context.Context, fs ReadOnlyFS, varStore *state.VariableStore, modPath string) error {
+//
+// It also maps files to root modules they are passed to via -var-file,
+// as declared by the given patterns or by a comment within the file.
+func ParseVariables(ctx context.Context, fs ReadOnlyFS, varStore *state.VariableStore, modPath string, patterns ast.VarFilePatterns) error {
 	mod, err := varStore.VariableRecordByPath(modPath)
 	if err != nil {
 		return err
@@ -88,6 +91,11 @@ func ParseVariables(ctx context.Context, fs ReadOnlyFS, varStore *state.Variable
 		return sErr
 	}
 
+	sErr = varStore.UpdateVarFileModules(modPath, varFileModules(modPath, files, patterns))
+	if sErr != nil {
+		return sErr
+	}
+
 	sErr = varStore.UpdateVarsDiagnostics(modPath, globalAst.HCLParsingSource, diags)
 	if sErr != nil {
 		return sErr
@@ -95,3 +103,23 @@ func ParseVariables(ctx context.Context, fs ReadOnlyFS, varStore *state.Variable
 
 	return err
 }
+
+// varFileModules maps files to root modules, where a comment
+// within the file takes precedence over the patterns
+func varFileModules(dirPath string, files ast.VarsFiles, patterns ast.VarFilePatterns) ast.VarFileModules {
+	modules := make(ast.VarFileModules)
+
+	for name, f := range files {
+		if f != nil {
+			if modPath, ok := ast.ModuleComment(dirPath, f.Bytes); ok {
+				modules[name] = modPath
+				continue
+			}
+		}
+		if modPath, ok := patterns.ModulePath(filepath.Join(dirPath, name.String())); ok {
+			modules[name] = modPath
+		}
+	}
+
+	return modules
+}
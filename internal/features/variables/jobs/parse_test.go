@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	"github.com/opentofu/tofu-ls/internal/features/variables/ast"
 	"github.com/opentofu/tofu-ls/internal/features/variables/state"
@@ -46,7 +47,7 @@ func TestParseVariables(t *testing.T) {
 	}
 
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseVariables(ctx, testFs, vs, singleFileModulePath)
+	err = ParseVariables(ctx, testFs, vs, singleFileModulePath, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -69,7 +70,7 @@ func TestParseVariables(t *testing.T) {
 		LanguageID: ilsp.OpenTofuVars.String(),
 		URI:        uri.FromPath(filePath),
 	})
-	err = ParseVariables(ctx, testFs, vs, singleFileModulePath)
+	err = ParseVariables(ctx, testFs, vs, singleFileModulePath, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -100,3 +101,59 @@ func TestParseVariables(t *testing.T) {
 		t.Fatal("diags should match for unchanged file")
 	}
 }
+
+func TestParseVariables_varFileModules(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs, err := state.NewVariableStore(gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFs := filesystem.NewFilesystem(gs.DocumentStore)
+
+	varFilesPath := filepath.Join(testData, "var-files")
+	err = vs.Add(varFilesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patterns := ast.VarFilePatterns{
+		{
+			Pattern:    filepath.Join(varFilesPath, "other.tfvars"),
+			ModulePath: filepath.Join(testData, "other"),
+		},
+		{
+			Pattern:    filepath.Join(varFilesPath, "*.tfvars"),
+			ModulePath: filepath.Join(testData, "infra"),
+		},
+	}
+
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseVariables(ctx, testFs, vs, varFilesPath, patterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := vs.VariableRecordByPath(varFilesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The comment takes precedence over patterns
+	expectedModules := ast.VarFileModules{
+		"dev.tfvars":   filepath.Join(filepath.Dir(testData), "app"),
+		"other.tfvars": filepath.Join(testData, "other"),
+		"prod.tfvars":  filepath.Join(testData, "infra"),
+	}
+	if diff := cmp.Diff(expectedModules, record.VarFileModules); diff != "" {
+		t.Fatalf("unexpected modules: %s", diff)
+	}
+}
@@ -9,13 +9,12 @@ import (
 	"context"
 
 	"github.com/hashicorp/hcl-lang/decoder"
-	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
 	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
 	"github.com/opentofu/tofu-ls/internal/document"
 	fdecoder "github.com/opentofu/tofu-ls/internal/features/variables/decoder"
 	"github.com/opentofu/tofu-ls/internal/features/variables/state"
 	"github.com/opentofu/tofu-ls/internal/job"
-	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
 )
 
@@ -51,15 +50,22 @@ func DecodeVarsReferences(ctx context.Context, varStore *state.VariableStore, mo
 	})
 	d.SetContext(idecoder.DecoderContext(ctx))
 
-	varsDecoder, err := d.Path(lang.Path{
-		Path:       modPath,
-		LanguageID: ilsp.OpenTofuVars.String(),
-	})
-	if err != nil {
-		return err
+	// Each file refers to variables of the module it is decoded against
+	origins := make(reference.Origins, 0)
+	var rErr error
+	for _, contextPath := range fdecoder.ContextPaths(mod) {
+		varsDecoder, err := d.Path(contextPath)
+		if err != nil {
+			return err
+		}
+
+		contextOrigins, err := varsDecoder.CollectReferenceOrigins()
+		if err != nil {
+			rErr = err
+		}
+		origins = append(origins, contextOrigins...)
 	}
 
-	origins, rErr := varsDecoder.CollectReferenceOrigins()
 	sErr := varStore.UpdateVarsReferenceOrigins(modPath, origins, rErr)
 	if sErr != nil {
 		return sErr
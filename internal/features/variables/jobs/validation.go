@@ -8,6 +8,7 @@ package jobs
 import (
 	"context"
 	"path"
+	"path/filepath"
 	"time"
 
 	"github.com/hashicorp/hcl-lang/decoder"
@@ -47,23 +48,25 @@ func SchemaVariablesValidation(ctx context.Context, varStore *state.VariableStor
 		return err
 	}
 
-	// We only wait a short period for the module to become ready
+	// We only wait a short period for the modules to become ready
 	// If we have to cancel the validation, we will just run it after the next change
 	timer := time.NewTimer(2 * time.Second)
 	defer timer.Stop()
-	wCh, moduleReady, err := moduleFeature.MetadataReady(document.DirHandleFromPath(modPath))
-	if err != nil {
-		return err
-	}
-	if !moduleReady {
-		select {
-		// Wait for module to be ready
-		case <-wCh:
-		// or for the remaining time to pass
-		case <-timer.C:
-		// or context cancellation
-		case <-ctx.Done():
-			return ctx.Err()
+	for _, path := range fdecoder.ModulePaths(mod) {
+		wCh, moduleReady, err := moduleFeature.MetadataReady(document.DirHandleFromPath(path))
+		if err != nil {
+			return err
+		}
+		if !moduleReady {
+			select {
+			// Wait for module to be ready
+			case <-wCh:
+			// or for the remaining time to pass
+			case <-timer.C:
+			// or context cancellation
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 		}
 	}
 
@@ -73,21 +76,21 @@ func SchemaVariablesValidation(ctx context.Context, varStore *state.VariableStor
 	})
 	d.SetContext(idecoder.DecoderContext(ctx))
 
-	moduleDecoder, err := d.Path(lang.Path{
-		Path:       modPath,
-		LanguageID: ilsp.OpenTofuVars.String(),
-	})
-	if err != nil {
-		return err
-	}
-
 	var rErr error
 	rpcContext := lsctx.DocumentContext(ctx)
 	if rpcContext.Method == "textDocument/didChange" && ilsp.IsValidVarsLanguage(rpcContext.LanguageID) {
 		filename := path.Base(rpcContext.URI)
 		// We only revalidate a single file that changed
+		// using the context of that file
+		fileDecoder, err := d.Path(lang.Path{
+			Path:       filepath.Join(modPath, filename),
+			LanguageID: ilsp.OpenTofuVars.String(),
+		})
+		if err != nil {
+			return err
+		}
 		var fileDiags hcl.Diagnostics
-		fileDiags, rErr = moduleDecoder.ValidateFile(ctx, filename)
+		fileDiags, rErr = fileDecoder.ValidateFile(ctx, filename)
 
 		varsDiags, ok := mod.VarsDiagnostics[globalAst.SchemaValidationSource]
 		if !ok {
@@ -100,9 +103,22 @@ func SchemaVariablesValidation(ctx context.Context, varStore *state.VariableStor
 			return sErr
 		}
 	} else {
-		// We validate the whole module, e.g. on open
-		var diags lang.DiagnosticsMap
-		diags, rErr = moduleDecoder.Validate(ctx)
+		// We validate the whole directory, e.g. on open,
+		// each file against the module it is decoded against
+		diags := make(lang.DiagnosticsMap)
+		for _, contextPath := range fdecoder.ContextPaths(mod) {
+			contextDecoder, err := d.Path(contextPath)
+			if err != nil {
+				return err
+			}
+			contextDiags, err := contextDecoder.Validate(ctx)
+			if err != nil {
+				rErr = err
+			}
+			for filename, fileDiags := range contextDiags {
+				diags[filename] = fileDiags
+			}
+		}
 
 		sErr := varStore.UpdateVarsDiagnostics(modPath, globalAst.SchemaValidationSource, ast.VarsDiagsFromMap(diags))
 		if sErr != nil {
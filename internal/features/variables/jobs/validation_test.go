@@ -13,6 +13,7 @@ import (
 	tfmod "github.com/opentofu/opentofu-schema/module"
 	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	"github.com/opentofu/tofu-ls/internal/document"
+	varsAst "github.com/opentofu/tofu-ls/internal/features/variables/ast"
 	"github.com/opentofu/tofu-ls/internal/features/variables/state"
 	"github.com/opentofu/tofu-ls/internal/filesystem"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
@@ -61,7 +62,7 @@ func TestSchemaVarsValidation_FullModule(t *testing.T) {
 		LanguageID: ilsp.OpenTofuVars.String(),
 		URI:        "file:///test/terraform.tfvars",
 	})
-	err = ParseVariables(ctx, fs, vs, modPath)
+	err = ParseVariables(ctx, fs, vs, modPath, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -114,7 +115,7 @@ func TestSchemaVarsValidation_SingleFile(t *testing.T) {
 		LanguageID: ilsp.OpenTofuVars.String(),
 		URI:        uri.FromPath(filePath),
 	})
-	err = ParseVariables(ctx, fs, vs, modPath)
+	err = ParseVariables(ctx, fs, vs, modPath, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -159,7 +160,7 @@ func TestSchemaVarsValidation_outsideOfModule(t *testing.T) {
 
 	fs := filesystem.NewFilesystem(gs.DocumentStore)
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseVariables(ctx, fs, vs, modPath)
+	err = ParseVariables(ctx, fs, vs, modPath, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -179,3 +180,82 @@ func TestSchemaVarsValidation_outsideOfModule(t *testing.T) {
 		t.Fatalf("expected %d diagnostics, %d given", expectedCount, diagsCount)
 	}
 }
+
+type varFileModuleReaderMock map[string]map[string]tfmod.Variable
+
+func (r varFileModuleReaderMock) ModuleInputs(modPath string) (map[string]tfmod.Variable, error) {
+	return r[modPath], nil
+}
+
+func (r varFileModuleReaderMock) MetadataReady(dir document.DirHandle) (<-chan struct{}, bool, error) {
+	return nil, true, nil
+}
+
+func TestSchemaVarsValidation_varFileModules(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs, err := state.NewVariableStore(gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	varFilesPath := filepath.Join(testData, "var-files")
+	err = vs.Add(varFilesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patterns := varsAst.VarFilePatterns{
+		{
+			Pattern:    filepath.Join(varFilesPath, "other.tfvars"),
+			ModulePath: filepath.Join(testData, "other"),
+		},
+		{
+			Pattern:    filepath.Join(varFilesPath, "*.tfvars"),
+			ModulePath: filepath.Join(testData, "infra"),
+		},
+	}
+
+	fs := filesystem.NewFilesystem(gs.DocumentStore)
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseVariables(ctx, fs, vs, varFilesPath, patterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the module of other.tfvars is missing the variable
+	reader := varFileModuleReaderMock{
+		filepath.Join(filepath.Dir(testData), "app"): {"region": {}},
+		filepath.Join(testData, "other"):             {"zone": {}},
+		filepath.Join(testData, "infra"):             {"region": {}},
+	}
+	err = SchemaVariablesValidation(ctx, vs, reader, varFilesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := vs.VariableRecordByPath(varFilesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := mod.VarsDiagnostics[ast.SchemaValidationSource]
+	expectedCounts := map[varsAst.VarsFilename]int{
+		"dev.tfvars":   0,
+		"other.tfvars": 1,
+		"prod.tfvars":  0,
+	}
+	for name, expectedCount := range expectedCounts {
+		if len(diags[name]) != expectedCount {
+			t.Fatalf("expected %d diagnostics for %s, %d given: %#v",
+				expectedCount, name, len(diags[name]), diags[name])
+		}
+	}
+}
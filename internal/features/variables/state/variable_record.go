@@ -25,6 +25,10 @@ type VariableRecord struct {
 	ParsedVarsFiles ast.VarsFiles
 	VarsParsingErr  error
 
+	// VarFileModules contains root modules which files outside
+	// of the autoloaded ones are mapped to via settings or comments
+	VarFileModules ast.VarFileModules
+
 	VarsDiagnostics      ast.SourceVarsDiags
 	VarsDiagnosticsState globalAst.DiagnosticSourceState
 }
@@ -53,6 +57,10 @@ func (v *VariableRecord) Copy() *VariableRecord {
 		}
 	}
 
+	if v.VarFileModules != nil {
+		newMod.VarFileModules = v.VarFileModules.Copy()
+	}
+
 	if v.VarsDiagnostics != nil {
 		newMod.VarsDiagnostics = make(ast.SourceVarsDiags, len(v.VarsDiagnostics))
 
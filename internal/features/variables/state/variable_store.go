@@ -6,6 +6,7 @@
 package state
 
 import (
+	"bytes"
 	"log"
 
 	"github.com/hashicorp/go-memdb"
@@ -200,6 +201,26 @@ func (s *VariableStore) UpdateParsedVarsFiles(path string, vFiles ast.VarsFiles,
 	return nil
 }
 
+func (s *VariableStore) UpdateVarFileModules(path string, modules ast.VarFileModules) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	record, err := variableRecordCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record.VarFileModules = modules
+
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
 func (s *VariableStore) UpdateVarsDiagnostics(path string, source globalAst.DiagnosticSource, diags ast.VarsDiags) error {
 	txn := s.db.Txn(true)
 	txn.Defer(func() {
@@ -322,6 +343,17 @@ func (s *VariableStore) queueRecordChange(oldRecord, newRecord *VariableRecord)
 		changes.ReferenceOrigins = true
 	}
 
+	var oldFiles, newFiles ast.VarsFiles
+	if oldRecord != nil {
+		oldFiles = oldRecord.ParsedVarsFiles
+	}
+	if newRecord != nil {
+		newFiles = newRecord.ParsedVarsFiles
+	}
+	if !varsFilesEqual(oldFiles, newFiles) {
+		changes.Variables = true
+	}
+
 	var dir document.DirHandle
 	if oldRecord != nil {
 		dir = document.DirHandleFromPath(oldRecord.Path())
@@ -331,3 +363,27 @@ func (s *VariableStore) queueRecordChange(oldRecord, newRecord *VariableRecord)
 
 	return s.changeStore.QueueChange(dir, changes)
 }
+
+func varsFilesEqual(old, new ast.VarsFiles) bool {
+	if len(old) != len(new) {
+		return false
+	}
+
+	for name, oldFile := range old {
+		newFile, ok := new[name]
+		if !ok {
+			return false
+		}
+		if oldFile == nil || newFile == nil {
+			if oldFile != newFile {
+				return false
+			}
+			continue
+		}
+		if !bytes.Equal(oldFile.Bytes, newFile.Bytes) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -12,14 +12,24 @@ import (
 
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/features/variables/ast"
 	fdecoder "github.com/opentofu/tofu-ls/internal/features/variables/decoder"
 	"github.com/opentofu/tofu-ls/internal/features/variables/jobs"
 	"github.com/opentofu/tofu-ls/internal/features/variables/state"
+	"github.com/opentofu/tofu-ls/internal/job"
 	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
 	globalState "github.com/opentofu/tofu-ls/internal/state"
 )
 
+// ModuleFeature provides access to modules whose variables
+// the variable definitions files are decoded against
+type ModuleFeature interface {
+	fdecoder.ModuleReader
+	IndexModule(ctx context.Context, modPath string) (job.IDs, error)
+}
+
 // VariablesFeature groups everything related to variables. Its internal
 // state keeps track of all variable definition files in the workspace.
 type VariablesFeature struct {
@@ -28,12 +38,14 @@ type VariablesFeature struct {
 	stopFunc context.CancelFunc
 	logger   *log.Logger
 
-	moduleFeature fdecoder.ModuleReader
+	moduleFeature ModuleFeature
 	stateStore    *globalState.StateStore
 	fs            jobs.ReadOnlyFS
+
+	varFilePatterns ast.VarFilePatterns
 }
 
-func NewVariablesFeature(eventbus *eventbus.EventBus, stateStore *globalState.StateStore, fs jobs.ReadOnlyFS, moduleFeature fdecoder.ModuleReader) (*VariablesFeature, error) {
+func NewVariablesFeature(eventbus *eventbus.EventBus, stateStore *globalState.StateStore, fs jobs.ReadOnlyFS, moduleFeature ModuleFeature) (*VariablesFeature, error) {
 	store, err := state.NewVariableStore(stateStore.ChangeStore)
 	if err != nil {
 		return nil, err
@@ -56,6 +68,12 @@ func (f *VariablesFeature) SetLogger(logger *log.Logger) {
 	f.store.SetLogger(logger)
 }
 
+// SetVarFilePatterns sets patterns mapping variable definitions files,
+// which are passed to OpenTofu via -var-file, to root modules
+func (f *VariablesFeature) SetVarFilePatterns(patterns ast.VarFilePatterns) {
+	f.varFilePatterns = patterns
+}
+
 // Start starts the features separate goroutine.
 // It listens to various events from the EventBus and performs corresponding actions.
 func (f *VariablesFeature) Start(ctx context.Context) {
@@ -122,6 +140,36 @@ func (f *VariablesFeature) Paths(ctx context.Context) []lang.Path {
 	return pathReader.Paths(ctx)
 }
 
+// ParsedFile returns the parsed variable definitions file, regardless
+// of whether it could be decoded against the schema
+func (f *VariablesFeature) ParsedFile(modPath string, filename string) (*hcl.File, bool) {
+	mod, err := f.store.VariableRecordByPath(modPath)
+	if err != nil {
+		return nil, false
+	}
+
+	file, ok := mod.ParsedVarsFiles[ast.VarsFilename(filename)]
+	return file, ok && file != nil
+}
+
+// AutoloadedVarsFiles returns the parsed variable definitions files
+// which OpenTofu loads automatically, keyed by their names
+func (f *VariablesFeature) AutoloadedVarsFiles(modPath string) map[string]*hcl.File {
+	files := make(map[string]*hcl.File)
+
+	mod, err := f.store.VariableRecordByPath(modPath)
+	if err != nil {
+		return files
+	}
+
+	for name, file := range mod.ParsedVarsFiles {
+		if name.IsAutoloaded() && file != nil {
+			files[name.String()] = file
+		}
+	}
+	return files
+}
+
 func (f *VariablesFeature) Diagnostics(path string) diagnostics.Diagnostics {
 	diags := diagnostics.NewDiagnostics()
 
@@ -131,7 +179,7 @@ func (f *VariablesFeature) Diagnostics(path string) diagnostics.Diagnostics {
 	}
 
 	for source, dm := range mod.VarsDiagnostics {
-		diags.Append(source, dm.AutoloadedOnly().AsMap())
+		diags.Append(source, dm.LoadedOnly(mod.VarFileModules).AsMap())
 	}
 
 	return diags
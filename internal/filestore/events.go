@@ -0,0 +1,327 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package filestore
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/protocol"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// Hooks describe the jobs a feature schedules for files of its kind
+type Hooks struct {
+	// Parse is the job parsing files in the given directory
+	Parse     func(ctx context.Context, path string) error
+	ParseType op.OpType
+
+	// Validate is the job validating parsed files in the given
+	// directory, which runs only if enhanced validation is enabled
+	Validate     func(ctx context.Context, path string) error
+	ValidateType op.OpType
+
+	// Index (optional) schedules indexing of anything the files in the
+	// given directory depend on, once any of them is opened, and returns
+	// IDs of jobs which parsing needs to wait for
+	Index func(ctx context.Context, path string) job.IDs
+
+	// DocumentChanged (optional) is called first whenever a document
+	// is opened or changed. If it reports the document as handled,
+	// e.g. because files of the kind depend on it, the event is not
+	// processed any further.
+	DocumentChanged func(ctx context.Context, dir document.DirHandle, languageID string) (job.IDs, bool, error)
+}
+
+// Events keeps records of directories containing files of a kind
+// in the store up to date, as documents are opened or changed and
+// files are discovered or changed on disk, and schedules the jobs
+// processing the files.
+type Events[N Filename, F any] struct {
+	name       string
+	store      *Store[N, F]
+	stateStore *globalState.StateStore
+	parser     Parser[N, F]
+	hooks      Hooks
+	logger     *log.Logger
+}
+
+// NewEvents returns event handling for files of a kind, whose
+// name (e.g. "backend files") is used in log messages
+func NewEvents[N Filename, F any](name string, store *Store[N, F], stateStore *globalState.StateStore, p Parser[N, F], hooks Hooks) *Events[N, F] {
+	discardLogger := log.New(io.Discard, "", 0)
+
+	return &Events[N, F]{
+		name:       name,
+		store:      store,
+		stateStore: stateStore,
+		parser:     p,
+		hooks:      hooks,
+		logger:     discardLogger,
+	}
+}
+
+func (e *Events[N, F]) SetLogger(logger *log.Logger) {
+	e.logger = logger
+}
+
+// Start listens to events from the EventBus in a separate goroutine
+// until ctx is done, and performs corresponding actions
+func (e *Events[N, F]) Start(ctx context.Context, bus *eventbus.EventBus, identifier string) {
+	discover := bus.OnDiscover(identifier, nil)
+
+	didOpenDone := make(chan struct{}, 10)
+	didOpen := bus.OnDidOpen(identifier, didOpenDone)
+
+	didChangeDone := make(chan struct{}, 10)
+	didChange := bus.OnDidChange(identifier, didChangeDone)
+
+	didChangeWatchedDone := make(chan struct{}, 10)
+	didChangeWatched := bus.OnDidChangeWatched(identifier, didChangeWatchedDone)
+
+	go func() {
+		for {
+			select {
+			case discover := <-discover:
+				// TODO? collect errors
+				e.Discover(discover.Path, discover.Files)
+			case didOpen := <-didOpen:
+				// TODO? collect errors
+				e.DidOpen(didOpen.Context, didOpen.Dir, didOpen.LanguageID)
+				didOpenDone <- struct{}{}
+			case didChange := <-didChange:
+				// TODO? collect errors
+				e.DidChange(didChange.Context, didChange.Dir, didChange.LanguageID)
+				didChangeDone <- struct{}{}
+			case didChangeWatched := <-didChangeWatched:
+				// TODO? collect errors
+				e.DidChangeWatched(didChangeWatched.Context, didChangeWatched.RawPath, didChangeWatched.ChangeType, didChangeWatched.IsDir)
+				didChangeWatchedDone <- struct{}{}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Discover adds a record for the directory if the walker
+// discovered any files of the kind in it
+func (e *Events[N, F]) Discover(path string, files []string) error {
+	for _, file := range files {
+		if _, ok := e.parser.NewFilename(file); ok {
+			e.logger.Printf("discovered %s in %s", e.name, path)
+
+			err := e.store.AddIfNotExists(path)
+			if err != nil {
+				return err
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
+
+func (e *Events[N, F]) DidOpen(ctx context.Context, dir document.DirHandle, languageID string) (job.IDs, error) {
+	if e.hooks.DocumentChanged != nil {
+		ids, handled, err := e.hooks.DocumentChanged(ctx, dir, languageID)
+		if handled {
+			return ids, err
+		}
+	}
+
+	ids := make(job.IDs, 0)
+	path := dir.Path()
+
+	// We need to decide if the path is relevant to us. It can be relevant because
+	// a) the walker discovered files of the kind and created a state entry for them
+	// b) the opened file is a file of the kind
+	//
+	// Add to state if language ID matches
+	if e.parser.IsValidLanguage(languageID) {
+		err := e.store.AddIfNotExists(path)
+		if err != nil {
+			return ids, err
+		}
+	}
+
+	// Schedule jobs if state entry exists
+	if !e.store.Exists(path) {
+		return ids, nil
+	}
+
+	// Whatever the files depend on may not have any open files,
+	// so we make sure it gets indexed before they are decoded
+	dependsOn := make(job.IDs, 0)
+	if e.hooks.Index != nil {
+		dependsOn = e.hooks.Index(ctx, path)
+		ids = append(ids, dependsOn...)
+	}
+
+	decodeIds, err := e.decode(ctx, dir, false, dependsOn)
+	ids = append(ids, decodeIds...)
+	return ids, err
+}
+
+func (e *Events[N, F]) DidChange(ctx context.Context, dir document.DirHandle, languageID string) (job.IDs, error) {
+	if e.hooks.DocumentChanged != nil {
+		ids, handled, err := e.hooks.DocumentChanged(ctx, dir, languageID)
+		if handled {
+			return ids, err
+		}
+	}
+
+	if !e.store.Exists(dir.Path()) {
+		return job.IDs{}, nil
+	}
+
+	return e.decode(ctx, dir, true, job.IDs{})
+}
+
+func (e *Events[N, F]) DidChangeWatched(ctx context.Context, rawPath string, changeType protocol.FileChangeType, isDir bool) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	if changeType == protocol.Deleted {
+		// We don't know whether file or dir is being deleted
+		// 1st we just blindly try to look it up as a directory
+		if e.store.Exists(rawPath) {
+			e.removeIndexed(rawPath)
+			return ids, nil
+		}
+
+		// 2nd we try again assuming it is a file
+		parentDir := filepath.Dir(rawPath)
+		if !e.store.Exists(parentDir) {
+			// Nothing relevant found in the feature state
+			return ids, nil
+		}
+
+		// and check the parent directory still exists
+		fi, err := os.Stat(parentDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// if not, we remove the indexed files
+				e.removeIndexed(rawPath)
+				return ids, nil
+			}
+			e.logger.Printf("error checking existence (%q deleted): %s", parentDir, err)
+			return ids, nil
+		}
+		if !fi.IsDir() {
+			// Should never happen
+			e.logger.Printf("error: %q (deleted) is not a directory", parentDir)
+			return ids, nil
+		}
+
+		// If the parent directory exists, we just need to
+		// check if the there are open documents for the path and the
+		// path has files of the kind. If so, we need to reparse them
+		dir := document.DirHandleFromPath(parentDir)
+		hasOpenDocs, err := e.stateStore.DocumentStore.HasOpenDocuments(dir)
+		if err != nil {
+			e.logger.Printf("error when checking for open documents in path (%q deleted): %s", rawPath, err)
+		}
+		if !hasOpenDocs {
+			return ids, nil
+		}
+
+		e.decode(ctx, dir, true, job.IDs{})
+	}
+
+	if changeType == protocol.Changed || changeType == protocol.Created {
+		var dir document.DirHandle
+		if isDir {
+			dir = document.DirHandleFromPath(rawPath)
+		} else {
+			docHandle := document.HandleFromPath(rawPath)
+			dir = docHandle.Dir
+		}
+
+		// Check if the there are open documents for the path and the
+		// path has files of the kind. If so, we need to reparse them
+		hasOpenDocs, err := e.stateStore.DocumentStore.HasOpenDocuments(dir)
+		if err != nil {
+			e.logger.Printf("error when checking for open documents in path (%q changed): %s", rawPath, err)
+		}
+		if !hasOpenDocs {
+			return ids, nil
+		}
+
+		if !e.store.Exists(dir.Path()) {
+			return ids, nil
+		}
+
+		e.decode(ctx, dir, true, job.IDs{})
+	}
+
+	return ids, nil
+}
+
+func (e *Events[N, F]) removeIndexed(rawPath string) {
+	dirHandle := document.DirHandleFromPath(rawPath)
+
+	err := e.stateStore.JobStore.DequeueJobsForDir(dirHandle)
+	if err != nil {
+		e.logger.Printf("failed to dequeue jobs for %s: %s", e.name, err)
+		return
+	}
+
+	err = e.store.Remove(rawPath)
+	if err != nil {
+		e.logger.Printf("failed to remove %s from state: %s", e.name, err)
+		return
+	}
+}
+
+func (e *Events[N, F]) decode(ctx context.Context, dir document.DirHandle, ignoreState bool, dependsOn job.IDs) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+	path := dir.Path()
+
+	parseId, err := e.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return e.hooks.Parse(ctx, path)
+		},
+		Type:        e.hooks.ParseType.String(),
+		DependsOn:   dependsOn,
+		IgnoreState: ignoreState,
+	})
+	if err != nil {
+		return ids, err
+	}
+	ids = append(ids, parseId)
+
+	validationOptions, err := lsctx.ValidationOptions(ctx)
+	if err != nil {
+		return ids, err
+	}
+	if validationOptions.EnableEnhancedValidation {
+		_, err = e.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+			Dir: dir,
+			Func: func(ctx context.Context) error {
+				return e.hooks.Validate(ctx, path)
+			},
+			Type:        e.hooks.ValidateType.String(),
+			DependsOn:   job.IDs{parseId},
+			IgnoreState: ignoreState,
+		})
+		if err != nil {
+			return ids, err
+		}
+	}
+
+	return ids, nil
+}
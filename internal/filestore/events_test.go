@@ -0,0 +1,182 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package filestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/settings"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+func newTestEvents(t *testing.T, hooks Hooks) (*Events[testFilename, hcl.File], *globalState.StateStore) {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewStore[testFilename, hcl.File]("test", testSources, globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noop := func(ctx context.Context, path string) error { return nil }
+	hooks.Parse = noop
+	hooks.ParseType = op.OpTypeUnknown
+	hooks.Validate = noop
+	hooks.ValidateType = op.OpTypeUnknown
+
+	return NewEvents("test files", s, globalStore, testParser, hooks), globalStore
+}
+
+func testEventsContext() context.Context {
+	ctx := lsctx.WithDocumentContext(context.Background(), lsctx.Document{})
+	return lsctx.WithValidationOptions(ctx, &settings.ValidationOptions{
+		EnableEnhancedValidation: true,
+	})
+}
+
+func TestEvents_Discover(t *testing.T) {
+	e, _ := newTestEvents(t, Hooks{})
+
+	err := e.Discover("/other", []string{"main.tf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = e.Discover("/dir", []string{"main.tf", "a.test", "b.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e.store.Exists("/other") {
+		t.Fatal("expected no record for directory without test files")
+	}
+	if !e.store.Exists("/dir") {
+		t.Fatal("expected record for directory with test files")
+	}
+}
+
+func TestEvents_DidOpen(t *testing.T) {
+	ctx := testEventsContext()
+	dir := document.DirHandleFromPath(t.TempDir())
+
+	var indexIds job.IDs
+	var ss *globalState.StateStore
+	e, ss := newTestEvents(t, Hooks{
+		Index: func(ctx context.Context, path string) job.IDs {
+			id, err := ss.JobStore.EnqueueJob(ctx, job.Job{
+				Dir:  document.DirHandleFromPath(path),
+				Func: func(ctx context.Context) error { return nil },
+				Type: op.OpTypeUnknown.String(),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			indexIds = job.IDs{id}
+			return indexIds
+		},
+	})
+
+	ids, err := e.DidOpen(ctx, dir, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 0 || indexIds != nil {
+		t.Fatalf("expected no jobs for other language, given %#v", ids)
+	}
+
+	ids, err = e.DidOpen(ctx, dir, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.store.Exists(dir.Path()) {
+		t.Fatal("expected record for opened test file")
+	}
+	// index and parse jobs are returned, validation is not
+	if len(ids) != 2 || ids[0] != indexIds[0] {
+		t.Fatalf("expected index and parse jobs, given %#v", ids)
+	}
+
+	queued, err := ss.JobStore.ListIncompleteJobsForDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queued) != 3 {
+		t.Fatalf("expected 3 queued jobs, given %d", len(queued))
+	}
+}
+
+func TestEvents_DidChange_documentChanged(t *testing.T) {
+	ctx := testEventsContext()
+	dir := document.DirHandleFromPath(t.TempDir())
+
+	e, ss := newTestEvents(t, Hooks{
+		DocumentChanged: func(ctx context.Context, dir document.DirHandle, languageID string) (job.IDs, bool, error) {
+			return job.IDs{}, languageID == "dependency", nil
+		},
+	})
+	err := e.store.Add(dir.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = e.DidChange(ctx, dir, "dependency")
+	if err != nil {
+		t.Fatal(err)
+	}
+	queued, err := ss.JobStore.ListIncompleteJobsForDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queued) != 0 {
+		t.Fatalf("expected no jobs for handled document, given %d", len(queued))
+	}
+
+	_, err = e.DidChange(ctx, dir, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	queued, err = ss.JobStore.ListIncompleteJobsForDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queued) != 2 {
+		t.Fatalf("expected parse and validation jobs, given %d", len(queued))
+	}
+}
+
+func TestEvents_DidChangeWatched_deletedDir(t *testing.T) {
+	ctx := testEventsContext()
+	dir := document.DirHandleFromPath(t.TempDir())
+
+	e, ss := newTestEvents(t, Hooks{})
+	_, err := e.DidOpen(ctx, dir, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = e.DidChangeWatched(ctx, dir.Path(), protocol.Deleted, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e.store.Exists(dir.Path()) {
+		t.Fatal("expected record of deleted directory to be removed")
+	}
+	queued, err := ss.JobStore.ListIncompleteJobsForDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queued) != 0 {
+		t.Fatalf("expected jobs of deleted directory to be dequeued, given %d", len(queued))
+	}
+}
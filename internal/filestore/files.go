@@ -0,0 +1,75 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package filestore
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+// Filename is the name of a file of a particular kind,
+// such as a backend configuration file
+type Filename interface {
+	~string
+	IsIgnored() bool
+}
+
+// Files maps files of a kind in a directory to their AST
+type Files[N Filename, F any] map[N]*F
+
+func (fs Files[N, F]) Copy() Files[N, F] {
+	m := make(Files[N, F], len(fs))
+	for name, file := range fs {
+		m[name] = file
+	}
+	return m
+}
+
+// Diags maps files of a kind in a directory to their diagnostics
+type Diags[N Filename] map[N]hcl.Diagnostics
+
+func DiagsFromMap[N Filename](m map[string]hcl.Diagnostics) Diags[N] {
+	mf := make(Diags[N], len(m))
+	for name, file := range m {
+		mf[N(name)] = file
+	}
+	return mf
+}
+
+func (d Diags[N]) Copy() Diags[N] {
+	m := make(Diags[N], len(d))
+	for name, file := range d {
+		m[name] = file
+	}
+	return m
+}
+
+func (d Diags[N]) AsMap() map[string]hcl.Diagnostics {
+	m := make(map[string]hcl.Diagnostics, len(d))
+	for name, diags := range d {
+		m[string(name)] = diags
+	}
+	return m
+}
+
+func (d Diags[N]) Count() int {
+	count := 0
+	for _, diags := range d {
+		count += len(diags)
+	}
+	return count
+}
+
+// SourceDiags groups diagnostics of files by their source
+type SourceDiags[N Filename] map[globalAst.DiagnosticSource]Diags[N]
+
+func (sd SourceDiags[N]) Count() int {
+	count := 0
+	for _, diags := range sd {
+		count += diags.Count()
+	}
+	return count
+}
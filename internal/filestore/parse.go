@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package filestore
+
+import (
+	"context"
+	"path/filepath"
+
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/job"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+	"github.com/opentofu/tofu-ls/internal/tofu/parser"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+// ParseFiles is the job which parses files of a kind in the given
+// directory and stores the resulting AST and diagnostics in the store.
+//
+// All files are parsed the first time. After that, only the changed
+// file is parsed when the job runs for a change of a document.
+func ParseFiles[N Filename, F any](ctx context.Context, fs parser.FS, store *Store[N, F], p Parser[N, F], dirPath string) error {
+	record, err := store.RecordByPath(dirPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid parsing if it is already in progress or already known
+	if record.DiagnosticsState[globalAst.HCLParsingSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(dirPath)}
+	}
+
+	var files Files[N, F]
+	var diags Diags[N]
+	rpcContext := lsctx.DocumentContext(ctx)
+	// Only parse the file that's being changed/opened, unless this is 1st-time parsing
+	if record.DiagnosticsState[globalAst.HCLParsingSource] == op.OpStateLoaded && rpcContext.IsDidChangeRequest() && p.IsValidLanguage(rpcContext.LanguageID) {
+		// the file has already been parsed, so only examine this file and not the whole directory
+		err = store.SetDiagnosticsState(dirPath, globalAst.HCLParsingSource, op.OpStateLoading)
+		if err != nil {
+			return err
+		}
+
+		filePath, err := uri.PathFromURI(rpcContext.URI)
+		if err != nil {
+			return err
+		}
+		fileName := filepath.Base(filePath)
+
+		f, fDiags, err := p.ParseFileFromPath(fs, filePath)
+		if err != nil {
+			return err
+		}
+
+		existingFiles := record.ParsedFiles.Copy()
+		existingFiles[N(fileName)] = f
+		files = existingFiles
+
+		existingDiags, ok := record.Diagnostics[globalAst.HCLParsingSource]
+		if !ok {
+			existingDiags = make(Diags[N])
+		} else {
+			existingDiags = existingDiags.Copy()
+		}
+		existingDiags[N(fileName)] = fDiags
+		diags = existingDiags
+	} else {
+		// this is the first time file is opened so parse the whole directory
+		err = store.SetDiagnosticsState(dirPath, globalAst.HCLParsingSource, op.OpStateLoading)
+		if err != nil {
+			return err
+		}
+
+		files, diags, err = p.ParseDir(fs, dirPath)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	sErr := store.UpdateParsedFiles(dirPath, files, err)
+	if sErr != nil {
+		return sErr
+	}
+
+	sErr = store.UpdateDiagnostics(dirPath, globalAst.HCLParsingSource, diags)
+	if sErr != nil {
+		return sErr
+	}
+
+	return err
+}
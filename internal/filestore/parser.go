@@ -0,0 +1,81 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package filestore
+
+import (
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/tofu/parser"
+)
+
+// Parser describes how to recognize and parse files of a kind
+type Parser[N Filename, F any] struct {
+	// NewFilename returns the filename if the given name
+	// is the name of a file of the kind
+	NewFilename func(name string) (N, bool)
+
+	// ParseFile turns the bytes of a file into AST
+	ParseFile func(src []byte, filename N) (*F, hcl.Diagnostics)
+
+	// IsValidLanguage reports whether documents of the given
+	// language ID are files of the kind
+	IsValidLanguage func(languageID string) bool
+}
+
+// ParseDir parses all files of the kind in the given directory
+func (p Parser[N, F]) ParseDir(fs parser.FS, dirPath string) (Files[N, F], Diags[N], error) {
+	files := make(Files[N, F], 0)
+	diags := make(Diags[N], 0)
+
+	dirEntries, err := fs.ReadDir(dirPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, entry := range dirEntries {
+		if entry.IsDir() {
+			// We only care about files
+			continue
+		}
+
+		name := entry.Name()
+		filename, ok := p.NewFilename(name)
+		if !ok || filename.IsIgnored() {
+			continue
+		}
+
+		fullPath := filepath.Join(dirPath, name)
+
+		src, err := fs.ReadFile(fullPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		f, pDiags := p.ParseFile(src, filename)
+
+		diags[filename] = pDiags
+		if f != nil {
+			files[filename] = f
+		}
+	}
+
+	return files, diags, nil
+}
+
+// ParseFileFromPath parses the file at the given path
+func (p Parser[N, F]) ParseFileFromPath(fs parser.FS, filePath string) (*F, hcl.Diagnostics, error) {
+	src, err := fs.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filename := N(filepath.Base(filePath))
+
+	f, pDiags := p.ParseFile(src, filename)
+
+	return f, pDiags, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package filestore
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// Record contains all information about files
+// of a kind we have for a certain path
+type Record[N Filename, F any] struct {
+	path string
+
+	ParsedFiles Files[N, F]
+	ParsingErr  error
+
+	Diagnostics      SourceDiags[N]
+	DiagnosticsState globalAst.DiagnosticSourceState
+}
+
+func (r *Record[N, F]) Copy() *Record[N, F] {
+	if r == nil {
+		return nil
+	}
+
+	newRecord := &Record[N, F]{
+		path: r.path,
+
+		ParsingErr: r.ParsingErr,
+
+		DiagnosticsState: r.DiagnosticsState.Copy(),
+	}
+
+	if r.ParsedFiles != nil {
+		// parsed files are practically immutable once they come out of parser
+		newRecord.ParsedFiles = r.ParsedFiles.Copy()
+	}
+
+	if r.Diagnostics != nil {
+		newRecord.Diagnostics = make(SourceDiags[N], len(r.Diagnostics))
+
+		for source, fileDiags := range r.Diagnostics {
+			newRecord.Diagnostics[source] = make(Diags[N], len(fileDiags))
+
+			for name, diags := range fileDiags {
+				newRecord.Diagnostics[source][name] = make(hcl.Diagnostics, len(diags))
+				copy(newRecord.Diagnostics[source][name], diags)
+			}
+		}
+	}
+
+	return newRecord
+}
+
+func (r *Record[N, F]) Path() string {
+	return r.path
+}
+
+func newRecord[N Filename, F any](path string, sources []globalAst.DiagnosticSource) *Record[N, F] {
+	state := make(globalAst.DiagnosticSourceState, len(sources))
+	for _, source := range sources {
+		state[source] = op.OpStateUnknown
+	}
+
+	return &Record[N, F]{
+		path:             path,
+		DiagnosticsState: state,
+	}
+}
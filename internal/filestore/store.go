@@ -0,0 +1,316 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package filestore provides state for features which parse
+// files of a particular kind per directory, such as backend
+// configuration or template files, and keep their diagnostics.
+package filestore
+
+import (
+	"io"
+	"log"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/opentofu/tofu-ls/internal/document"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// Store keeps one [Record] per directory
+type Store[N Filename, F any] struct {
+	db        *memdb.MemDB
+	tableName string
+	logger    *log.Logger
+
+	// sources are the sources of diagnostics of new records
+	sources []globalAst.DiagnosticSource
+
+	changeStore *globalState.ChangeStore
+}
+
+// NewStore returns a store of records in a table of the given name.
+// The state of diagnostics of each new record is tracked
+// for the given sources.
+func NewStore[N Filename, F any](tableName string, sources []globalAst.DiagnosticSource, changeStore *globalState.ChangeStore) (*Store[N, F], error) {
+	db, err := memdb.NewMemDB(&memdb.DBSchema{
+		Tables: map[string]*memdb.TableSchema{
+			tableName: {
+				Name: tableName,
+				Indexes: map[string]*memdb.IndexSchema{
+					"id": {
+						Name:    "id",
+						Unique:  true,
+						Indexer: &memdb.StringFieldIndex{Field: "path"},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	discardLogger := log.New(io.Discard, "", 0)
+
+	return &Store[N, F]{
+		db:          db,
+		tableName:   tableName,
+		logger:      discardLogger,
+		sources:     sources,
+		changeStore: changeStore,
+	}, nil
+}
+
+func (s *Store[N, F]) SetLogger(logger *log.Logger) {
+	s.logger = logger
+}
+
+func (s *Store[N, F]) Add(path string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	err := s.add(txn, path)
+	if err != nil {
+		return err
+	}
+	txn.Commit()
+
+	return nil
+}
+
+func (s *Store[N, F]) add(txn *memdb.Txn, path string) error {
+	obj, err := txn.First(s.tableName, "id", path)
+	if err != nil {
+		return err
+	}
+	if obj != nil {
+		return &globalState.AlreadyExistsError{
+			Idx: path,
+		}
+	}
+
+	record := newRecord[N, F](path, s.sources)
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	err = s.queueRecordChange(nil, record)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store[N, F]) AddIfNotExists(path string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	_, err := s.recordByPath(txn, path)
+	if err != nil {
+		if globalState.IsRecordNotFound(err) {
+			err := s.add(txn, path)
+			if err != nil {
+				return err
+			}
+			txn.Commit()
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store[N, F]) Remove(path string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	oldObj, err := txn.First(s.tableName, "id", path)
+	if err != nil {
+		return err
+	}
+
+	if oldObj == nil {
+		// already removed
+		return nil
+	}
+
+	oldRecord := oldObj.(*Record[N, F])
+	err = s.queueRecordChange(oldRecord, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = txn.DeleteAll(s.tableName, "id", path)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *Store[N, F]) List() ([]*Record[N, F], error) {
+	txn := s.db.Txn(false)
+
+	it, err := txn.Get(s.tableName, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*Record[N, F], 0)
+	for item := it.Next(); item != nil; item = it.Next() {
+		record := item.(*Record[N, F])
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func (s *Store[N, F]) Exists(path string) bool {
+	txn := s.db.Txn(false)
+
+	obj, err := txn.First(s.tableName, "id", path)
+	if err != nil {
+		return false
+	}
+
+	return obj != nil
+}
+
+func (s *Store[N, F]) RecordByPath(path string) (*Record[N, F], error) {
+	txn := s.db.Txn(false)
+
+	record, err := s.recordByPath(txn, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (s *Store[N, F]) recordByPath(txn *memdb.Txn, path string) (*Record[N, F], error) {
+	obj, err := txn.First(s.tableName, "id", path)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, &globalState.RecordNotFoundError{
+			Source: path,
+		}
+	}
+	return obj.(*Record[N, F]), nil
+}
+
+func (s *Store[N, F]) recordCopyByPath(txn *memdb.Txn, path string) (*Record[N, F], error) {
+	record, err := s.recordByPath(txn, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return record.Copy(), nil
+}
+
+func (s *Store[N, F]) UpdateParsedFiles(path string, files Files[N, F], pErr error) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	record, err := s.recordCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record.ParsedFiles = files
+	record.ParsingErr = pErr
+
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *Store[N, F]) UpdateDiagnostics(path string, source globalAst.DiagnosticSource, diags Diags[N]) error {
+	txn := s.db.Txn(true)
+	txn.Defer(func() {
+		s.SetDiagnosticsState(path, source, op.OpStateLoaded)
+	})
+	defer txn.Abort()
+
+	oldRecord, err := s.recordByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record := oldRecord.Copy()
+	if record.Diagnostics == nil {
+		record.Diagnostics = make(SourceDiags[N])
+	}
+	record.Diagnostics[source] = diags
+
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	err = s.queueRecordChange(oldRecord, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *Store[N, F]) SetDiagnosticsState(path string, source globalAst.DiagnosticSource, state op.OpState) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	record, err := s.recordCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record.DiagnosticsState[source] = state
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *Store[N, F]) queueRecordChange(oldRecord, newRecord *Record[N, F]) error {
+	changes := globalState.Changes{}
+
+	oldDiags, newDiags := 0, 0
+	if oldRecord != nil {
+		oldDiags = oldRecord.Diagnostics.Count()
+	}
+	if newRecord != nil {
+		newDiags = newRecord.Diagnostics.Count()
+	}
+	// Comparing diagnostics accurately could be expensive
+	// so we just treat any non-empty diags as a change
+	if oldDiags > 0 || newDiags > 0 {
+		changes.Diagnostics = true
+	}
+
+	var dir document.DirHandle
+	if oldRecord != nil {
+		dir = document.DirHandleFromPath(oldRecord.Path())
+	} else {
+		dir = document.DirHandleFromPath(newRecord.Path())
+	}
+
+	return s.changeStore.QueueChange(dir, changes)
+}
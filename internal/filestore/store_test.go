@@ -0,0 +1,157 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package filestore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+type testFilename string
+
+func (tf testFilename) IsIgnored() bool {
+	return globalAst.IsIgnoredFile(string(tf))
+}
+
+var testParser = Parser[testFilename, hcl.File]{
+	NewFilename: func(name string) (testFilename, bool) {
+		if strings.HasSuffix(name, ".test") {
+			return testFilename(name), true
+		}
+		return "", false
+	},
+	ParseFile: func(src []byte, filename testFilename) (*hcl.File, hcl.Diagnostics) {
+		return hclsyntax.ParseConfig(src, string(filename), hcl.InitialPos)
+	},
+	IsValidLanguage: func(languageID string) bool {
+		return languageID == "test"
+	},
+}
+
+var testSources = []globalAst.DiagnosticSource{
+	globalAst.HCLParsingSource,
+	globalAst.SchemaValidationSource,
+}
+
+func newTestStore(t *testing.T) *Store[testFilename, hcl.File] {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewStore[testFilename, hcl.File]("test", testSources, globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStore_Add_duplicate(t *testing.T) {
+	s := newTestStore(t)
+
+	err := s.Add("/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.Add("/test")
+	if err == nil {
+		t.Fatal("expected error for duplicate entry")
+	}
+	existsError := &globalState.AlreadyExistsError{}
+	if !errors.As(err, &existsError) {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = s.AddIfNotExists("/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_RecordByPath(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.RecordByPath("/test")
+	if !globalState.IsRecordNotFound(err) {
+		t.Fatalf("expected record not found error, given: %s", err)
+	}
+
+	err = s.Add("/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := s.RecordByPath("/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Path() != "/test" {
+		t.Fatalf("unexpected path: %q", record.Path())
+	}
+	for _, source := range testSources {
+		if state := record.DiagnosticsState[source]; state != op.OpStateUnknown {
+			t.Fatalf("expected unknown state of %s, given %s", source, state)
+		}
+	}
+}
+
+func TestParseFiles(t *testing.T) {
+	s := newTestStore(t)
+	err := s.Add("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := fstest.MapFS{
+		"dir/valid.test":    {Data: []byte("foo = \"bar\"\n")},
+		"dir/invalid.test":  {Data: []byte("foo = \n")},
+		"dir/other.tf":      {Data: []byte("foo = \"bar\"\n")},
+		"dir/nested/a.test": {Data: []byte("foo = \"bar\"\n")},
+	}
+
+	ctx := lsctx.WithDocumentContext(context.Background(), lsctx.Document{})
+	err = ParseFiles(ctx, fs, s, testParser, "dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := s.RecordByPath("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(record.ParsedFiles) != 2 {
+		t.Fatalf("expected 2 parsed files, given %d", len(record.ParsedFiles))
+	}
+	if _, ok := record.ParsedFiles["other.tf"]; ok {
+		t.Fatal("expected other.tf not to be parsed")
+	}
+
+	diags := record.Diagnostics[globalAst.HCLParsingSource]
+	if len(diags["valid.test"]) != 0 {
+		t.Fatalf("unexpected diagnostics for valid.test: %s", diags["valid.test"])
+	}
+	if len(diags["invalid.test"]) != 1 {
+		t.Fatalf("expected 1 diagnostic for invalid.test, given: %s", diags["invalid.test"])
+	}
+	if state := record.DiagnosticsState[globalAst.HCLParsingSource]; state != op.OpStateLoaded {
+		t.Fatalf("expected loaded parsing state, given %s", state)
+	}
+
+	err = ParseFiles(ctx, fs, s, testParser, "dir")
+	if err == nil {
+		t.Fatal("expected parsing to be skipped once loaded")
+	}
+}
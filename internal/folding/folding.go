@@ -0,0 +1,169 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package folding computes foldable regions of HCL files
+// purely from their syntax, i.e. without any schema.
+package folding
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type RangeKind uint
+
+const (
+	RangeKindRegion RangeKind = iota
+	RangeKindComment
+)
+
+// Range represents a foldable region spanning whole lines,
+// 1-indexed and inclusive on both ends.
+type Range struct {
+	StartLine int
+	EndLine   int
+	Kind      RangeKind
+}
+
+// Ranges returns the foldable regions of a parsed file, which are
+// bodies of blocks, multi-line object and tuple expressions,
+// heredoc strings and runs of consecutive comments.
+//
+// Closing braces, brackets and heredoc markers are left out
+// of the ranges, so they remain visible when folded.
+// JSON files are not supported.
+func Ranges(f *hcl.File) []Range {
+	ranges := make([]Range, 0)
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return ranges
+	}
+
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		switch n := node.(type) {
+		case *hclsyntax.Block:
+			ranges = appendRange(ranges, n.OpenBraceRange.Start.Line, n.CloseBraceRange.Start.Line-1, RangeKindRegion)
+		case *hclsyntax.ObjectConsExpr:
+			ranges = appendRange(ranges, n.SrcRange.Start.Line, n.SrcRange.End.Line-1, RangeKindRegion)
+		case *hclsyntax.TupleConsExpr:
+			ranges = appendRange(ranges, n.SrcRange.Start.Line, n.SrcRange.End.Line-1, RangeKindRegion)
+		}
+		return nil
+	})
+
+	tokens, _ := hclsyntax.LexConfig(f.Bytes, body.SrcRange.Filename, hcl.InitialPos)
+	ranges = append(ranges, heredocRanges(tokens)...)
+	ranges = append(ranges, commentRanges(f.Bytes, tokens)...)
+
+	return dedupe(ranges)
+}
+
+func appendRange(ranges []Range, startLine, endLine int, kind RangeKind) []Range {
+	if endLine <= startLine {
+		return ranges
+	}
+	return append(ranges, Range{
+		StartLine: startLine,
+		EndLine:   endLine,
+		Kind:      kind,
+	})
+}
+
+func heredocRanges(tokens hclsyntax.Tokens) []Range {
+	ranges := make([]Range, 0)
+
+	var openings []hclsyntax.Token
+	for _, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenOHeredoc:
+			openings = append(openings, token)
+		case hclsyntax.TokenCHeredoc:
+			if len(openings) == 0 {
+				continue
+			}
+			opening := openings[len(openings)-1]
+			openings = openings[:len(openings)-1]
+
+			ranges = appendRange(ranges, opening.Range.Start.Line, token.Range.Start.Line-1, RangeKindRegion)
+		}
+	}
+
+	return ranges
+}
+
+// commentRanges returns ranges of multi-line block comments
+// and runs of line comments on consecutive lines.
+// Comments following any other tokens on the same line are ignored.
+func commentRanges(src []byte, tokens hclsyntax.Tokens) []Range {
+	ranges := make([]Range, 0)
+
+	runStart, runEnd := 0, 0
+	flushRun := func() {
+		ranges = appendRange(ranges, runStart, runEnd, RangeKindComment)
+		runStart, runEnd = 0, 0
+	}
+
+	for _, token := range tokens {
+		if token.Type != hclsyntax.TokenComment || !startsLine(src, token.Range.Start) {
+			continue
+		}
+
+		if isBlockComment(token.Bytes) {
+			flushRun()
+			ranges = appendRange(ranges, token.Range.Start.Line, token.Range.End.Line, RangeKindComment)
+			continue
+		}
+
+		if runStart != 0 && token.Range.Start.Line == runEnd+1 {
+			runEnd = token.Range.Start.Line
+			continue
+		}
+
+		flushRun()
+		runStart, runEnd = token.Range.Start.Line, token.Range.Start.Line
+	}
+	flushRun()
+
+	return ranges
+}
+
+func isBlockComment(b []byte) bool {
+	return bytes.HasPrefix(b, []byte("/*"))
+}
+
+// startsLine reports whether there are only whitespace
+// characters between the start of the line and the given position
+func startsLine(src []byte, pos hcl.Pos) bool {
+	if pos.Byte > len(src) {
+		return false
+	}
+	lineStart := bytes.LastIndexByte(src[:pos.Byte], '\n') + 1
+	return len(bytes.TrimSpace(src[lineStart:pos.Byte])) == 0
+}
+
+// dedupe sorts the ranges and removes all but the largest range
+// starting on each line, as clients would not be able to tell them apart
+func dedupe(ranges []Range) []Range {
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].StartLine != ranges[j].StartLine {
+			return ranges[i].StartLine < ranges[j].StartLine
+		}
+		return ranges[i].EndLine > ranges[j].EndLine
+	})
+
+	result := make([]Range, 0, len(ranges))
+	for _, rng := range ranges {
+		if len(result) > 0 && result[len(result)-1].StartLine == rng.StartLine {
+			continue
+		}
+		result = append(result, rng)
+	}
+
+	return result
+}
@@ -0,0 +1,110 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package folding
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+)
+
+func TestRanges(t *testing.T) {
+	cfg := `# Example configuration
+# with a multi-line header
+resource "aws_instance" "web" {
+  tags = {
+    Name = "web"
+  }
+  ports = [
+    80,
+    443,
+  ]
+  single = { a = 1 }
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+/*
+  block comment
+*/
+locals {
+  script = <<EOT
+echo "hello"
+echo "world"
+EOT
+  value = 1 # trailing comment
+  # standalone comment
+}
+
+// unknown blocks are folded too
+unknown "label" {
+  foo = "bar"
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	expectedRanges := []Range{
+		{StartLine: 1, EndLine: 2, Kind: RangeKindComment},
+		{StartLine: 3, EndLine: 15, Kind: RangeKindRegion},
+		{StartLine: 4, EndLine: 5, Kind: RangeKindRegion},
+		{StartLine: 7, EndLine: 9, Kind: RangeKindRegion},
+		{StartLine: 13, EndLine: 14, Kind: RangeKindRegion},
+		{StartLine: 18, EndLine: 20, Kind: RangeKindComment},
+		{StartLine: 21, EndLine: 27, Kind: RangeKindRegion},
+		{StartLine: 22, EndLine: 24, Kind: RangeKindRegion},
+		{StartLine: 31, EndLine: 32, Kind: RangeKindRegion},
+	}
+
+	ranges := Ranges(f)
+	if diff := cmp.Diff(expectedRanges, ranges); diff != "" {
+		t.Fatalf("unexpected ranges: %s", diff)
+	}
+}
+
+func TestRanges_invalidSyntax(t *testing.T) {
+	cfg := `resource "aws_instance" "web" {
+  tags = {
+    Name = "web"
+  }
+  ami =
+}
+`
+	f, _ := hclsyntax.ParseConfig([]byte(cfg), "main.tf", hcl.InitialPos)
+
+	expectedRanges := []Range{
+		{StartLine: 1, EndLine: 5, Kind: RangeKindRegion},
+		{StartLine: 2, EndLine: 3, Kind: RangeKindRegion},
+	}
+
+	ranges := Ranges(f)
+	if diff := cmp.Diff(expectedRanges, ranges); diff != "" {
+		t.Fatalf("unexpected ranges: %s", diff)
+	}
+}
+
+func TestRanges_json(t *testing.T) {
+	f, diags := json.Parse([]byte(`{
+  "locals": {
+    "foo": "bar"
+  }
+}`), "main.tf.json")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	ranges := Ranges(f)
+	if len(ranges) != 0 {
+		t.Fatalf("expected no ranges for JSON, given: %#v", ranges)
+	}
+}
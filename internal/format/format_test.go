@@ -0,0 +1,112 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package format
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestFormat_golden checks the formatter against a corpus of inputs
+// (testdata/*/in.*) and the output produced by `tofu fmt` (testdata/*/out.*)
+func TestFormat_golden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*", "in.*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no test inputs found")
+	}
+
+	for _, inPath := range inputs {
+		t.Run(filepath.Base(filepath.Dir(inPath)), func(t *testing.T) {
+			src, err := os.ReadFile(inPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			outPath := filepath.Join(filepath.Dir(inPath), "out"+filepath.Ext(inPath))
+			expected, err := os.ReadFile(outPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			formatted, err := Format(src, filepath.Base(inPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(expected), string(formatted)); diff != "" {
+				t.Fatalf("unexpected output: %s", diff)
+			}
+
+			// formatting is expected to be idempotent
+			reformatted, err := Format(formatted, filepath.Base(inPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(formatted), string(reformatted)); diff != "" {
+				t.Fatalf("formatting is not idempotent: %s", diff)
+			}
+		})
+	}
+}
+
+// TestFormat_matchesCLI compares the formatter with `tofu fmt`
+// directly, if tofu is available on the PATH
+func TestFormat_matchesCLI(t *testing.T) {
+	tofuPath, err := exec.LookPath("tofu")
+	if err != nil {
+		t.Skip("tofu binary not found on PATH")
+	}
+
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*", "in.*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, inPath := range inputs {
+		t.Run(filepath.Base(filepath.Dir(inPath)), func(t *testing.T) {
+			src, err := os.ReadFile(inPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := exec.CommandContext(context.Background(), tofuPath, "fmt", "-no-color", "-")
+			cmd.Stdin = bytes.NewReader(src)
+			var stdout, stderr strings.Builder
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			err = cmd.Run()
+			if err != nil {
+				t.Fatalf("tofu fmt failed: %s: %s", err, stderr.String())
+			}
+
+			formatted, err := Format(src, filepath.Base(inPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(stdout.String(), string(formatted)); diff != "" {
+				t.Fatalf("output does not match tofu fmt: %s", diff)
+			}
+		})
+	}
+}
+
+func TestFormat_invalidSyntax(t *testing.T) {
+	src := []byte(`resource "aws_instance" "web" {
+  ami = 
+`)
+	_, err := Format(src, "main.tf")
+	if err == nil {
+		t.Fatal("expected error for invalid syntax")
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package format
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// LineRange represents a range of whole lines,
+// 1-indexed and inclusive on both ends.
+type LineRange struct {
+	Start, End int
+}
+
+func (lr LineRange) overlaps(start, end int) bool {
+	return start <= lr.End && end >= lr.Start
+}
+
+// TopLevelLines returns the lines spanned by all top-level blocks
+// and attributes which intersect with the given lines.
+//
+// Top-level blocks can be formatted in isolation, since their formatting
+// does not depend on any surrounding content. Attributes on adjacent lines
+// are aligned together though, so these are included as well.
+func TopLevelLines(src []byte, filename string, lines LineRange) (LineRange, bool) {
+	body, ok := parseBody(src, filename)
+	if !ok {
+		return LineRange{}, false
+	}
+
+	var result LineRange
+	found := false
+	for _, rng := range topLevelRanges(body) {
+		if !lines.overlaps(rng.Start.Line, rng.End.Line) {
+			continue
+		}
+		if !found {
+			result = LineRange{Start: rng.Start.Line, End: rng.End.Line}
+			found = true
+			continue
+		}
+		result.Start = min(result.Start, rng.Start.Line)
+		result.End = max(result.End, rng.End.Line)
+	}
+	if !found {
+		return result, false
+	}
+
+	for extended := true; extended; {
+		extended = false
+		for _, attr := range body.Attributes {
+			if attr.SrcRange.End.Line == result.Start-1 {
+				result.Start = attr.SrcRange.Start.Line
+				extended = true
+			}
+			if attr.SrcRange.Start.Line == result.End+1 {
+				result.End = attr.SrcRange.End.Line
+				extended = true
+			}
+		}
+	}
+
+	return result, true
+}
+
+// ClosedBlockLines finds the block whose closing brace is on the given line
+// and returns the lines spanned by it, along with the lines spanned
+// by the top-level block which contains it (which may be the same block).
+//
+// If more than one block is closed on the line, the outermost one is returned.
+// The closing brace must end at or before maxByte, if it is positive.
+func ClosedBlockLines(src []byte, filename string, line int, maxByte int) (LineRange, LineRange, bool) {
+	body, ok := parseBody(src, filename)
+	if !ok {
+		return LineRange{}, LineRange{}, false
+	}
+
+	for _, block := range body.Blocks {
+		closed, ok := closedBlock(block, line, maxByte)
+		if !ok {
+			continue
+		}
+
+		topRng := block.Range()
+		return LineRange{Start: closed.Range().Start.Line, End: closed.Range().End.Line},
+			LineRange{Start: topRng.Start.Line, End: topRng.End.Line},
+			true
+	}
+
+	return LineRange{}, LineRange{}, false
+}
+
+func closedBlock(block *hclsyntax.Block, line int, maxByte int) (*hclsyntax.Block, bool) {
+	closeRng := block.CloseBraceRange
+	if closeRng.Start.Line == line && (maxByte <= 0 || closeRng.End.Byte <= maxByte) {
+		return block, true
+	}
+
+	rng := block.Range()
+	if line < rng.Start.Line || line > rng.End.Line {
+		return nil, false
+	}
+
+	for _, nested := range block.Body.Blocks {
+		closed, ok := closedBlock(nested, line, maxByte)
+		if ok {
+			return closed, true
+		}
+	}
+
+	return nil, false
+}
+
+// Lines returns the content of the given lines, including
+// the trailing newline of the last line, if any.
+func Lines(src []byte, lines LineRange) []byte {
+	start, end := lineOffsets(src, lines)
+	return src[start:end]
+}
+
+// ReplaceLines returns a copy of src where the given lines
+// are replaced with the replacement.
+func ReplaceLines(src []byte, lines LineRange, replacement []byte) []byte {
+	start, end := lineOffsets(src, lines)
+
+	result := make([]byte, 0, len(src)-(end-start)+len(replacement))
+	result = append(result, src[:start]...)
+	result = append(result, replacement...)
+	result = append(result, src[end:]...)
+
+	return result
+}
+
+func lineOffsets(src []byte, lines LineRange) (int, int) {
+	start, end := len(src), len(src)
+
+	line := 1
+	if lines.Start <= 1 {
+		start = 0
+	}
+	for i, b := range src {
+		if b != '\n' {
+			continue
+		}
+		line++
+		if line == lines.Start {
+			start = i + 1
+		}
+		if line == lines.End+1 {
+			end = i + 1
+			break
+		}
+	}
+
+	return start, end
+}
+
+func parseBody(src []byte, filename string) (*hclsyntax.Body, bool) {
+	f, _ := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if f == nil {
+		return nil, false
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	return body, ok
+}
+
+func topLevelRanges(body *hclsyntax.Body) []hcl.Range {
+	ranges := make([]hcl.Range, 0, len(body.Attributes)+len(body.Blocks))
+	for _, attr := range body.Attributes {
+		ranges = append(ranges, attr.SrcRange)
+	}
+	for _, block := range body.Blocks {
+		ranges = append(ranges, block.Range())
+	}
+	return ranges
+}
@@ -0,0 +1,107 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package format
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var linesTestCfg = []byte(`a = 1
+bb = 2
+
+locals {
+  foo = 1
+}
+
+resource "aws_instance" "web" {
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+`)
+
+func TestTopLevelLines(t *testing.T) {
+	testCases := []struct {
+		lines         LineRange
+		expectedLines LineRange
+		expectFound   bool
+	}{
+		{
+			// adjacent attributes are aligned together
+			LineRange{Start: 1, End: 1},
+			LineRange{Start: 1, End: 2},
+			true,
+		},
+		{
+			LineRange{Start: 5, End: 5},
+			LineRange{Start: 4, End: 6},
+			true,
+		},
+		{
+			LineRange{Start: 5, End: 9},
+			LineRange{Start: 4, End: 12},
+			true,
+		},
+		{
+			// empty line
+			LineRange{Start: 7, End: 7},
+			LineRange{},
+			false,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			lines, ok := TopLevelLines(linesTestCfg, "test.tf", tc.lines)
+			if ok != tc.expectFound {
+				t.Fatalf("expected found: %t, given: %t", tc.expectFound, ok)
+			}
+			if diff := cmp.Diff(tc.expectedLines, lines); diff != "" {
+				t.Fatalf("unexpected lines: %s", diff)
+			}
+		})
+	}
+}
+
+func TestClosedBlockLines(t *testing.T) {
+	blockLines, topLines, ok := ClosedBlockLines(linesTestCfg, "test.tf", 11, 0)
+	if !ok {
+		t.Fatal("expected closed block to be found")
+	}
+	if diff := cmp.Diff(LineRange{Start: 9, End: 11}, blockLines); diff != "" {
+		t.Fatalf("unexpected block lines: %s", diff)
+	}
+	if diff := cmp.Diff(LineRange{Start: 8, End: 12}, topLines); diff != "" {
+		t.Fatalf("unexpected top-level lines: %s", diff)
+	}
+
+	_, _, ok = ClosedBlockLines(linesTestCfg, "test.tf", 10, 0)
+	if ok {
+		t.Fatal("expected no block to be closed on line 10")
+	}
+}
+
+func TestReplaceLines(t *testing.T) {
+	src := []byte("one\ntwo\nthree\n")
+
+	given := string(Lines(src, LineRange{Start: 2, End: 3}))
+	if diff := cmp.Diff("two\nthree\n", given); diff != "" {
+		t.Fatalf("unexpected lines: %s", diff)
+	}
+
+	given = string(ReplaceLines(src, LineRange{Start: 2, End: 2}, []byte("TWO\n")))
+	if diff := cmp.Diff("one\nTWO\nthree\n", given); diff != "" {
+		t.Fatalf("unexpected result: %s", diff)
+	}
+
+	given = string(ReplaceLines(src, LineRange{Start: 1, End: 1}, []byte("ONE\n")))
+	if diff := cmp.Diff("ONE\ntwo\nthree\n", given); diff != "" {
+		t.Fatalf("unexpected result: %s", diff)
+	}
+}
@@ -0,0 +1,8 @@
+resource "aws_instance" "web" {
+}
+
+module "network" {
+  source = "./network"
+}
+
+data "aws_ami" "ubuntu" {}
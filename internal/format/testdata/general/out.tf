@@ -0,0 +1,19 @@
+# This comment should not be touched
+resource "aws_instance" "web" {
+  ami           = "ami-12345"
+  instance_type = "t2.micro"
+  tags = {
+    Name        = "web"
+    Environment = "prod"
+  }
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+locals {
+  a   = 1
+  bb  = 2 # trailing comment
+  ccc = [1, 2, 3]
+}
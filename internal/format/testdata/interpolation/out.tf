@@ -0,0 +1,12 @@
+locals {
+  simple   = var.foo
+  literal  = "prefix-${var.foo}"
+  multiple = "${var.foo}${var.bar}"
+  nested   = upper("${var.foo}")
+  multi_line = (var.enabled
+    ? var.foo
+  : var.bar)
+  heredoc = <<EOT
+  ${var.foo}
+  EOT
+}
@@ -0,0 +1,28 @@
+variable "a" {
+  type = "string"
+}
+
+variable "b" {
+  type = "list"
+}
+
+variable "c" {
+  type = "map"
+}
+
+variable "d" {
+  type = list
+}
+
+variable "e" {
+  type = set
+}
+
+variable "f" {
+  type = map(string)
+}
+
+output "g" {
+  # type is only normalized within variable blocks
+  value = "list"
+}
@@ -0,0 +1,28 @@
+variable "a" {
+  type = string
+}
+
+variable "b" {
+  type = list(string)
+}
+
+variable "c" {
+  type = map(string)
+}
+
+variable "d" {
+  type = list(any)
+}
+
+variable "e" {
+  type = set(any)
+}
+
+variable "f" {
+  type = map(string)
+}
+
+output "g" {
+  # type is only normalized within variable blocks
+  value = "list"
+}
@@ -0,0 +1,163 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package inlay computes inlay hints for OpenTofu configuration files,
+// i.e. short pieces of information rendered inline next to the code.
+package inlay
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type HintKind uint
+
+const (
+	HintKindValue HintKind = iota
+	HintKindType
+)
+
+// Hint represents a label to be shown after the given position
+type Hint struct {
+	Pos     hcl.Pos
+	Label   string
+	Tooltip string
+	Kind    HintKind
+}
+
+// moduleMetaArguments are arguments of module blocks
+// which are not passed to the module as inputs
+var moduleMetaArguments = map[string]bool{
+	"source":     true,
+	"version":    true,
+	"count":      true,
+	"for_each":   true,
+	"providers":  true,
+	"depends_on": true,
+}
+
+// VariableValueHints returns hints with values of variables next to
+// all var.<name> references within the given range of the file.
+// Values of sensitive variables are not revealed.
+func VariableValueHints(f *hcl.File, rng hcl.Range, variables map[string]tfmod.Variable, values map[string]VariableValue) []Hint {
+	hints := make([]Hint, 0)
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return hints
+	}
+
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+		if !ok || len(expr.Traversal) < 2 || expr.Traversal.RootName() != "var" {
+			return nil
+		}
+		step, ok := expr.Traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			return nil
+		}
+
+		pos := step.SrcRange.End
+		if !containsPos(rng, pos) {
+			return nil
+		}
+
+		v, ok := variables[step.Name]
+		if !ok {
+			return nil
+		}
+		value, ok := values[step.Name]
+		if !ok {
+			return nil
+		}
+
+		label := FormatValue(value.Value, v.Type)
+		if v.IsSensitive {
+			label = "(sensitive)"
+		}
+		hints = append(hints, Hint{
+			Pos:     pos,
+			Label:   fmt.Sprintf("= %s", label),
+			Tooltip: fmt.Sprintf("Value from %s", value.Source),
+			Kind:    HintKindValue,
+		})
+
+		return nil
+	})
+
+	sortHints(hints)
+	return hints
+}
+
+// ModuleInputTypeHints returns hints with declared types of inputs
+// next to their names in module blocks within the given range of the file.
+// Inputs of each module call are looked up via the given function.
+func ModuleInputTypeHints(f *hcl.File, rng hcl.Range, moduleInputs func(callName string) (map[string]tfmod.Variable, bool)) []Hint {
+	hints := make([]Hint, 0)
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return hints
+	}
+
+	for _, block := range body.Blocks {
+		if block.Type != "module" || len(block.Labels) != 1 {
+			continue
+		}
+		if !rangesOverlap(rng, block.Range()) {
+			continue
+		}
+
+		inputs, ok := moduleInputs(block.Labels[0])
+		if !ok {
+			continue
+		}
+
+		for name, attr := range block.Body.Attributes {
+			if moduleMetaArguments[name] {
+				continue
+			}
+			pos := attr.NameRange.End
+			if !containsPos(rng, pos) {
+				continue
+			}
+
+			input, ok := inputs[name]
+			if !ok || input.Type == cty.NilType || input.Type == cty.DynamicPseudoType {
+				continue
+			}
+
+			hints = append(hints, Hint{
+				Pos:     pos,
+				Label:   fmt.Sprintf(": %s", typeexpr.TypeString(input.Type)),
+				Tooltip: input.Description,
+				Kind:    HintKindType,
+			})
+		}
+	}
+
+	sortHints(hints)
+	return hints
+}
+
+func sortHints(hints []Hint) {
+	sort.Slice(hints, func(i, j int) bool {
+		return hints[i].Pos.Byte < hints[j].Pos.Byte
+	})
+}
+
+func containsPos(rng hcl.Range, pos hcl.Pos) bool {
+	return rng.Start.Byte <= pos.Byte && pos.Byte <= rng.End.Byte
+}
+
+func rangesOverlap(a, b hcl.Range) bool {
+	return a.Start.Byte <= b.End.Byte && b.Start.Byte <= a.End.Byte
+}
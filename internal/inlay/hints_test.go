@@ -0,0 +1,124 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package inlay
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestVariableValueHints(t *testing.T) {
+	f := parseHCL(t, "main.tf", `resource "aws_instance" "web" {
+  ami  = var.ami
+  tags = { Name = "${var.name}-web" }
+  key  = var.secret
+  env  = var.unknown.foo
+}
+`)
+	variables := map[string]tfmod.Variable{
+		"ami":    {Type: cty.String},
+		"name":   {Type: cty.String},
+		"secret": {Type: cty.String, IsSensitive: true},
+	}
+	values := map[string]VariableValue{
+		"ami":    {Value: cty.StringVal("ami-123"), Source: "terraform.tfvars"},
+		"name":   {Value: cty.StringVal("prod"), Source: "default"},
+		"secret": {Value: cty.StringVal("hunter2"), Source: "TF_VAR_secret"},
+	}
+
+	hints := VariableValueHints(f, fileRange(f), variables, values)
+
+	expectedHints := []Hint{
+		{
+			Pos:     hcl.Pos{Line: 2, Column: 17, Byte: 48},
+			Label:   `= "ami-123"`,
+			Tooltip: "Value from terraform.tfvars",
+			Kind:    HintKindValue,
+		},
+		{
+			Pos:     hcl.Pos{Line: 3, Column: 30, Byte: 78},
+			Label:   `= "prod"`,
+			Tooltip: "Value from default",
+			Kind:    HintKindValue,
+		},
+		{
+			Pos:     hcl.Pos{Line: 4, Column: 20, Byte: 106},
+			Label:   "= (sensitive)",
+			Tooltip: "Value from TF_VAR_secret",
+			Kind:    HintKindValue,
+		},
+	}
+	if diff := cmp.Diff(expectedHints, hints); diff != "" {
+		t.Fatalf("unexpected hints: %s", diff)
+	}
+
+	firstLine := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 1, Byte: 32},
+		End:      hcl.Pos{Line: 2, Column: 17, Byte: 48},
+	}
+	hints = VariableValueHints(f, firstLine, variables, values)
+	if len(hints) != 1 {
+		t.Fatalf("expected 1 hint within range, given %d: %#v", len(hints), hints)
+	}
+}
+
+func TestModuleInputTypeHints(t *testing.T) {
+	f := parseHCL(t, "main.tf", `module "web" {
+  source = "./web"
+  count  = 2
+  name   = "web"
+  ports  = [80]
+  any    = true
+}
+
+module "unknown" {
+  source = "./unknown"
+  name   = "unknown"
+}
+`)
+	moduleInputs := func(callName string) (map[string]tfmod.Variable, bool) {
+		if callName != "web" {
+			return nil, false
+		}
+		return map[string]tfmod.Variable{
+			"name":  {Type: cty.String, Description: "Name of the service"},
+			"ports": {Type: cty.List(cty.Number)},
+			"any":   {Type: cty.DynamicPseudoType},
+		}, true
+	}
+
+	hints := ModuleInputTypeHints(f, fileRange(f), moduleInputs)
+
+	expectedHints := []Hint{
+		{
+			Pos:     hcl.Pos{Line: 4, Column: 7, Byte: 53},
+			Label:   ": string",
+			Tooltip: "Name of the service",
+			Kind:    HintKindType,
+		},
+		{
+			Pos:   hcl.Pos{Line: 5, Column: 8, Byte: 71},
+			Label: ": list(number)",
+			Kind:  HintKindType,
+		},
+	}
+	if diff := cmp.Diff(expectedHints, hints); diff != "" {
+		t.Fatalf("unexpected hints: %s", diff)
+	}
+}
+
+func fileRange(f *hcl.File) hcl.Range {
+	return hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.InitialPos,
+		End:      hcl.Pos{Line: 100, Column: 1, Byte: len(f.Bytes)},
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package inlay
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+const envVarPrefix = "TF_VAR_"
+
+// VariableValue represents the value which would be
+// assigned to a variable, along with its source
+type VariableValue struct {
+	Value cty.Value
+
+	// Source describes where the value comes from,
+	// i.e. the default, a filename or an environment variable
+	Source string
+}
+
+// VariableValues resolves the values of the declared variables
+// from their defaults, environment variables (in the KEY=value
+// format of os.Environ) and autoloaded variable files.
+//
+// Values are taken in the same order of precedence as OpenTofu uses,
+// where any later source overrides the earlier ones:
+//   - default of the variable
+//   - TF_VAR_ environment variables
+//   - terraform.tfvars
+//   - terraform.tfvars.json
+//   - *.auto.tfvars and *.auto.tfvars.json, in lexical order
+//
+// Values which cannot be evaluated without any context,
+// e.g. because they contain references or function calls, are ignored.
+func VariableValues(variables map[string]tfmod.Variable, varsFiles map[string]*hcl.File, environ []string) map[string]VariableValue {
+	values := make(map[string]VariableValue)
+
+	for name, v := range variables {
+		if v.DefaultValue != cty.NilVal {
+			values[name] = VariableValue{
+				Value:  v.DefaultValue,
+				Source: "default",
+			}
+		}
+	}
+
+	for _, env := range environ {
+		key, rawValue, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(key, envVarPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, envVarPrefix)
+		v, ok := variables[name]
+		if !ok {
+			continue
+		}
+
+		val, ok := envVarValue(rawValue, v.Type)
+		if !ok {
+			continue
+		}
+		values[name] = VariableValue{
+			Value:  val,
+			Source: key,
+		}
+	}
+
+	for _, filename := range sortedVarsFilenames(varsFiles) {
+		attrs, _ := varsFiles[filename].Body.JustAttributes()
+		for name, attr := range attrs {
+			if _, ok := variables[name]; !ok {
+				continue
+			}
+
+			val, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				continue
+			}
+			values[name] = VariableValue{
+				Value:  val,
+				Source: filename,
+			}
+		}
+	}
+
+	return values
+}
+
+// envVarValue parses the raw value of an environment variable,
+// which is taken literally for primitive types (and variables
+// without any type) and parsed as an HCL expression otherwise
+func envVarValue(rawValue string, typ cty.Type) (cty.Value, bool) {
+	if typ == cty.NilType || typ == cty.DynamicPseudoType || typ.IsPrimitiveType() {
+		return cty.StringVal(rawValue), true
+	}
+
+	expr, diags := hclsyntax.ParseExpression([]byte(rawValue), envVarPrefix, hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilVal, false
+	}
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return cty.NilVal, false
+	}
+	return val, true
+}
+
+// sortedVarsFilenames returns the filenames in the order
+// in which OpenTofu loads them
+func sortedVarsFilenames(varsFiles map[string]*hcl.File) []string {
+	filenames := make([]string, 0, len(varsFiles))
+	for filename := range varsFiles {
+		filenames = append(filenames, filename)
+	}
+
+	loadOrder := func(filename string) int {
+		switch filename {
+		case "terraform.tfvars":
+			return 0
+		case "terraform.tfvars.json":
+			return 1
+		}
+		return 2
+	}
+	sort.Slice(filenames, func(i, j int) bool {
+		if loadOrder(filenames[i]) != loadOrder(filenames[j]) {
+			return loadOrder(filenames[i]) < loadOrder(filenames[j])
+		}
+		return filenames[i] < filenames[j]
+	})
+
+	return filenames
+}
+
+const maxValueLength = 40
+
+// FormatValue renders the value as a single line of HCL,
+// which is shortened if it is too long to be shown inline
+func FormatValue(val cty.Value, typ cty.Type) string {
+	if typ != cty.NilType && typ != cty.DynamicPseudoType {
+		// Values from variable files and defaults get converted
+		// to the declared type, e.g. 1 to "1" for string variables
+		if converted, err := convert.Convert(val, typ); err == nil {
+			val = converted
+		}
+	}
+	if !val.IsWhollyKnown() {
+		return "(known after apply)"
+	}
+
+	text := formatValue(val)
+
+	runes := []rune(text)
+	if len(runes) > maxValueLength {
+		return fmt.Sprintf("%s…", string(runes[:maxValueLength-1]))
+	}
+	return text
+}
+
+func formatValue(val cty.Value) string {
+	if val.IsNull() || val.Type().IsPrimitiveType() {
+		return string(hclwrite.TokensForValue(val).Bytes())
+	}
+
+	ty := val.Type()
+	switch {
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		elems := make([]string, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			elems = append(elems, formatValue(elem))
+		}
+		return fmt.Sprintf("[%s]", strings.Join(elems, ", "))
+	case ty.IsMapType() || ty.IsObjectType():
+		if val.LengthInt() == 0 {
+			return "{}"
+		}
+		elems := make([]string, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			key, elem := it.Element()
+			elems = append(elems, fmt.Sprintf("%s = %s", formatKey(key), formatValue(elem)))
+		}
+		return fmt.Sprintf("{ %s }", strings.Join(elems, ", "))
+	}
+
+	return string(hclwrite.TokensForValue(val).Bytes())
+}
+
+func formatKey(key cty.Value) string {
+	if key.Type() == cty.String && hclsyntax.ValidIdentifier(key.AsString()) {
+		return key.AsString()
+	}
+	return string(hclwrite.TokensForValue(key).Bytes())
+}
@@ -0,0 +1,144 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package inlay
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestVariableValues(t *testing.T) {
+	variables := map[string]tfmod.Variable{
+		"default_only": {
+			Type:         cty.String,
+			DefaultValue: cty.StringVal("default"),
+		},
+		"from_env": {
+			Type:         cty.String,
+			DefaultValue: cty.StringVal("default"),
+		},
+		"from_env_list": {
+			Type: cty.List(cty.Number),
+		},
+		"from_tfvars": {
+			Type: cty.Number,
+		},
+		"from_json": {
+			Type: cty.String,
+		},
+		"from_auto": {
+			Type:         cty.Bool,
+			DefaultValue: cty.False,
+		},
+		"unresolved": {
+			Type: cty.String,
+		},
+		"reference": {
+			Type: cty.String,
+		},
+	}
+
+	varsFiles := map[string]*hcl.File{
+		"terraform.tfvars": parseHCL(t, "terraform.tfvars", `
+from_env    = "tfvars"
+from_tfvars = 42
+from_auto   = false
+reference   = local.foo
+undeclared  = "ignored"
+`),
+		"terraform.tfvars.json": parseJSON(t, "terraform.tfvars.json", `{"from_json": "json", "from_auto": false}`),
+		"b.auto.tfvars":         parseHCL(t, "b.auto.tfvars", `from_auto = true`),
+		"a.auto.tfvars":         parseHCL(t, "a.auto.tfvars", `from_tfvars = 43`),
+	}
+
+	environ := []string{
+		"PATH=/usr/bin",
+		"TF_VAR_from_env=env",
+		"TF_VAR_from_env_list=[1, 2]",
+		"TF_VAR_undeclared=ignored",
+	}
+
+	values := VariableValues(variables, varsFiles, environ)
+
+	expectedValues := map[string]VariableValue{
+		"default_only":  {Value: cty.StringVal("default"), Source: "default"},
+		"from_env":      {Value: cty.StringVal("tfvars"), Source: "terraform.tfvars"},
+		"from_env_list": {Value: cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.NumberIntVal(2)}), Source: "TF_VAR_from_env_list"},
+		"from_tfvars":   {Value: cty.NumberIntVal(43), Source: "a.auto.tfvars"},
+		"from_json":     {Value: cty.StringVal("json"), Source: "terraform.tfvars.json"},
+		"from_auto":     {Value: cty.True, Source: "b.auto.tfvars"},
+	}
+
+	if len(values) != len(expectedValues) {
+		t.Fatalf("expected %d values, given %d: %#v", len(expectedValues), len(values), values)
+	}
+	for name, expected := range expectedValues {
+		given, ok := values[name]
+		if !ok {
+			t.Fatalf("expected value for %q", name)
+		}
+		if given.Source != expected.Source {
+			t.Fatalf("%q: expected source %q, given %q", name, expected.Source, given.Source)
+		}
+		if !given.Value.RawEquals(expected.Value) {
+			t.Fatalf("%q: expected value %#v, given %#v", name, expected.Value, given.Value)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	testCases := []struct {
+		val      cty.Value
+		typ      cty.Type
+		expected string
+	}{
+		{cty.StringVal("foo"), cty.String, `"foo"`},
+		{cty.NumberIntVal(1), cty.String, `"1"`},
+		{cty.StringVal("1"), cty.DynamicPseudoType, `"1"`},
+		{cty.NullVal(cty.String), cty.String, `null`},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"port": cty.NumberIntVal(80),
+			}),
+			cty.DynamicPseudoType,
+			`{ name = "web", port = 80 }`,
+		},
+		{
+			cty.StringVal("a very long string which does not fit inline"),
+			cty.String,
+			`"a very long string which does not fit …`,
+		},
+	}
+
+	for _, tc := range testCases {
+		given := FormatValue(tc.val, tc.typ)
+		if given != tc.expected {
+			t.Fatalf("expected %q, given %q", tc.expected, given)
+		}
+	}
+}
+
+func parseHCL(t *testing.T, filename, src string) *hcl.File {
+	f, diags := hclsyntax.ParseConfig([]byte(src), filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	return f
+}
+
+func parseJSON(t *testing.T, filename, src string) *hcl.File {
+	f, diags := json.Parse([]byte(src), filename)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	return f
+}
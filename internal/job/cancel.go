@@ -0,0 +1,26 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package job
+
+import (
+	"context"
+)
+
+// CancelWith wraps the function of a job, so that the job is cancelled
+// once ctx is done, in addition to the scheduler context it runs with.
+//
+// This allows cancelling a request (e.g. a command) to stop
+// the job it scheduled, rather than just stop waiting for it.
+func CancelWith(ctx context.Context, fn func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(jobCtx context.Context) error {
+		jobCtx, cancel := context.WithCancel(jobCtx)
+		defer cancel()
+		stop := context.AfterFunc(ctx, cancel)
+		defer stop()
+
+		return fn(jobCtx)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"log"
 	"path/filepath"
+	"sort"
 	"sync"
 
 	"github.com/hashicorp/hcl/v2"
@@ -59,12 +60,7 @@ func (n *Notifier) PublishHCLDiags(ctx context.Context, dirPath string, diags Di
 	default:
 	}
 
-	for filename, ds := range diags {
-		fileDiags := make([]lsp.Diagnostic, 0)
-		for source, diags := range ds {
-			fileDiags = append(fileDiags, ilsp.HCLDiagsToLSP(diags, source.String())...)
-		}
-
+	for filename, fileDiags := range diags.ToLSP() {
 		n.diags <- diagContext{
 			ctx:   ctx,
 			uri:   lsp.DocumentURI(uri.FromPath(filepath.Join(dirPath, filename))),
@@ -120,3 +116,27 @@ func (d Diagnostics) Extend(diags Diagnostics) Diagnostics {
 
 	return d
 }
+
+// ToLSP converts the diagnostics of each file to LSP diagnostics,
+// ordered by their source, so that the result is stable
+func (d Diagnostics) ToLSP() map[string][]lsp.Diagnostic {
+	lspDiags := make(map[string][]lsp.Diagnostic, len(d))
+
+	for filename, ds := range d {
+		sources := make([]ast.DiagnosticSource, 0, len(ds))
+		for source := range ds {
+			sources = append(sources, source)
+		}
+		sort.Slice(sources, func(i, j int) bool {
+			return sources[i] < sources[j]
+		})
+
+		fileDiags := make([]lsp.Diagnostic, 0)
+		for _, source := range sources {
+			fileDiags = append(fileDiags, ilsp.HCLDiagsToLSP(ds[source], source.String())...)
+		}
+		lspDiags[filename] = fileDiags
+	}
+
+	return lspDiags
+}
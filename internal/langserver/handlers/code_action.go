@@ -9,10 +9,11 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/opentofu/tofu-ls/internal/langserver/errors"
+	"github.com/opentofu/tofu-ls/internal/document"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
-	"github.com/opentofu/tofu-ls/internal/tofu/module"
+	"github.com/opentofu/tofu-ls/internal/rename"
+	"github.com/opentofu/tofu-ls/internal/uri"
 )
 
 func (svc *service) TextDocumentCodeAction(ctx context.Context, params lsp.CodeActionParams) []lsp.CodeAction {
@@ -28,21 +29,23 @@ func (svc *service) textDocumentCodeAction(ctx context.Context, params lsp.CodeA
 	var ca []lsp.CodeAction
 
 	// For action definitions, refer to https://code.visualstudio.com/api/references/vscode-api#CodeActionKind
-	// We only support format type code actions at the moment, and do not want to format without the client asking for
-	// them, so exit early here if nothing is requested.
-	if len(params.Context.Only) == 0 {
-		svc.logger.Printf("No code action requested, exiting")
-		return ca, nil
-	}
-
-	for _, o := range params.Context.Only {
-		svc.logger.Printf("Code actions requested: %q", o)
+	// We do not want to format without the client asking for it, so only
+	// refactorings are offered if no particular kind is requested.
+	wantedCodeActions := ilsp.CodeActions{
+		lsp.RefactorRewrite: true,
 	}
+	if len(params.Context.Only) == 0 {
+		svc.logger.Printf("No code action requested, offering refactorings")
+	} else {
+		for _, o := range params.Context.Only {
+			svc.logger.Printf("Code actions requested: %q", o)
+		}
 
-	wantedCodeActions := ilsp.SupportedCodeActions.Only(params.Context.Only)
-	if len(wantedCodeActions) == 0 {
-		return nil, fmt.Errorf("could not find a supported code action to execute for %s, wanted %v",
-			params.TextDocument.URI, params.Context.Only)
+		wantedCodeActions = ilsp.SupportedCodeActions.Only(params.Context.Only)
+		if len(wantedCodeActions) == 0 {
+			return nil, fmt.Errorf("could not find a supported code action to execute for %s, wanted %v",
+				params.TextDocument.URI, params.Context.Only)
+		}
 	}
 
 	svc.logger.Printf("Code actions supported: %v", wantedCodeActions)
@@ -57,12 +60,7 @@ func (svc *service) textDocumentCodeAction(ctx context.Context, params lsp.CodeA
 	for action := range wantedCodeActions {
 		switch action {
 		case ilsp.SourceFormatAllTofu:
-			tfExec, err := module.TofuExecutorForModule(ctx, dh.Dir.Path())
-			if err != nil {
-				return ca, errors.EnrichTfExecError(err)
-			}
-
-			edits, err := svc.formatDocument(ctx, tfExec, doc.Text, dh)
+			edits, err := svc.formatDocument(ctx, doc.Text, dh)
 			if err != nil {
 				return ca, err
 			}
@@ -76,8 +74,58 @@ func (svc *service) textDocumentCodeAction(ctx context.Context, params lsp.CodeA
 					},
 				},
 			})
+		case lsp.RefactorRewrite:
+			movedCa, err := svc.movedBlockCodeAction(ctx, doc, params.Range)
+			if err != nil {
+				return ca, err
+			}
+			if movedCa != nil {
+				ca = append(ca, *movedCa)
+			}
 		}
 	}
 
 	return ca, nil
 }
+
+// movedBlockCodeAction offers adding a moved block for a managed resource
+// or module call relabeled before, if the range starts at the label of its block
+func (svc *service) movedBlockCodeAction(ctx context.Context, doc *document.Document, rng lsp.Range) (*lsp.CodeAction, error) {
+	if ilsp.ParseLanguageID(doc.LanguageID) != ilsp.OpenTofu || svc.features == nil {
+		return nil, nil
+	}
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(doc.Dir)
+	if err != nil {
+		return nil, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	pos, err := ilsp.HCLPositionFromLspPosition(rng.Start, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	moves, err := svc.features.Modules.Moves(doc.Dir.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	move, edits, err := rename.MovedBlockEdits(svc.pathReader, moves, documentPath(doc), doc.Filename, pos)
+	if err != nil || move == nil {
+		return nil, err
+	}
+
+	changes := make(map[lsp.DocumentURI][]lsp.TextEdit, len(edits))
+	for filePath, fileEdits := range edits {
+		changes[lsp.DocumentURI(uri.FromPath(filePath))] = ilsp.TextEdits(fileEdits, false)
+	}
+
+	return &lsp.CodeAction{
+		Title: fmt.Sprintf("Add moved block from %s", move.From),
+		Kind:  lsp.RefactorRewrite,
+		Edit: lsp.WorkspaceEdit{
+			Changes: changes,
+		},
+	}, nil
+}
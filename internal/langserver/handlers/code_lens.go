@@ -8,7 +8,6 @@ package handlers
 import (
 	"context"
 
-	"github.com/hashicorp/hcl-lang/lang"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 )
@@ -28,10 +27,7 @@ func (svc *service) TextDocumentCodeLens(ctx context.Context, params lsp.CodeLen
 	}
 	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
 
-	path := lang.Path{
-		Path:       doc.Dir.Path(),
-		LanguageID: string(ilsp.ParseLanguageID(doc.LanguageID)),
-	}
+	path := documentPath(doc)
 
 	lenses, err := svc.decoder.CodeLensesForFile(ctx, path, doc.Filename)
 	if err != nil {
@@ -8,6 +8,7 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/opentofu/tofu-ls/internal/langserver/session"
 	"github.com/opentofu/tofu-ls/internal/state"
 	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/uri"
 	"github.com/opentofu/tofu-ls/internal/walker"
 	"github.com/stretchr/testify/mock"
 )
@@ -396,3 +398,167 @@ variable "instances" {
 			]
 	}`)
 }
+
+func TestCodeLens_lockFileProviderDeclarations(t *testing.T) {
+	tmpDir := TempDir(t)
+	rootDir := filepath.Join(tmpDir.Path(), "root")
+	rootURI := uri.FromPath(rootDir)
+	cfg := `terraform {
+  required_providers {
+    random = {
+      source  = "hashicorp/random"
+      version = "~> 3.0"
+    }
+  }
+}
+`
+	otherDir := filepath.Join(tmpDir.Path(), "other")
+	for _, dir := range []string{rootDir, otherDir} {
+		err := os.MkdirAll(dir, 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, "main.tf"), []byte(cfg), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	lockFile := `provider "registry.opentofu.org/hashicorp/random" {
+  version = "3.6.0"
+}
+`
+	err := os.WriteFile(filepath.Join(rootDir, ".terraform.lock.hcl"), []byte(lockFile), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			AnyWorkDir: validTfMockCalls(),
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"experimental": {
+				"showReferencesCommandId": "test.id"
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	// The other root module has its own lock file,
+	// so its declaration is not linked from this one
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, cfg, uri.FromPath(otherDir))})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu-lock",
+			"text": %q,
+			"uri": "%s/.terraform.lock.hcl"
+		}
+	}`, lockFile, rootURI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeLens",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/.terraform.lock.hcl"
+			}
+		}`, rootURI),
+	}, `{
+				"jsonrpc": "2.0",
+				"id": 4,
+				"result": [
+					{
+						"range": {
+							"start": {
+								"line": 0,
+								"character": 0
+							},
+							"end": {
+								"line": 2,
+								"character": 1
+							}
+						},
+						"command": {
+							"title": "1 declaration",
+							"command": "test.id",
+							"arguments": [
+								{
+									"line": 0,
+									"character": 9
+								},
+								{
+									"includeDeclaration": false
+								}
+							]
+						}
+					}
+				]
+	}`)
+
+	// The client shows the declarations by requesting references
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/references",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/.terraform.lock.hcl"
+			},
+			"position": {
+				"line": 0,
+				"character": 9
+			},
+			"context": {
+				"includeDeclaration": false
+			}
+		}`, rootURI),
+	}, fmt.Sprintf(`{
+				"jsonrpc": "2.0",
+				"id": 5,
+				"result": [
+					{
+						"uri": "%s/main.tf",
+						"range": {
+							"start": {
+								"line": 2,
+								"character": 4
+							},
+							"end": {
+								"line": 5,
+								"character": 5
+							}
+						}
+					}
+				]
+	}`, rootURI))
+}
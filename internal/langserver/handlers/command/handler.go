@@ -10,6 +10,7 @@ import (
 
 	fmodules "github.com/opentofu/tofu-ls/internal/features/modules"
 	frootmodules "github.com/opentofu/tofu-ls/internal/features/rootmodules"
+	ftests "github.com/opentofu/tofu-ls/internal/features/tests"
 	"github.com/opentofu/tofu-ls/internal/state"
 )
 
@@ -20,4 +21,5 @@ type CmdHandler struct {
 	// the features here?
 	ModulesFeature     *fmodules.ModulesFeature
 	RootModulesFeature *frootmodules.RootModulesFeature
+	TestsFeature       *ftests.TestsFeature
 }
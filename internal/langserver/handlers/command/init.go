@@ -8,12 +8,16 @@ package command
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/creachadair/jrpc2"
+	"github.com/opentofu/tofu-exec/tfexec"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
 	"github.com/opentofu/tofu-ls/internal/langserver/errors"
 	"github.com/opentofu/tofu-ls/internal/langserver/progress"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 	"github.com/opentofu/tofu-ls/internal/tofu/module"
 	"github.com/opentofu/tofu-ls/internal/uri"
 )
@@ -28,6 +32,11 @@ func (h *CmdHandler) TofuInitHandler(ctx context.Context, args cmd.CommandArgs)
 		return nil, fmt.Errorf("URI %q is not valid", dirUri)
 	}
 
+	opts, err := initOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
 	dirHandle := document.DirHandleFromURI(dirUri)
 	tfExec, err := module.TofuExecutorForModule(ctx, dirHandle.Path())
 	if err != nil {
@@ -40,10 +49,79 @@ func (h *CmdHandler) TofuInitHandler(ctx context.Context, args cmd.CommandArgs)
 	}()
 
 	progress.Report(ctx, "Running tofu init ...")
-	err = tfExec.Init(ctx)
+	mu := &sync.Mutex{}
+	stdout := &initOutput{ctx: ctx, mu: mu, msgType: lsp.Log}
+	stderr := &initOutput{ctx: ctx, mu: mu, msgType: lsp.Error}
+	err = tfExec.Init(ctx, stdout, stderr, opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	return nil, nil
 }
+
+// initOptions returns options of tofu init
+// corresponding to the given arguments
+func initOptions(args cmd.CommandArgs) ([]tfexec.InitOption, error) {
+	opts := make([]tfexec.InitOption, 0)
+
+	for _, name := range []string{"upgrade", "backend", "reconfigure"} {
+		if _, ok := args[name]; !ok {
+			continue
+		}
+		value, ok := args.GetBool(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected %s argument to be a boolean", jrpc2.InvalidParams.Err(), name)
+		}
+
+		switch name {
+		case "upgrade":
+			opts = append(opts, tfexec.Upgrade(value))
+		case "backend":
+			opts = append(opts, tfexec.Backend(value))
+		case "reconfigure":
+			opts = append(opts, tfexec.Reconfigure(value))
+		}
+	}
+
+	// Argument names are case-insensitive
+	if _, ok := args["plugindir"]; ok {
+		pluginDir, ok := args.GetString("plugindir")
+		if !ok || pluginDir == "" {
+			return nil, fmt.Errorf("%w: expected pluginDir argument to be a path", jrpc2.InvalidParams.Err())
+		}
+		opts = append(opts, tfexec.PluginDir(pluginDir))
+	}
+
+	return opts, nil
+}
+
+// initOutput reports each line of the output of tofu init
+// via $/progress and window/logMessage notifications
+type initOutput struct {
+	ctx     context.Context
+	msgType lsp.MessageType
+
+	// mu is shared by stdout and stderr, which are written concurrently
+	mu *sync.Mutex
+}
+
+func (o *initOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, line := range strings.Split(string(p), "\n") {
+		line = strings.TrimRight(line, "\r\t ")
+		if line == "" {
+			continue
+		}
+
+		progress.Report(o.ctx, line)
+		jrpc2.ServerFromContext(o.ctx).Notify(o.ctx, "window/logMessage", &lsp.LogMessageParams{
+			Type:    o.msgType,
+			Message: line,
+		})
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+func (h *CmdHandler) LoadPlanHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
+	dirUri, ok := args.GetString("uri")
+	if !ok || dirUri == "" {
+		return nil, fmt.Errorf("%w: expected module uri argument to be set", jrpc2.InvalidParams.Err())
+	}
+
+	if !uri.IsURIValid(dirUri) {
+		return nil, fmt.Errorf("URI %q is not valid", dirUri)
+	}
+
+	planPath, ok := args.GetString("path")
+	if !ok || planPath == "" {
+		return nil, fmt.Errorf("%w: expected plan path argument to be set", jrpc2.InvalidParams.Err())
+	}
+
+	dirHandle := document.DirHandleFromURI(dirUri)
+
+	if h.ModulesFeature == nil {
+		return nil, fmt.Errorf("modules feature is not available")
+	}
+	if !h.ModulesFeature.Store.Exists(dirHandle.Path()) {
+		return nil, fmt.Errorf("%s: module not indexed", dirHandle.Path())
+	}
+
+	return nil, h.ModulesFeature.LoadPlan(ctx, dirHandle.Path(), planPath)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
+	"github.com/opentofu/tofu-ls/internal/langserver/progress"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+func (h *CmdHandler) TofuPlanHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
+	validationOptions, err := lsctx.ValidationOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !validationOptions.EnablePlanValidation {
+		return nil, fmt.Errorf("%w: plan validation is disabled, set validation.enablePlanValidation to enable it", jrpc2.InvalidRequest.Err())
+	}
+
+	dirUri, ok := args.GetString("uri")
+	if !ok || dirUri == "" {
+		return nil, fmt.Errorf("%w: expected module uri argument to be set", jrpc2.InvalidParams.Err())
+	}
+
+	if !uri.IsURIValid(dirUri) {
+		return nil, fmt.Errorf("URI %q is not valid", dirUri)
+	}
+
+	dirHandle := document.DirHandleFromURI(dirUri)
+
+	if h.ModulesFeature == nil {
+		return nil, fmt.Errorf("modules feature is not available")
+	}
+	if !h.ModulesFeature.Store.Exists(dirHandle.Path()) {
+		return nil, fmt.Errorf("%s: module not indexed", dirHandle.Path())
+	}
+
+	progress.Begin(ctx, "Planning")
+	defer func() {
+		progress.End(ctx, "Finished")
+	}()
+
+	progress.Report(ctx, "Running tofu plan ...")
+	id, err := h.ModulesFeature.PlanModule(ctx, dirHandle.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.StateStore.JobStore.WaitForJobs(ctx, id)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/tests/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/tests/jobs"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
+	"github.com/opentofu/tofu-ls/internal/langserver/errors"
+	"github.com/opentofu/tofu-ls/internal/langserver/progress"
+	"github.com/opentofu/tofu-ls/internal/tofu/module"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+const tofuTestVersion = 0
+
+type tofuTestResponse struct {
+	FormatVersion int           `json:"v"`
+	Status        string        `json:"status"`
+	Runs          []tofuTestRun `json:"runs"`
+}
+
+type tofuTestRun struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func (h *CmdHandler) TofuTestHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
+	fileUri, ok := args.GetString("uri")
+	if !ok || fileUri == "" {
+		return nil, fmt.Errorf("%w: expected test file uri argument to be set", jrpc2.InvalidParams.Err())
+	}
+
+	if !uri.IsURIValid(fileUri) {
+		return nil, fmt.Errorf("URI %q is not valid", fileUri)
+	}
+
+	docHandle := document.HandleFromURI(fileUri)
+	if !ast.IsTestFilename(docHandle.Filename) {
+		return nil, fmt.Errorf("%w: %s is not a test file", jrpc2.InvalidParams.Err(), docHandle.Filename)
+	}
+	runName, _ := args.GetString("run")
+
+	if h.TestsFeature == nil {
+		return nil, fmt.Errorf("tests feature is not available")
+	}
+
+	tfExec, err := module.TofuExecutorForModule(ctx, fdecoder.ModuleUnderTestPath(docHandle.Dir.Path()))
+	if err != nil {
+		return nil, errors.EnrichTfExecError(err)
+	}
+
+	progress.Begin(ctx, "Testing")
+	defer func() {
+		progress.End(ctx, "Finished")
+	}()
+
+	progress.Report(ctx, "Running tofu test ...")
+	runs, err := h.TestsFeature.RunTests(ctx, tfExec, docHandle.Dir.Path(), docHandle.Filename, runName, func(run jobs.TestRun) {
+		progress.Report(ctx, fmt.Sprintf("run %q: %s", run.Name, run.Status))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if runName != "" && len(runs) == 0 {
+		return nil, fmt.Errorf("%s: run %q was not found", docHandle.Filename, runName)
+	}
+
+	response := tofuTestResponse{
+		FormatVersion: tofuTestVersion,
+		Status:        string(runs.Status()),
+		Runs:          make([]tofuTestRun, 0, len(runs)),
+	}
+	for _, run := range runs {
+		response.Runs = append(response.Runs, tofuTestRun{
+			Name:   run.Name,
+			Status: string(run.Status),
+		})
+	}
+
+	return response, nil
+}
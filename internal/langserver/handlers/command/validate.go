@@ -11,6 +11,7 @@ import (
 
 	"github.com/creachadair/jrpc2"
 	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/modules/jobs"
 	"github.com/opentofu/tofu-ls/internal/job"
 	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
 	"github.com/opentofu/tofu-ls/internal/langserver/progress"
@@ -30,6 +31,14 @@ func (h *CmdHandler) TofuValidateHandler(ctx context.Context, args cmd.CommandAr
 
 	dirHandle := document.DirHandleFromURI(dirUri)
 
+	if h.ModulesFeature == nil {
+		return nil, fmt.Errorf("modules feature is not available")
+	}
+	modStore := h.ModulesFeature.Store
+	if !modStore.Exists(dirHandle.Path()) {
+		return nil, fmt.Errorf("%s: module not indexed", dirHandle.Path())
+	}
+
 	progress.Begin(ctx, "Validating")
 	defer func() {
 		progress.End(ctx, "Finished")
@@ -38,9 +47,10 @@ func (h *CmdHandler) TofuValidateHandler(ctx context.Context, args cmd.CommandAr
 	progress.Report(ctx, "Running tofu validate ...")
 	id, err := h.StateStore.JobStore.EnqueueJob(ctx, job.Job{
 		Dir: dirHandle,
-		Func: func(ctx context.Context) error {
-			return nil //module.TofuValidate(ctx, h.StateStore.Modules, dirHandle.Path())
-		},
+		// Cancelling the command stops tofu validate
+		Func: job.CancelWith(ctx, func(ctx context.Context) error {
+			return jobs.TofuValidate(ctx, modStore, dirHandle.Path())
+		}),
 		Type:        op.OpTypeTofuValidate.String(),
 		IgnoreState: true,
 	})
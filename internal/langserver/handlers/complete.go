@@ -33,23 +33,30 @@ func (svc *service) TextDocumentComplete(ctx context.Context, params lsp.Complet
 	}
 	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
 
-	d, err := svc.decoderForDocument(ctx, doc)
+	pos, err := ilsp.HCLPositionFromLspPosition(params.TextDocumentPositionParams.Position, doc)
 	if err != nil {
 		return list, err
 	}
 
-	expFeatures, err := lsctx.ExperimentalFeatures(ctx)
+	if ilsp.IsValidTemplateLanguage(doc.LanguageID) {
+		// Templates have no schema, we can only complete
+		// variables passed to them by templatefile calls
+		candidates := svc.features.Templates.VariableCandidates(ctx, doc.Dir.Path(), doc.Filename, pos)
+		return ilsp.ToCompletionList(candidates, cc.TextDocument), nil
+	}
+
+	d, err := svc.decoderForDocument(ctx, doc)
 	if err != nil {
 		return list, err
 	}
 
-	d.PrefillRequiredFields = expFeatures.PrefillRequiredFields
-
-	pos, err := ilsp.HCLPositionFromLspPosition(params.TextDocumentPositionParams.Position, doc)
+	expFeatures, err := lsctx.ExperimentalFeatures(ctx)
 	if err != nil {
 		return list, err
 	}
 
+	d.PrefillRequiredFields = expFeatures.PrefillRequiredFields
+
 	svc.logger.Printf("Looking for candidates at %q -> %#v", doc.Filename, pos)
 	candidates, err := d.CompletionAtPos(ctx, doc.Filename, pos)
 	svc.logger.Printf("received candidates: %#v", candidates)
@@ -1964,3 +1964,110 @@ func writeContentToFile(t *testing.T, path string, content string) {
 		t.Fatal(err)
 	}
 }
+
+func TestCompletion_templateVariables(t *testing.T) {
+	tmpDir := TempDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "main.tf"), []byte(`locals {
+  user_data = templatefile("init.tftpl", {
+    hostname = "web"
+    port     = 8080
+  })
+}
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tplSrc := "listen ${}\n"
+	err = os.WriteFile(filepath.Join(tmpDir.Path(), "init.tftpl"), []byte(tplSrc), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu-template",
+			"text": %q,
+			"uri": "%s/init.tftpl"
+		}
+	}`, tplSrc, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/completion",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/init.tftpl"
+			},
+			"position": {
+				"character": 9,
+				"line": 0
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"isIncomplete": false,
+				"items": [
+					{
+						"label": "hostname",
+						"kind": 6,
+						"detail": "template variable",
+						"insertTextFormat": 1,
+						"textEdit": {
+							"range": {
+								"start": {"line": 0, "character": 9},
+								"end": {"line": 0, "character": 9}
+							},
+							"newText": "hostname"
+						}
+					},
+					{
+						"label": "port",
+						"kind": 6,
+						"detail": "template variable",
+						"insertTextFormat": 1,
+						"textEdit": {
+							"range": {
+								"start": {"line": 0, "character": 9},
+								"end": {"line": 0, "character": 9}
+							},
+							"newText": "port"
+						}
+					}
+				]
+			}
+		}`)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+// TextDocumentDiagnostic returns the diagnostics of a single document,
+// either as a full report, or as an unchanged one if the client
+// already has the diagnostics identified by the previous result ID
+func (svc *service) TextDocumentDiagnostic(ctx context.Context, params lsp.DocumentDiagnosticParams) (interface{}, error) {
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return nil, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	fileDiags, ok := svc.dirDiagnostics(dh.Dir.Path())[dh.Filename]
+	if !ok {
+		fileDiags = []lsp.Diagnostic{}
+	}
+	resultId := ilsp.DiagnosticsResultID(fileDiags)
+
+	if params.PreviousResultID != "" && params.PreviousResultID == resultId {
+		return lsp.RelatedUnchangedDocumentDiagnosticReport{
+			UnchangedDocumentDiagnosticReport: lsp.UnchangedDocumentDiagnosticReport{
+				Kind:     string(lsp.DiagnosticUnchanged),
+				ResultID: resultId,
+			},
+		}, nil
+	}
+
+	return lsp.RelatedFullDocumentDiagnosticReport{
+		FullDocumentDiagnosticReport: lsp.FullDocumentDiagnosticReport{
+			Kind:     string(lsp.DiagnosticFull),
+			ResultID: resultId,
+			Items:    fileDiags,
+		},
+	}, nil
+}
+
+// WorkspaceDiagnostic returns the diagnostics of all files in all indexed
+// modules, including those which are not open in the client
+func (svc *service) WorkspaceDiagnostic(ctx context.Context, params lsp.WorkspaceDiagnosticParams) (ilsp.WorkspaceDiagnosticReport, error) {
+	report := ilsp.WorkspaceDiagnosticReport{
+		Items: make([]interface{}, 0),
+	}
+
+	previousResultIds := make(map[lsp.DocumentURI]string, len(params.PreviousResultIds))
+	for _, prid := range params.PreviousResultIds {
+		previousResultIds[prid.URI] = prid.Value
+	}
+
+	for _, dirPath := range svc.indexedDirs(ctx) {
+		dirDiags := svc.dirDiagnostics(dirPath)
+
+		filenames := make([]string, 0, len(dirDiags))
+		for filename := range dirDiags {
+			if filename == "" {
+				continue
+			}
+			filenames = append(filenames, filename)
+		}
+		sort.Strings(filenames)
+
+		for _, filename := range filenames {
+			fileDiags := dirDiags[filename]
+			docUri := lsp.DocumentURI(uri.FromPath(filepath.Join(dirPath, filename)))
+			resultId := ilsp.DiagnosticsResultID(fileDiags)
+
+			var version *int32
+			doc, err := svc.stateStore.DocumentStore.GetDocument(document.HandleFromPath(filepath.Join(dirPath, filename)))
+			if err == nil {
+				v := int32(doc.Version)
+				version = &v
+			}
+
+			if previousResultIds[docUri] == resultId {
+				report.Items = append(report.Items, ilsp.WorkspaceUnchangedDocumentDiagnosticReport{
+					URI:     docUri,
+					Version: version,
+					UnchangedDocumentDiagnosticReport: lsp.UnchangedDocumentDiagnosticReport{
+						Kind:     string(lsp.DiagnosticUnchanged),
+						ResultID: resultId,
+					},
+				})
+				continue
+			}
+
+			report.Items = append(report.Items, ilsp.WorkspaceFullDocumentDiagnosticReport{
+				URI:     docUri,
+				Version: version,
+				FullDocumentDiagnosticReport: lsp.FullDocumentDiagnosticReport{
+					Kind:     string(lsp.DiagnosticFull),
+					ResultID: resultId,
+					Items:    fileDiags,
+				},
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// dirDiagnostics returns the LSP diagnostics of all files
+// in the directory, as known to the features
+func (svc *service) dirDiagnostics(dirPath string) map[string][]lsp.Diagnostic {
+	diags := diagnostics.NewDiagnostics()
+	for _, provider := range svc.features.DiagnosticsProviders() {
+		diags.Extend(provider.Diagnostics(dirPath))
+	}
+
+	return diags.ToLSP()
+}
+
+// indexedDirs returns the sorted paths of all directories
+// which are indexed by any of the features
+func (svc *service) indexedDirs(ctx context.Context) []string {
+	seen := make(map[string]bool)
+	dirs := make([]string, 0)
+
+	paths := make([]lang.Path, 0)
+	for _, provider := range svc.features.DiagnosticsProviders() {
+		paths = append(paths, provider.Paths(ctx)...)
+	}
+	for _, path := range paths {
+		if seen[path.Path] {
+			continue
+		}
+		seen[path.Path] = true
+		dirs = append(dirs, path.Path)
+	}
+	sort.Strings(dirs)
+
+	return dirs
+}
@@ -0,0 +1,235 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+const pullDiagnosticsCapabilities = `{
+	"textDocument": {
+		"diagnostic": {}
+	},
+	"workspace": {
+		"diagnostics": {}
+	}
+}`
+
+func TestTextDocumentDiagnostic(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	mainCfg := `variable "foo" {
+  default =
+}
+`
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "main.tf"), []byte(mainCfg), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": %s,
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, pullDiagnosticsCapabilities, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, mainCfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"kind": "full",
+				"resultId": "eb408b9e4d3f80e60c012838c4df5dfa",
+				"items": [
+					{
+						"range": {
+							"start": {"line": 1, "character": 11},
+							"end": {"line": 2, "character": 0}
+						},
+						"severity": 1,
+						"source": "OpenTofu",
+						"message": "Invalid expression: Expected the start of an expression, but found an invalid expression token."
+					}
+				]
+			}
+		}`)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"previousResultId": "eb408b9e4d3f80e60c012838c4df5dfa"
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"kind": "unchanged",
+				"resultId": "eb408b9e4d3f80e60c012838c4df5dfa"
+			}
+		}`)
+}
+
+func TestWorkspaceDiagnostic(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	childDir := filepath.Join(tmpDir.Path(), "child")
+	err := os.Mkdir(childDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(childDir, "main.tf"), []byte(`variable "foo" {
+  default =
+}
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainCfg := `module "child" {
+  source = "./child"
+}
+`
+	err = os.WriteFile(filepath.Join(tmpDir.Path(), "main.tf"), []byte(mainCfg), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+				childDir:      validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": %s,
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, pullDiagnosticsCapabilities, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 2,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, mainCfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"previousResultIds": [
+				{
+					"uri": "%s/main.tf",
+					"value": "4f53cda18c2baa0c0354bb5f9a3ecbe5"
+				}
+			]
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"items": [
+					{
+						"uri": "%s/main.tf",
+						"version": 2,
+						"kind": "unchanged",
+						"resultId": "4f53cda18c2baa0c0354bb5f9a3ecbe5"
+					},
+					{
+						"uri": "%s/child/main.tf",
+						"version": null,
+						"kind": "full",
+						"resultId": "eb408b9e4d3f80e60c012838c4df5dfa",
+						"items": [
+							{
+								"range": {
+									"start": {"line": 1, "character": 11},
+									"end": {"line": 2, "character": 0}
+								},
+								"severity": 1,
+								"source": "OpenTofu",
+								"message": "Invalid expression: Expected the start of an expression, but found an invalid expression token."
+							}
+						]
+					}
+				]
+			}
+		}`, tmpDir.URI, tmpDir.URI))
+}
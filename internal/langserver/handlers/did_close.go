@@ -14,5 +14,6 @@ import (
 
 func (svc *service) TextDocumentDidClose(ctx context.Context, params lsp.DidCloseTextDocumentParams) error {
 	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	svc.semTokensCache.Remove(dh)
 	return svc.stateStore.DocumentStore.CloseDocument(dh)
 }
@@ -24,12 +24,16 @@ func cmdHandlers(svc *service) cmd.Handlers {
 	if svc.features != nil {
 		cmdHandler.ModulesFeature = svc.features.Modules
 		cmdHandler.RootModulesFeature = svc.features.RootModules
+		cmdHandler.TestsFeature = svc.features.Tests
 	}
 	return cmd.Handlers{
 		cmd.Name("rootmodules"):      removedHandler("use module.callers instead"),
 		cmd.Name("module.callers"):   cmdHandler.ModuleCallersHandler,
 		cmd.Name("tofu.init"):        cmdHandler.TofuInitHandler,
 		cmd.Name("tofu.validate"):    cmdHandler.TofuValidateHandler,
+		cmd.Name("tofu.test"):        cmdHandler.TofuTestHandler,
+		cmd.Name("tofu.plan"):        cmdHandler.TofuPlanHandler,
+		cmd.Name("tofu.loadPlan"):    cmdHandler.LoadPlanHandler,
 		cmd.Name("module.calls"):     cmdHandler.ModuleCallsHandler,
 		cmd.Name("module.providers"): cmdHandler.ModuleProvidersHandler,
 		cmd.Name("module.opentofu"):  cmdHandler.TofuVersionRequestHandler,
@@ -52,6 +56,12 @@ func (svc *service) WorkspaceExecuteCommand(ctx context.Context, params lsp.Exec
 	}
 
 	ctx = lsctx.WithProgressToken(ctx, params.WorkDoneToken)
+	if params.WorkDoneToken != nil {
+		// Cancelling the progress cancels the command, e.g. stopping tofu
+		var cancel context.CancelFunc
+		ctx, cancel = svc.workDoneProgress.WithCancel(ctx, params.WorkDoneToken)
+		defer cancel()
+	}
 
 	return handler(ctx, cmd.ParseCommandArgs(params.Arguments))
 }
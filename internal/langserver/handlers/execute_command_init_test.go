@@ -6,14 +6,21 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/creachadair/jrpc2"
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/go-version"
+	"github.com/opentofu/tofu-exec/tfexec"
 	"github.com/opentofu/tofu-ls/internal/langserver"
 	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 	"github.com/opentofu/tofu-ls/internal/state"
 	"github.com/opentofu/tofu-ls/internal/tofu/exec"
 	"github.com/opentofu/tofu-ls/internal/walker"
@@ -103,6 +110,8 @@ func TestLangServer_workspaceExecuteCommand_init_basic(t *testing.T) {
 			Repeatability: 1,
 			Arguments: []interface{}{
 				mock.AnythingOfType(""),
+				mock.Anything,
+				mock.Anything,
 			},
 			ReturnArguments: []interface{}{
 				nil,
@@ -193,6 +202,8 @@ func TestLangServer_workspaceExecuteCommand_init_error(t *testing.T) {
 			Repeatability: 1,
 			Arguments: []interface{}{
 				mock.AnythingOfType(""),
+				mock.Anything,
+				mock.Anything,
 			},
 			ReturnArguments: []interface{}{
 				errors.New("something bad happened"),
@@ -249,3 +260,238 @@ func TestLangServer_workspaceExecuteCommand_init_error(t *testing.T) {
 		"arguments": ["uri=%s"]
 	}`, cmd.Name("tofu.init"), testFileURI)}, jrpc2.SystemError.Err())
 }
+
+func TestLangServer_workspaceExecuteCommand_init_streamOutput(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	tfMockCalls := []*mock.Call{
+		{
+			Method:        "Version",
+			Repeatability: 1,
+			Arguments: []interface{}{
+				mock.AnythingOfType(""),
+			},
+			ReturnArguments: []interface{}{
+				version.Must(version.NewVersion("0.12.0")),
+				nil,
+				nil,
+			},
+		},
+		{
+			Method:        "GetExecPath",
+			Repeatability: 1,
+			ReturnArguments: []interface{}{
+				"",
+			},
+		},
+		{
+			Method:        "Init",
+			Repeatability: 1,
+			Arguments: []interface{}{
+				mock.AnythingOfType(""),
+				mock.Anything,
+				mock.Anything,
+				tfexec.Upgrade(true),
+				tfexec.Backend(false),
+				tfexec.Reconfigure(true),
+				tfexec.PluginDir("/tmp/plugins"),
+			},
+			RunFn: func(args mock.Arguments) {
+				stdout, stderr := args.Get(1).(io.Writer), args.Get(2).(io.Writer)
+				io.WriteString(stdout, "Initializing provider plugins...\n")
+				io.WriteString(stderr, "Warning: Incomplete lock file information for providers\n")
+				io.WriteString(stdout, "\n")
+				io.WriteString(stdout, "- Installing hashicorp/random v3.6.0...\n")
+			},
+			ReturnArguments: []interface{}{
+				nil,
+			},
+		},
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): tfMockCalls,
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	var mu sync.Mutex
+	progressMessages := make([]string, 0)
+	logMessages := make([]lsp.LogMessageParams, 0)
+	ls.OnNotify(func(req *jrpc2.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch req.Method() {
+		case "$/progress":
+			var params struct {
+				Value lsp.WorkDoneProgressReport `json:"value"`
+			}
+			req.UnmarshalParams(&params)
+			if params.Value.Kind == "report" {
+				progressMessages = append(progressMessages, params.Value.Message)
+			}
+		case "window/logMessage":
+			var params lsp.LogMessageParams
+			req.UnmarshalParams(&params)
+			logMessages = append(logMessages, params)
+		}
+	})
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s", "upgrade=true", "backend=false", "reconfigure=true", "pluginDir=/tmp/plugins"],
+		"workDoneToken": "init-token"
+	}`, cmd.Name("tofu.init"), tmpDir.URI)}, `{
+		"jsonrpc": "2.0",
+		"id": 2,
+		"result": null
+	}`)
+
+	mu.Lock()
+	defer mu.Unlock()
+	expectedProgress := []string{
+		"Running tofu init ...",
+		"Initializing provider plugins...",
+		"Warning: Incomplete lock file information for providers",
+		"- Installing hashicorp/random v3.6.0...",
+	}
+	if diff := cmp.Diff(expectedProgress, progressMessages); diff != "" {
+		t.Fatalf("unexpected progress: %s", diff)
+	}
+	expectedLogs := []lsp.LogMessageParams{
+		{Type: lsp.Log, Message: "Initializing provider plugins..."},
+		{Type: lsp.Error, Message: "Warning: Incomplete lock file information for providers"},
+		{Type: lsp.Log, Message: "- Installing hashicorp/random v3.6.0..."},
+	}
+	if diff := cmp.Diff(expectedLogs, logMessages); diff != "" {
+		t.Fatalf("unexpected log messages: %s", diff)
+	}
+}
+
+func TestLangServer_workspaceExecuteCommand_init_cancel(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	cancelled := make(chan struct{})
+	tfMockCalls := []*mock.Call{
+		{
+			Method:        "Version",
+			Repeatability: 1,
+			Arguments: []interface{}{
+				mock.AnythingOfType(""),
+			},
+			ReturnArguments: []interface{}{
+				version.Must(version.NewVersion("0.12.0")),
+				nil,
+				nil,
+			},
+		},
+		{
+			Method:        "GetExecPath",
+			Repeatability: 1,
+			ReturnArguments: []interface{}{
+				"",
+			},
+		},
+		{
+			Method:        "Init",
+			Repeatability: 1,
+			Arguments: []interface{}{
+				mock.AnythingOfType(""),
+				mock.Anything,
+				mock.Anything,
+			},
+			RunFn: func(args mock.Arguments) {
+				ctx := args.Get(0).(context.Context)
+				io.WriteString(args.Get(1).(io.Writer), "Initializing the backend...\n")
+				select {
+				case <-ctx.Done():
+					close(cancelled)
+				case <-time.After(10 * time.Second):
+				}
+			},
+			ReturnArguments: []interface{}{
+				exec.ExecCanceledError("Init"),
+			},
+		},
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): tfMockCalls,
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	// Cancel the progress once tofu init is running
+	ls.OnNotify(func(req *jrpc2.Request) {
+		if req.Method() == "window/logMessage" {
+			go ls.Notify(t, &langserver.CallRequest{
+				Method:    "window/workDoneProgress/cancel",
+				ReqParams: `{"token": 42}`,
+			})
+		}
+	})
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	ls.CallAndExpectError(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s"],
+		"workDoneToken": 42
+	}`, cmd.Name("tofu.init"), tmpDir.URI)}, jrpc2.SystemError.Err())
+
+	select {
+	case <-cancelled:
+	default:
+		t.Fatal("expected tofu init to be cancelled")
+	}
+}
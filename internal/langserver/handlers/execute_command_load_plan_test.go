@@ -0,0 +1,378 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/filesystem"
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+var testPlanConfig = `resource "random_pet" "name" {
+  length    = 3
+  separator = "_"
+}
+
+resource "random_id" "server" {
+  count       = 2
+  byte_length = 8
+}
+`
+
+var testPlanJSON = `{
+  "format_version": "1.2",
+  "terraform_version": "1.8.0",
+  "resource_changes": [
+    {
+      "address": "random_pet.name",
+      "mode": "managed",
+      "type": "random_pet",
+      "name": "name",
+      "change": {
+        "actions": ["delete", "create"],
+        "before": {"id": "big-happy-cat", "length": 2, "separator": "-"},
+        "after": {"length": 3, "separator": "_"},
+        "after_unknown": {"id": true},
+        "before_sensitive": {},
+        "after_sensitive": {}
+      }
+    },
+    {
+      "address": "random_id.server[0]",
+      "mode": "managed",
+      "type": "random_id",
+      "name": "server",
+      "index": 0,
+      "change": {
+        "actions": ["no-op"],
+        "before": {"byte_length": 8, "id": "abc"},
+        "after": {"byte_length": 8, "id": "abc"},
+        "after_unknown": {},
+        "before_sensitive": {},
+        "after_sensitive": {}
+      }
+    },
+    {
+      "address": "random_id.server[1]",
+      "mode": "managed",
+      "type": "random_id",
+      "name": "server",
+      "index": 1,
+      "change": {
+        "actions": ["create"],
+        "before": null,
+        "after": {"byte_length": 8},
+        "after_unknown": {"id": true},
+        "before_sensitive": false,
+        "after_sensitive": {}
+      }
+    }
+  ]
+}`
+
+func TestLangServer_workspaceExecuteCommand_loadPlan(t *testing.T) {
+	tmpDir := TempDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "main.tf"), []byte(testPlanConfig), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(tmpDir.Path(), "plan.json"), []byte(testPlanJSON), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventBus := eventbus.NewEventBus()
+	mockCalls := &exec.TofuMockCalls{
+		PerWorkDir: map[string][]*mock.Call{
+			tmpDir.Path(): validTfMockCalls(),
+		},
+	}
+	fs := filesystem.NewFilesystem(ss.DocumentStore)
+	features, err := NewTestFeatures(eventBus, ss, fs, mockCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	features.Modules.Start(ctx)
+	defer features.Modules.Stop()
+	features.RootModules.Start(ctx)
+	defer features.RootModules.Stop()
+
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls:       mockCalls,
+		StateStore:      ss,
+		WalkerCollector: wc,
+		Features:        features,
+		EventBus:        eventBus,
+		FileSystem:      fs,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+			"textDocument": {
+				"hover": {
+					"contentFormat": ["markdown"]
+				}
+			}
+		},
+	    "rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, testPlanConfig, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s", "path=plan.json"]
+	}`, cmd.Name("tofu.loadPlan"), tmpDir.URI)}, `{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": null
+	}`)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeLens",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, tmpDir.URI),
+	}, `{
+		"jsonrpc": "2.0",
+		"id": 4,
+		"result": [
+			{
+				"range": {
+					"start": {"line": 0, "character": 0},
+					"end": {"line": 0, "character": 28}
+				},
+				"command": {
+					"title": "-/+ destroy and then create replacement (3 attributes)",
+					"command": ""
+				}
+			},
+			{
+				"range": {
+					"start": {"line": 5, "character": 0},
+					"end": {"line": 5, "character": 29}
+				},
+				"command": {
+					"title": "[1] + create",
+					"command": ""
+				}
+			}
+		]
+	}`)
+
+	hoverNote := "Planned changes of `separator`:\n\n" +
+		"- `random_pet.name` (-/+ destroy and then create replacement (3 attributes)): `\"-\"` → `\"_\"`"
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/hover",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 2,
+				"character": 4
+			}
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 5,
+			"result": {
+				"contents": {
+					"kind": "markdown",
+					"value": %q
+				},
+				"range": {
+					"start": {"line": 2, "character": 2},
+					"end": {"line": 2, "character": 17}
+				}
+			}
+		}`, hoverNote))
+}
+
+func TestLangServer_workspaceExecuteCommand_loadPlanChildModule(t *testing.T) {
+	tmpDir := TempDir(t)
+	rootCfg := `module "db" {
+  source = "./db"
+}
+`
+	dbCfg := `resource "random_password" "main" {
+  length = 24
+}
+`
+	planJSON := `{
+  "format_version": "1.2",
+  "terraform_version": "1.8.0",
+  "resource_changes": [
+    {
+      "address": "module.db.random_password.main",
+      "module_address": "module.db",
+      "mode": "managed",
+      "type": "random_password",
+      "name": "main",
+      "change": {
+        "actions": ["update"],
+        "before": {"length": 16, "result": "secret"},
+        "after": {"length": 24},
+        "after_unknown": {"result": true},
+        "before_sensitive": {"result": true},
+        "after_sensitive": {"result": true}
+      }
+    }
+  ]
+}`
+	manifest := `{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"db","Source":"./db","Dir":"db"}]}`
+	files := map[string]string{
+		"main.tf":                      rootCfg,
+		filepath.Join("db", "main.tf"): dbCfg,
+		"plan.json":                    planJSON,
+		filepath.Join(".terraform", "modules", "modules.json"): manifest,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir.Path(), name)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventBus := eventbus.NewEventBus()
+	mockCalls := &exec.TofuMockCalls{
+		PerWorkDir: map[string][]*mock.Call{
+			tmpDir.Path(): validTfMockCalls(),
+		},
+	}
+	fs := filesystem.NewFilesystem(ss.DocumentStore)
+	features, err := NewTestFeatures(eventBus, ss, fs, mockCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	features.Modules.Start(ctx)
+	defer features.Modules.Stop()
+	features.RootModules.Start(ctx)
+	defer features.RootModules.Stop()
+
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls:       mockCalls,
+		StateStore:      ss,
+		WalkerCollector: wc,
+		Features:        features,
+		EventBus:        eventBus,
+		FileSystem:      fs,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/db/main.tf"
+		}
+	}`, dbCfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s", "path=plan.json"]
+	}`, cmd.Name("tofu.loadPlan"), tmpDir.URI)}, `{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": null
+	}`)
+
+	// the change is shown in the child module, labelled with its address
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeLens",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/db/main.tf"
+			}
+		}`, tmpDir.URI),
+	}, `{
+		"jsonrpc": "2.0",
+		"id": 4,
+		"result": [
+			{
+				"range": {
+					"start": {"line": 0, "character": 0},
+					"end": {"line": 0, "character": 33}
+				},
+				"command": {
+					"title": "module.db.random_password.main ~ update in-place (2 attributes)",
+					"command": ""
+				}
+			}
+		]
+	}`)
+}
@@ -0,0 +1,367 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/filesystem"
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_workspaceExecuteCommand_plan_disabled(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	ls.CallAndExpectError(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s"]
+	}`, cmd.Name("tofu.plan"), tmpDir.URI)}, jrpc2.InvalidRequest.Err())
+}
+
+func TestLangServer_workspaceExecuteCommand_plan_basic(t *testing.T) {
+	tmpDir := TempDir(t)
+	testFileURI := fmt.Sprintf("%s/main.tf", tmpDir.URI)
+	ctx := context.Background()
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventBus := eventbus.NewEventBus()
+	mockCalls := &exec.TofuMockCalls{
+		PerWorkDir: map[string][]*mock.Call{
+			tmpDir.Path(): validTfMockCalls(),
+		},
+		// tofu plan runs in a scratch directory mirroring the module
+		AnyWorkDir: []*mock.Call{
+			{
+				Method:        "Plan",
+				Repeatability: 1,
+				Arguments: []interface{}{
+					mock.AnythingOfType(""),
+					mock.Anything,
+					mock.Anything,
+				},
+				ReturnArguments: []interface{}{
+					[]tfjson.Diagnostic{
+						{
+							Severity: tfjson.DiagnosticSeverityError,
+							Summary:  "Invalid count argument",
+							Range: &tfjson.Range{
+								Filename: "main.tf",
+								Start:    tfjson.Pos{Line: 1, Column: 1, Byte: 0},
+								End:      tfjson.Pos{Line: 1, Column: 18, Byte: 17},
+							},
+						},
+					},
+					nil,
+				},
+			},
+		},
+	}
+	fs := filesystem.NewFilesystem(ss.DocumentStore)
+	features, err := NewTestFeatures(eventBus, ss, fs, mockCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	features.Modules.Start(ctx)
+	defer features.Modules.Stop()
+	features.RootModules.Start(ctx)
+	defer features.RootModules.Stop()
+	features.Variables.Start(ctx)
+	defer features.Variables.Stop()
+
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls:       mockCalls,
+		StateStore:      ss,
+		WalkerCollector: wc,
+		Features:        features,
+		EventBus:        eventBus,
+		FileSystem:      fs,
+	}))
+	// published diagnostics of main.tf, reduced to their messages
+	published := make(chan []string, 100)
+	ls.OnNotify(func(req *jrpc2.Request) {
+		if req.Method() != "textDocument/publishDiagnostics" {
+			return
+		}
+		var params lsp.PublishDiagnosticsParams
+		req.UnmarshalParams(&params)
+		if string(params.URI) != testFileURI {
+			return
+		}
+		messages := make([]string, 0, len(params.Diagnostics))
+		for _, diag := range params.Diagnostics {
+			messages = append(messages, diag.Message)
+		}
+		published <- messages
+	})
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345,
+		"initializationOptions": {
+			"validation": {
+				"enablePlanValidation": true
+			}
+		}
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "resource \"x\" \"y\" {}",
+			"uri": %q
+		}
+	}`, testFileURI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s"]
+	}`, cmd.Name("tofu.plan"), tmpDir.URI)}, `{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": null
+	}`)
+
+	diags := features.Modules.Diagnostics(tmpDir.Path())
+	planDiags := diags["main.tf"][ast.TofuPlanSource]
+	if len(planDiags) != 1 {
+		t.Fatalf("expected 1 tofu plan diagnostic, given: %#v", diags)
+	}
+	if summary := planDiags[0].Summary; summary != "Invalid count argument" {
+		t.Fatalf("unexpected diagnostic summary: %q", summary)
+	}
+	waitForPublishedDiagnostics(t, published, func(messages []string) bool {
+		return slices.Contains(messages, "Invalid count argument")
+	})
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didChange",
+		ReqParams: fmt.Sprintf(`{
+    "textDocument": {
+        "version": 1,
+        "uri": %q
+    },
+    "contentChanges": [
+        {
+            "text": "resource \"x\" \"z\" {}"
+        }
+    ]
+}`, testFileURI)})
+	waitForAllJobs(t, ss)
+
+	mod, err := features.Modules.Store.ModuleRecordByPath(tmpDir.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := mod.ModuleDiagnostics[ast.TofuPlanSource].Count(); count != 0 {
+		t.Fatalf("expected tofu plan diagnostics to be cleared, %d remaining", count)
+	}
+	waitForPublishedDiagnostics(t, published, func(messages []string) bool {
+		return !slices.Contains(messages, "Invalid count argument")
+	})
+}
+
+// waitForPublishedDiagnostics waits until diagnostics
+// matching the given function are published
+func waitForPublishedDiagnostics(t *testing.T, published <-chan []string, match func(messages []string) bool) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case messages := <-published:
+			if match(messages) {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for published diagnostics")
+		}
+	}
+}
+
+func TestLangServer_workspaceExecuteCommand_plan_cancel(t *testing.T) {
+	tmpDir := TempDir(t)
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventBus := eventbus.NewEventBus()
+	mockCalls := &exec.TofuMockCalls{
+		PerWorkDir: map[string][]*mock.Call{
+			tmpDir.Path(): validTfMockCalls(),
+		},
+		AnyWorkDir: []*mock.Call{
+			{
+				Method:        "Plan",
+				Repeatability: 1,
+				Arguments: []interface{}{
+					mock.AnythingOfType(""),
+					mock.Anything,
+					mock.Anything,
+				},
+				RunFn: func(args mock.Arguments) {
+					ctx := args.Get(0).(context.Context)
+					close(started)
+					select {
+					case <-ctx.Done():
+						close(cancelled)
+					case <-time.After(10 * time.Second):
+					}
+				},
+				ReturnArguments: []interface{}{
+					[]tfjson.Diagnostic{},
+					exec.ExecCanceledError("Plan"),
+				},
+			},
+		},
+	}
+	fs := filesystem.NewFilesystem(ss.DocumentStore)
+	features, err := NewTestFeatures(eventBus, ss, fs, mockCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	features.Modules.Start(ctx)
+	defer features.Modules.Stop()
+	features.RootModules.Start(ctx)
+	defer features.RootModules.Stop()
+	features.Variables.Start(ctx)
+	defer features.Variables.Stop()
+
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls:       mockCalls,
+		StateStore:      ss,
+		WalkerCollector: wc,
+		Features:        features,
+		EventBus:        eventBus,
+		FileSystem:      fs,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345,
+		"initializationOptions": {
+			"validation": {
+				"enablePlanValidation": true
+			}
+		}
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "resource \"x\" \"y\" {}",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// Cancel the progress once tofu plan is running
+	go func() {
+		<-started
+		ls.Notify(t, &langserver.CallRequest{
+			Method:    "window/workDoneProgress/cancel",
+			ReqParams: `{"token": 42}`,
+		})
+	}()
+
+	ls.CallAndExpectError(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s"],
+		"workDoneToken": 42
+	}`, cmd.Name("tofu.plan"), tmpDir.URI)}, context.Canceled)
+
+	// The plan runs as a job, which is cancelled along with the command
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected tofu plan to be cancelled")
+	}
+}
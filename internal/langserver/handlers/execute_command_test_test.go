@@ -0,0 +1,217 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/filesystem"
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+var testTofuTestFile = `run "first" {
+  assert {
+    condition     = true
+    error_message = "unexpected"
+  }
+}
+
+run "second" {
+  assert {
+    condition     = 1 == 2
+    error_message = "unexpected"
+  }
+}
+`
+
+var testTofuTestOutput = `{"@level":"info","@message":"Found 1 file and 2 run blocks","test_abstract":{"tests/main.tftest.hcl":["first","second"]},"type":"test_abstract"}
+{"@level":"info","@message":"  \"first\"... pass","@testfile":"tests/main.tftest.hcl","@testrun":"first","test_run":{"path":"tests/main.tftest.hcl","run":"first","status":"pass"},"type":"test_run"}
+{"@level":"error","@message":"Error: Test assertion failed","@testfile":"tests/main.tftest.hcl","@testrun":"second","diagnostic":{"severity":"error","summary":"Test assertion failed","detail":"unexpected","range":{"filename":"tests/main.tftest.hcl","start":{"line":10,"column":21,"byte":136},"end":{"line":10,"column":27,"byte":142}}},"type":"diagnostic"}
+{"@level":"info","@message":"  \"second\"... fail","@testfile":"tests/main.tftest.hcl","@testrun":"second","test_run":{"path":"tests/main.tftest.hcl","run":"second","status":"fail"},"type":"test_run"}
+{"@level":"info","@message":"Failure! 1 passed, 1 failed.","test_summary":{"status":"fail","passed":1,"failed":1,"errored":0,"skipped":0},"type":"test_summary"}
+`
+
+func TestLangServer_workspaceExecuteCommand_test_basic(t *testing.T) {
+	tmpDir := TempDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "main.tf"), []byte("output \"name\" {\n  value = \"foo\"\n}\n"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testsPath := filepath.Join(tmpDir.Path(), "tests")
+	err = os.Mkdir(testsPath, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFileURI := fmt.Sprintf("%s/tests/main.tftest.hcl", tmpDir.URI)
+	ctx := context.Background()
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventBus := eventbus.NewEventBus()
+	mockCalls := &exec.TofuMockCalls{
+		PerWorkDir: map[string][]*mock.Call{
+			tmpDir.Path(): append(validTfMockCalls(), &mock.Call{
+				Method:        "Test",
+				Repeatability: 1,
+				Arguments: []interface{}{
+					mock.Anything,
+					mock.Anything,
+					"tests/main.tftest.hcl",
+				},
+				RunFn: func(args mock.Arguments) {
+					io.WriteString(args.Get(1).(io.Writer), testTofuTestOutput)
+				},
+				// tofu test exits with an error when tests fail
+				ReturnArguments: []interface{}{
+					errors.New("exit status 1"),
+				},
+			}),
+		},
+	}
+	fs := filesystem.NewFilesystem(ss.DocumentStore)
+	features, err := NewTestFeatures(eventBus, ss, fs, mockCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	features.Modules.Start(ctx)
+	defer features.Modules.Stop()
+	features.RootModules.Start(ctx)
+	defer features.RootModules.Stop()
+	features.Tests.Start(ctx)
+	defer features.Tests.Stop()
+
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls:       mockCalls,
+		StateStore:      ss,
+		WalkerCollector: wc,
+		Features:        features,
+		EventBus:        eventBus,
+		FileSystem:      fs,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345,
+		"initializationOptions": {
+			"commandPrefix": "client1"
+		}
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu-test",
+			"text": %q,
+			"uri": %q
+		}
+	}`, testTofuTestFile, testFileURI)})
+	waitForAllJobs(t, ss)
+
+	testCmd := "client1." + cmd.Name("tofu.test")
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeLens",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": %q
+			}
+		}`, testFileURI),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": [
+			{
+				"range": {
+					"start": {"line": 0, "character": 0},
+					"end": {"line": 0, "character": 0}
+				},
+				"command": {
+					"title": "Run all tests",
+					"command": %[1]q,
+					"arguments": ["uri=%[2]s"]
+				}
+			},
+			{
+				"range": {
+					"start": {"line": 0, "character": 0},
+					"end": {"line": 0, "character": 11}
+				},
+				"command": {
+					"title": "Run test",
+					"command": %[1]q,
+					"arguments": ["uri=%[2]s", "run=first"]
+				}
+			},
+			{
+				"range": {
+					"start": {"line": 7, "character": 0},
+					"end": {"line": 7, "character": 12}
+				},
+				"command": {
+					"title": "Run test",
+					"command": %[1]q,
+					"arguments": ["uri=%[2]s", "run=second"]
+				}
+			}
+		]
+	}`, testCmd, testFileURI))
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s"]
+	}`, testCmd, testFileURI)}, `{
+		"jsonrpc": "2.0",
+		"id": 4,
+		"result": {
+			"v": 0,
+			"status": "fail",
+			"runs": [
+				{"name": "first", "status": "pass"},
+				{"name": "second", "status": "fail"}
+			]
+		}
+	}`)
+
+	diags := features.Tests.Diagnostics(testsPath)["main.tftest.hcl"][ast.TofuTestSource]
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 tofu test diagnostic, given: %#v", diags)
+	}
+	if diags[0].Summary != "Test assertion failed" {
+		t.Fatalf("unexpected diagnostic summary: %q", diags[0].Summary)
+	}
+	if start := diags[0].Subject.Start; start.Line != 10 || start.Column != 21 {
+		t.Fatalf("expected diagnostic on the condition, given: %#v", diags[0].Subject)
+	}
+}
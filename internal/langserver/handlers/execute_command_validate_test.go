@@ -6,13 +6,18 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/creachadair/jrpc2"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/filesystem"
 	"github.com/opentofu/tofu-ls/internal/langserver"
 	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
 	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/ast"
 	"github.com/opentofu/tofu-ls/internal/tofu/exec"
 	"github.com/opentofu/tofu-ls/internal/walker"
 	"github.com/stretchr/testify/mock"
@@ -70,3 +75,136 @@ func TestLangServer_workspaceExecuteCommand_validate_argumentError(t *testing.T)
 		"command": %q
 	}`, cmd.Name("tofu.validate"))}, jrpc2.InvalidParams.Err())
 }
+
+func TestLangServer_workspaceExecuteCommand_validate_basic(t *testing.T) {
+	tmpDir := TempDir(t)
+	testFileURI := fmt.Sprintf("%s/main.tf", tmpDir.URI)
+	ctx := context.Background()
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventBus := eventbus.NewEventBus()
+	mockCalls := &exec.TofuMockCalls{
+		PerWorkDir: map[string][]*mock.Call{
+			tmpDir.Path(): {
+				{
+					Method:        "Validate",
+					Repeatability: 1,
+					Arguments: []interface{}{
+						mock.AnythingOfType(""),
+					},
+					ReturnArguments: []interface{}{
+						[]tfjson.Diagnostic{
+							{
+								Severity: tfjson.DiagnosticSeverityError,
+								Summary:  "Missing required argument",
+								Range: &tfjson.Range{
+									Filename: "main.tf",
+									Start:    tfjson.Pos{Line: 1, Column: 1, Byte: 0},
+									End:      tfjson.Pos{Line: 1, Column: 18, Byte: 17},
+								},
+							},
+						},
+						nil,
+					},
+				},
+			},
+		},
+	}
+	fs := filesystem.NewFilesystem(ss.DocumentStore)
+	features, err := NewTestFeatures(eventBus, ss, fs, mockCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	features.Modules.Start(ctx)
+	defer features.Modules.Stop()
+	features.RootModules.Start(ctx)
+	defer features.RootModules.Stop()
+	features.Variables.Start(ctx)
+	defer features.Variables.Stop()
+
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls:       mockCalls,
+		StateStore:      ss,
+		WalkerCollector: wc,
+		Features:        features,
+		EventBus:        eventBus,
+		FileSystem:      fs,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "resource \"x\" \"y\" {}",
+			"uri": %q
+		}
+	}`, testFileURI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s"]
+	}`, cmd.Name("tofu.validate"), tmpDir.URI)}, `{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": null
+	}`)
+
+	mod, err := features.Modules.Store.ModuleRecordByPath(tmpDir.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateDiags := mod.ModuleDiagnostics[ast.TofuValidateSource]
+	if validateDiags.Count() != 1 {
+		t.Fatalf("expected 1 tofu validate diagnostic, given: %#v", validateDiags)
+	}
+	if summary := validateDiags["main.tf"][0].Summary; summary != "Missing required argument" {
+		t.Fatalf("unexpected diagnostic summary: %q", summary)
+	}
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didChange",
+		ReqParams: fmt.Sprintf(`{
+    "textDocument": {
+        "version": 1,
+        "uri": %q
+    },
+    "contentChanges": [
+        {
+            "text": "resource \"x\" \"z\" {}"
+        }
+    ]
+}`, testFileURI)})
+	waitForAllJobs(t, ss)
+
+	mod, err = features.Modules.Store.ModuleRecordByPath(tmpDir.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := mod.ModuleDiagnostics[ast.TofuValidateSource].Count(); count != 0 {
+		t.Fatalf("expected tofu validate diagnostics to be cleared, %d remaining", count)
+	}
+}
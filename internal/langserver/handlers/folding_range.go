@@ -0,0 +1,84 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/folding"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+func (svc *service) TextDocumentFoldingRange(ctx context.Context, params lsp.FoldingRangeParams) ([]lsp.FoldingRange, error) {
+	ranges := make([]lsp.FoldingRange, 0)
+
+	cc, err := ilsp.ClientCapabilities(ctx)
+	if err != nil {
+		return ranges, err
+	}
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return ranges, err
+	}
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return ranges, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	file, ok := svc.parsedFile(doc)
+	if !ok {
+		return ranges, nil
+	}
+
+	limit := int(cc.TextDocument.FoldingRange.RangeLimit)
+	for _, rng := range folding.Ranges(file) {
+		if limit > 0 && len(ranges) >= limit {
+			break
+		}
+		ranges = append(ranges, foldingRangeToLSP(rng))
+	}
+
+	return ranges, nil
+}
+
+func foldingRangeToLSP(rng folding.Range) lsp.FoldingRange {
+	fr := lsp.FoldingRange{
+		StartLine: uint32(rng.StartLine - 1),
+		EndLine:   uint32(rng.EndLine - 1),
+	}
+	if rng.Kind == folding.RangeKindComment {
+		fr.Kind = string(lsp.Comment)
+	}
+	return fr
+}
+
+// parsedFile returns the parsed file of the document. Using it rather
+// than decoding against the schema allows syntax-based features
+// to work in any file, regardless of the schema.
+func (svc *service) parsedFile(doc *document.Document) (*hcl.File, bool) {
+	switch ilsp.ParseLanguageID(doc.LanguageID) {
+	case ilsp.OpenTofu:
+		return svc.features.Modules.ParsedFile(doc.Dir.Path(), doc.Filename)
+	case ilsp.OpenTofuVars:
+		return svc.features.Variables.ParsedFile(doc.Dir.Path(), doc.Filename)
+	case ilsp.OpenTofuTest, ilsp.OpenTofuMock:
+		return svc.features.Tests.ParsedFile(doc.Dir.Path(), doc.Filename)
+	case ilsp.OpenTofuBackend:
+		return svc.features.Backends.ParsedFile(doc.Dir.Path(), doc.Filename)
+	case ilsp.OpenTofuCLIConfig:
+		return svc.features.CLIConfig.ParsedFile(doc.Dir.Path(), doc.Filename)
+	case ilsp.OpenTofuLock:
+		return svc.features.LockFile.ParsedFile(doc.Dir.Path(), doc.Filename)
+	}
+	return nil, false
+}
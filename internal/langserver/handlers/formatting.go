@@ -6,15 +6,18 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"time"
 
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/format"
 	"github.com/opentofu/tofu-ls/internal/hcl"
 	"github.com/opentofu/tofu-ls/internal/langserver/errors"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
-	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/settings"
 	"github.com/opentofu/tofu-ls/internal/tofu/module"
 )
 
@@ -23,17 +26,12 @@ func (svc *service) TextDocumentFormatting(ctx context.Context, params lsp.Docum
 
 	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
 
-	tfExec, err := module.TofuExecutorForModule(ctx, dh.Dir.Path())
-	if err != nil {
-		return edits, errors.EnrichTfExecError(err)
-	}
-
 	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
 	if err != nil {
 		return edits, err
 	}
 
-	edits, err = svc.formatDocument(ctx, tfExec, doc.Text, dh)
+	edits, err = svc.formatDocument(ctx, doc.Text, dh)
 	if err != nil {
 		return edits, err
 	}
@@ -41,20 +39,139 @@ func (svc *service) TextDocumentFormatting(ctx context.Context, params lsp.Docum
 	return edits, nil
 }
 
-func (svc *service) formatDocument(ctx context.Context, tfExec exec.TofuExecutor, original []byte, dh document.Handle) ([]lsp.TextEdit, error) {
+func (svc *service) formatDocument(ctx context.Context, original []byte, dh document.Handle) ([]lsp.TextEdit, error) {
 	var edits []lsp.TextEdit
 
+	formatted, err := svc.formattedSource(ctx, original, dh)
+	if err != nil {
+		return edits, err
+	}
+
+	changes := hcl.Diff(dh, original, formatted)
+
+	return ilsp.TextEditsFromDocumentChanges(changes), nil
+}
+
+func (svc *service) formattedSource(ctx context.Context, original []byte, dh document.Handle) ([]byte, error) {
+	// We can safely ignore the error here and use
+	// the default (CLI) formatting if no options were set.
+	opts, _ := lsctx.FormattingOptions(ctx)
+
+	if opts.Mode == settings.FormattingModeNative {
+		startTime := time.Now()
+		formatted, err := format.Format(original, dh.Filename)
+		if err != nil {
+			svc.logger.Printf("Failed native formatting in %s", time.Since(startTime))
+			return nil, err
+		}
+		svc.logger.Printf("Finished native formatting in %s", time.Since(startTime))
+		return formatted, nil
+	}
+
+	tfExec, err := module.TofuExecutorForModule(ctx, dh.Dir.Path())
+	if err != nil {
+		return nil, errors.EnrichTfExecError(err)
+	}
+
 	svc.logger.Printf("formatting document via %q", tfExec.GetExecPath())
 
 	startTime := time.Now()
 	formatted, err := tfExec.Format(ctx, original)
 	if err != nil {
 		svc.logger.Printf("Failed 'tofu fmt' in %s", time.Since(startTime))
-		return edits, err
+		return nil, err
 	}
 	svc.logger.Printf("Finished 'tofu fmt' in %s", time.Since(startTime))
 
-	changes := hcl.Diff(dh, original, formatted)
+	return formatted, nil
+}
+
+func (svc *service) TextDocumentRangeFormatting(ctx context.Context, params lsp.DocumentRangeFormattingParams) ([]lsp.TextEdit, error) {
+	var edits []lsp.TextEdit
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return edits, err
+	}
+
+	// Only top-level blocks and attributes can be formatted
+	// in isolation, so we format all which intersect with the range.
+	lines := format.LineRange{
+		Start: int(params.Range.Start.Line) + 1,
+		End:   int(params.Range.End.Line) + 1,
+	}
+	if params.Range.End.Character == 0 && params.Range.End.Line > params.Range.Start.Line {
+		// the selection ends at the beginning of the line
+		lines.End--
+	}
+	itemLines, ok := format.TopLevelLines(doc.Text, dh.Filename, lines)
+	if !ok {
+		return edits, nil
+	}
+
+	formatted, err := svc.formattedSource(ctx, format.Lines(doc.Text, itemLines), dh)
+	if err != nil {
+		return edits, err
+	}
+
+	after := format.ReplaceLines(doc.Text, itemLines, formatted)
+	changes := hcl.Diff(dh, doc.Text, after)
+
+	return ilsp.TextEditsFromDocumentChanges(changes), nil
+}
+
+func (svc *service) TextDocumentOnTypeFormatting(ctx context.Context, params lsp.DocumentOnTypeFormattingParams) ([]lsp.TextEdit, error) {
+	var edits []lsp.TextEdit
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return edits, err
+	}
+
+	pos, err := ilsp.HCLPositionFromLspPosition(params.Position, doc)
+	if err != nil {
+		return edits, err
+	}
+
+	// Find the block which was just closed, either by typing
+	// the closing brace, or a newline after it.
+	var blockLines, topLines format.LineRange
+	var ok bool
+	switch params.Ch {
+	case "}":
+		blockLines, topLines, ok = format.ClosedBlockLines(doc.Text, dh.Filename, pos.Line, pos.Byte)
+	case "\n":
+		blockLines, topLines, ok = format.ClosedBlockLines(doc.Text, dh.Filename, pos.Line-1, 0)
+	}
+	if !ok {
+		return edits, nil
+	}
+
+	// Formatting on type happens often, so we always use
+	// the native formatter rather than executing tofu.
+	formattedTop, err := format.Format(format.Lines(doc.Text, topLines), dh.Filename)
+	if err != nil {
+		// The document is likely still being edited, which is expected
+		svc.logger.Printf("skipping on-type formatting: %s", err)
+		return edits, nil
+	}
+
+	after := format.ReplaceLines(doc.Text, topLines, formattedTop)
+	if bytes.Count(formattedTop, []byte("\n")) == bytes.Count(format.Lines(doc.Text, topLines), []byte("\n")) {
+		// Lines of the formatted top-level block map to the original ones,
+		// so we can limit the edits to the block which was just closed.
+		formattedBlock := format.Lines(formattedTop, format.LineRange{
+			Start: blockLines.Start - topLines.Start + 1,
+			End:   blockLines.End - topLines.Start + 1,
+		})
+		after = format.ReplaceLines(doc.Text, blockLines, formattedBlock)
+	}
+
+	changes := hcl.Diff(dh, doc.Text, after)
 
 	return ilsp.TextEditsFromDocumentChanges(changes), nil
 }
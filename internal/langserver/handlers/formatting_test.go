@@ -455,3 +455,268 @@ func TestLangServer_formatting_diffBug(t *testing.T) {
 			]
 		}`)
 }
+
+func TestLangServer_formatting_native(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	// No Format call is expected, as the tofu binary
+	// is not needed for native formatting
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		StateStore:      ss,
+		WalkerCollector: wc,
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345,
+	    "initializationOptions": {
+	    	"formatting": {
+	    		"mode": "native"
+	    	}
+	    }
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "provider  \"test\"   {\n\n  }\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/formatting",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"range": {
+						"start": { "line": 0, "character": 0 },
+						"end": { "line": 1, "character": 0 }
+					},
+					"newText": "provider \"test\" {\n"
+				},
+				{
+					"range": {
+						"start": { "line": 2, "character": 0 },
+						"end": { "line": 3, "character": 0 }
+					},
+					"newText": "}\n"
+				}
+			]
+		}`)
+}
+
+func TestLangServer_rangeFormatting_native(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		StateStore:      ss,
+		WalkerCollector: wc,
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345,
+	    "initializationOptions": {
+	    	"formatting": {
+	    		"mode": "native"
+	    	}
+	    }
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "locals {\n  a = 1\n  bbb = 2\n}\n\nlocals {\n  c = 1\n  ddd = 2\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// Only the second block is expected to be formatted
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rangeFormatting",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"range": {
+				"start": { "line": 6, "character": 2 },
+				"end": { "line": 6, "character": 5 }
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"range": {
+						"start": { "line": 6, "character": 0 },
+						"end": { "line": 7, "character": 0 }
+					},
+					"newText": "  c   = 1\n"
+				}
+			]
+		}`)
+}
+
+func TestLangServer_onTypeFormatting(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		StateStore:      ss,
+		WalkerCollector: wc,
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "resource \"aws_instance\" \"web\" {\n  ami  = \"ami-123\"\n  lifecycle {\n    a = 1\n    bbb = 2\n  }\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// Only the lifecycle block, which was just closed, is expected to be formatted
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/onTypeFormatting",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": { "line": 5, "character": 3 },
+			"ch": "}",
+			"options": {
+				"tabSize": 2,
+				"insertSpaces": true
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"range": {
+						"start": { "line": 3, "character": 0 },
+						"end": { "line": 4, "character": 0 }
+					},
+					"newText": "    a   = 1\n"
+				}
+			]
+		}`)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/onTypeFormatting",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": { "line": 7, "character": 0 },
+			"ch": "\n",
+			"options": {
+				"tabSize": 2,
+				"insertSpaces": true
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": [
+				{
+					"range": {
+						"start": { "line": 1, "character": 0 },
+						"end": { "line": 2, "character": 0 }
+					},
+					"newText": "  ami = \"ami-123\"\n"
+				},
+				{
+					"range": {
+						"start": { "line": 3, "character": 0 },
+						"end": { "line": 4, "character": 0 }
+					},
+					"newText": "    a   = 1\n"
+				}
+			]
+		}`)
+}
@@ -9,7 +9,6 @@ import (
 	"context"
 
 	"github.com/hashicorp/hcl-lang/decoder"
-	"github.com/hashicorp/hcl-lang/lang"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 )
@@ -60,10 +59,46 @@ func (svc *service) goToReferenceTarget(ctx context.Context, params lsp.TextDocu
 	}
 	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
 
-	path := lang.Path{
-		Path:       doc.Dir.Path(),
-		LanguageID: string(ilsp.ParseLanguageID(doc.LanguageID)),
+	if ilsp.IsValidTemplateLanguage(doc.LanguageID) {
+		// Variables of templates are defined by
+		// the templatefile calls rendering them
+		return svc.features.Templates.VariableDefinitions(ctx, doc.Dir.Path(), doc.Filename, pos), nil
 	}
 
-	return svc.decoder.ReferenceTargetsForOriginAtPos(path, doc.Filename, pos)
+	path := documentPath(doc)
+
+	targets, err := svc.decoder.ReferenceTargetsForOriginAtPos(path, doc.Filename, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.withOverridingTargets(targets), nil
+}
+
+// withOverridingTargets adds blocks of override files which are merged
+// into any of the given targets, so that the client can navigate
+// to all blocks contributing to the effective configuration
+func (svc *service) withOverridingTargets(targets decoder.ReferenceTargets) decoder.ReferenceTargets {
+	allTargets := make(decoder.ReferenceTargets, 0, len(targets))
+	for _, target := range targets {
+		allTargets = append(allTargets, target)
+
+		if ilsp.ParseLanguageID(target.Path.LanguageID) != ilsp.OpenTofu {
+			continue
+		}
+		overrides, ok := svc.features.Modules.Overrides(target.Path.Path)
+		if !ok {
+			continue
+		}
+		for _, bo := range overrides.OverridesOfBlock(target.Range) {
+			allTargets = append(allTargets, &decoder.ReferenceTarget{
+				OriginRange: target.OriginRange,
+				Path:        target.Path,
+				Range:       bo.Range,
+				DefRangePtr: bo.DefRange.Ptr(),
+			})
+		}
+	}
+
+	return allTargets
 }
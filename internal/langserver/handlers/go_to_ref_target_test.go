@@ -779,3 +779,618 @@ output "foo" {
 			]
 		}`, tmpDir.URI))
 }
+
+func TestDefinition_testFile(t *testing.T) {
+	tmpDir := TempDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "main.tf"), []byte(`variable "region" {
+}
+
+output "id" {
+  value = var.region
+}
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testsDir := filepath.Join(tmpDir.Path(), "tests")
+	err = os.Mkdir(testsDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testsURI := document.DirHandleFromPath(testsDir).URI
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu-test",
+			"text": `+fmt.Sprintf("%q",
+			`run "setup" {
+  variables {
+    region = "eu-west-1"
+  }
+}
+
+run "check" {
+  assert {
+    condition     = run.setup.id == var.region
+    error_message = "unexpected id"
+  }
+}
+`)+`,
+			"uri": "%s/main.tftest.hcl"
+		}
+	}`, testsURI)})
+	waitForAllJobs(t, ss)
+
+	// run.setup.id
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/definition",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tftest.hcl"
+			},
+			"position": {
+				"line": 8,
+				"character": 26
+			}
+		}`, testsURI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [{
+				"uri": "%s/main.tftest.hcl",
+				"range": {
+					"start": {
+						"line": 0,
+						"character": 0
+					},
+					"end": {
+						"line": 4,
+						"character": 1
+					}
+				}
+			}]
+		}`, testsURI))
+
+	// var.region is declared in the module under test
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/definition",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tftest.hcl"
+			},
+			"position": {
+				"line": 8,
+				"character": 40
+			}
+		}`, testsURI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": [{
+				"uri": "%s/main.tf",
+				"range": {
+					"start": {
+						"line": 0,
+						"character": 0
+					},
+					"end": {
+						"line": 1,
+						"character": 1
+					}
+				}
+			}]
+		}`, tmpDir.URI))
+}
+
+func TestDefinition_overrideFile(t *testing.T) {
+	tmpDir := TempDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "override.tf"), []byte(`variable "region" {
+  default = "us-east-1"
+}
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": `+fmt.Sprintf("%q",
+			`variable "region" {
+  default = "eu-west-1"
+}
+
+output "region" {
+  value = var.region
+}
+`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// var.region lands on the primary block, followed by the overriding block
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/definition",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 5,
+				"character": 14
+			}
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"uri": "%s/main.tf",
+					"range": {
+						"start": {
+							"line": 0,
+							"character": 0
+						},
+						"end": {
+							"line": 2,
+							"character": 1
+						}
+					}
+				},
+				{
+					"uri": "%s/override.tf",
+					"range": {
+						"start": {
+							"line": 0,
+							"character": 0
+						},
+						"end": {
+							"line": 2,
+							"character": 1
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI, tmpDir.URI))
+
+	// the default value of the primary block is overridden
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/hover",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 1,
+				"character": 4
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"contents": {
+					"kind": "plaintext",
+					"value": "default optional, any type\n\nDefault value to use when variable is not explicitly set\n\nThe effective value of default comes from the override file override.tf"
+				},
+				"range": {
+					"start": {
+						"line": 1,
+						"character": 2
+					},
+					"end": {
+						"line": 1,
+						"character": 23
+					}
+				}
+			}
+		}`)
+}
+
+func TestDefinition_templateVariable(t *testing.T) {
+	tmpDir := TempDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "main.tf"), []byte(`locals {
+  user_data = templatefile("${path.module}/templates/init.tftpl", {
+    hostname = "web"
+  })
+}
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tplDir := filepath.Join(tmpDir.Path(), "templates")
+	err = os.Mkdir(tplDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tplSrc := "hostnamectl set-hostname ${hostname}\n"
+	err = os.WriteFile(filepath.Join(tplDir, "init.tftpl"), []byte(tplSrc), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu-template",
+			"text": %q,
+			"uri": "%s/templates/init.tftpl"
+		}
+	}`, tplSrc, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/definition",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/templates/init.tftpl"
+			},
+			"position": {
+				"line": 0,
+				"character": 30
+			}
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"uri": "%s/main.tf",
+					"range": {
+						"start": {
+							"line": 2,
+							"character": 4
+						},
+						"end": {
+							"line": 2,
+							"character": 12
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI))
+}
+
+func TestDefinition_mappedVarFile(t *testing.T) {
+	tmpDir := TempDir(t)
+	appDir := filepath.Join(tmpDir.Path(), "app")
+	err := os.Mkdir(appDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(appDir, "variables.tf"), []byte(`variable "region" {
+  type = string
+}
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envsDir := filepath.Join(tmpDir.Path(), "envs")
+	err = os.Mkdir(envsDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	varsSrc := "region = \"eu-west-1\"\n"
+	err = os.WriteFile(filepath.Join(envsDir, "prod.tfvars"), []byte(varsSrc), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+				appDir:        validTfMockCalls(),
+				envsDir:       validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {},
+		"rootUri": %q,
+		"processId": 12345,
+		"initializationOptions": {
+			"variables": {
+				"varFiles": [
+					{
+						"pattern": "envs/*.tfvars",
+						"module": "app"
+					}
+				]
+			}
+		}
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu-vars",
+			"text": %q,
+			"uri": "%s/envs/prod.tfvars"
+		}
+	}`, varsSrc, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/definition",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/envs/prod.tfvars"
+			},
+			"position": {
+				"line": 0,
+				"character": 2
+			}
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"uri": "%s/app/variables.tf",
+					"range": {
+						"start": {
+							"line": 0,
+							"character": 0
+						},
+						"end": {
+							"line": 2,
+							"character": 1
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI))
+}
+
+func TestDefinition_overrideLocals(t *testing.T) {
+	tmpDir := TempDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "override.tf"), []byte(`locals {
+  zone = "b"
+  name = "db"
+}
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": `+fmt.Sprintf("%q",
+			`locals {
+  name = "web"
+  zone = "a"
+}
+
+output "name" {
+  value = local.name
+}
+`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// local.name lands on the primary value, followed by the overriding
+	// value only, as locals are overridden one by one
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/definition",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 6,
+				"character": 16
+			}
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"uri": "%s/main.tf",
+					"range": {
+						"start": {
+							"line": 1,
+							"character": 2
+						},
+						"end": {
+							"line": 1,
+							"character": 14
+						}
+					}
+				},
+				{
+					"uri": "%s/override.tf",
+					"range": {
+						"start": {
+							"line": 2,
+							"character": 2
+						},
+						"end": {
+							"line": 2,
+							"character": 13
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI, tmpDir.URI))
+
+	// the primary value is overridden
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/hover",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 1,
+				"character": 4
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"contents": {
+					"kind": "plaintext",
+					"value": "name any type\n\nThe effective value of name comes from the override file override.tf"
+				},
+				"range": {
+					"start": {
+						"line": 1,
+						"character": 2
+					},
+					"end": {
+						"line": 1,
+						"character": 14
+					}
+				}
+			}
+		}`)
+}
@@ -53,12 +53,20 @@ func initializeResponse(t *testing.T, commandPrefix string) string {
 				"referencesProvider": true,
 				"documentSymbolProvider": true,
 				"codeActionProvider": {
-					"codeActionKinds": ["source.formatAll.opentofu"]
+					"codeActionKinds": ["refactor.rewrite", "source.formatAll.opentofu"]
 				},
 				"codeLensProvider": {},
 				"documentLinkProvider": {},
 				"workspaceSymbolProvider": true,
 				"documentFormattingProvider": true,
+				"documentRangeFormattingProvider": true,
+				"documentOnTypeFormattingProvider": {
+					"firstTriggerCharacter": "}",
+					"moreTriggerCharacter": ["\n"]
+				},
+				"renameProvider": true,
+				"foldingRangeProvider": true,
+				"selectionRangeProvider": true,
 				"executeCommandProvider": {
 					"commands": %s,
 					"workDoneProgress":true
@@ -69,6 +77,7 @@ func initializeResponse(t *testing.T, commandPrefix string) string {
 						"tokenModifiers": []
 					}
 				},
+				"inlayHintProvider": true,
 				"workspace": {
 					"workspaceFolders": {
 						"supported": true,
@@ -9,9 +9,11 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
 	"github.com/opentofu/tofu-ls/internal/langserver/notifier"
 	"github.com/opentofu/tofu-ls/internal/langserver/session"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	"github.com/opentofu/tofu-ls/internal/state"
 )
 
@@ -26,8 +28,9 @@ func updateDiagnostics(features *Features, dNotifier *diagnostics.Notifier) noti
 			diags := diagnostics.NewDiagnostics()
 			diags.EmptyRootDiagnostic()
 
-			diags.Extend(features.Modules.Diagnostics(path))
-			diags.Extend(features.Variables.Diagnostics(path))
+			for _, provider := range features.DiagnosticsProviders() {
+				diags.Extend(provider.Diagnostics(path))
+			}
 
 			dNotifier.PublishHCLDiags(ctx, path, diags)
 		}
@@ -35,6 +38,23 @@ func updateDiagnostics(features *Features, dNotifier *diagnostics.Notifier) noti
 	}
 }
 
+func refreshDiagnostics(clientRequester session.ClientCaller) notifier.Hook {
+	return func(ctx context.Context, changes state.Changes) error {
+		if changes.Diagnostics {
+			path, err := notifier.RecordPathFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, err = clientRequester.Callback(ctx, "workspace/diagnostic/refresh", nil)
+			if err != nil {
+				return fmt.Errorf("error refreshing diagnostics of %s: %s", path, err)
+			}
+		}
+		return nil
+	}
+}
+
 func callRefreshClientCommand(clientRequester session.ClientCaller, commandId string) notifier.Hook {
 	return func(ctx context.Context, changes state.Changes) error {
 		// TODO: avoid triggering if module calls/providers did not change
@@ -62,7 +82,7 @@ func callRefreshClientCommand(clientRequester session.ClientCaller, commandId st
 func refreshCodeLens(clientRequester session.ClientCaller) notifier.Hook {
 	return func(ctx context.Context, changes state.Changes) error {
 		// TODO: avoid triggering for new targets outside of open module
-		if changes.ReferenceOrigins || changes.ReferenceTargets {
+		if changes.ReferenceOrigins || changes.ReferenceTargets || changes.PlannedChanges {
 			_, err := clientRequester.Callback(ctx, "workspace/codeLens/refresh", nil)
 			if err != nil {
 				return err
@@ -72,6 +92,24 @@ func refreshCodeLens(clientRequester session.ClientCaller) notifier.Hook {
 	}
 }
 
+// invalidateSemanticTokens stops reusing semantic tokens of documents
+// whose tokens may change without the document itself changing
+func invalidateSemanticTokens(cache *ilsp.SemanticTokensCache) notifier.Hook {
+	return func(ctx context.Context, changes state.Changes) error {
+		if changes.TofuVersion || changes.CoreRequirements || changes.InstalledProviders ||
+			changes.ProviderRequirements || changes.ReferenceOrigins || changes.ReferenceTargets {
+			path, err := notifier.RecordPathFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			cache.Invalidate(document.DirHandleFromPath(path))
+		}
+
+		return nil
+	}
+}
+
 func refreshSemanticTokens(clientRequester session.ClientCaller) notifier.Hook {
 	return func(ctx context.Context, changes state.Changes) error {
 		isOpen, err := notifier.RecordIsOpen(ctx)
@@ -97,3 +135,23 @@ func refreshSemanticTokens(clientRequester session.ClientCaller) notifier.Hook {
 		return nil
 	}
 }
+
+func refreshInlayHints(clientRequester session.ClientCaller) notifier.Hook {
+	return func(ctx context.Context, changes state.Changes) error {
+		// Values of variables and inputs of called modules may
+		// change in any module, so we don't check whether it is open
+		if changes.Variables {
+			path, err := notifier.RecordPathFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, err = clientRequester.Callback(ctx, "workspace/inlayHint/refresh", nil)
+			if err != nil {
+				return fmt.Errorf("error refreshing %s: %s", path, err)
+			}
+		}
+
+		return nil
+	}
+}
@@ -8,6 +8,8 @@ package handlers
 import (
 	"context"
 
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 )
@@ -43,9 +45,37 @@ func (svc *service) TextDocumentHover(ctx context.Context, params lsp.TextDocume
 	svc.logger.Printf("Looking for hover data at %q -> %#v", doc.Filename, pos)
 	hoverData, err := d.HoverAtPos(ctx, doc.Filename, pos)
 	svc.logger.Printf("received hover data: %#v", hoverData)
+
+	if ilsp.ParseLanguageID(doc.LanguageID) == ilsp.OpenTofu {
+		note, rng, ok := svc.features.Modules.OverrideHoverNote(dh.Dir.Path(), doc.Filename, pos)
+		if ok {
+			hoverData = withHoverNote(hoverData, note, rng)
+			err = nil
+		}
+		note, rng, ok = svc.features.Modules.PlannedChangeHoverNote(dh.Dir.Path(), doc.Filename, pos)
+		if ok {
+			hoverData = withHoverNote(hoverData, note, rng)
+			err = nil
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return ilsp.HoverData(hoverData, cc.TextDocument), nil
 }
+
+// withHoverNote appends the note (e.g. about overridden value)
+// to the hover data or creates new hover data if there is none
+func withHoverNote(hoverData *lang.HoverData, note string, rng hcl.Range) *lang.HoverData {
+	if hoverData == nil {
+		return &lang.HoverData{
+			Content: lang.Markdown(note),
+			Range:   rng,
+		}
+	}
+
+	hoverData.Content.Value += "\n\n" + note
+
+	return hoverData
+}
@@ -11,7 +11,10 @@ import (
 	"github.com/zclconf/go-cty/cty"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
 	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/opentofu/tofu-ls/internal/langserver"
 	"github.com/opentofu/tofu-ls/internal/langserver/session"
@@ -390,3 +393,40 @@ func TestHoverDynamic_inProvider(t *testing.T) {
 			}
 		}`)
 }
+
+func TestWithHoverNote(t *testing.T) {
+	rng := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 3, Byte: 22},
+		End:      hcl.Pos{Line: 2, Column: 24, Byte: 43},
+	}
+
+	// without hover data the note is the only content
+	hoverData := withHoverNote(nil, "Overrides the value of `default` from `main.tf`", rng)
+	expectedData := &lang.HoverData{
+		Content: lang.Markdown("Overrides the value of `default` from `main.tf`"),
+		Range:   rng,
+	}
+	if diff := cmp.Diff(expectedData, hoverData); diff != "" {
+		t.Fatalf("unexpected hover data: %s", diff)
+	}
+
+	// the note is appended to existing hover data, keeping its range
+	dataRng := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 3, Byte: 22},
+		End:      hcl.Pos{Line: 2, Column: 10, Byte: 29},
+	}
+	hoverData = withHoverNote(&lang.HoverData{
+		Content: lang.Markdown("**default** _optional, any type_"),
+		Range:   dataRng,
+	}, "The effective value of `default` comes from the override file `override.tf`", rng)
+	expectedData = &lang.HoverData{
+		Content: lang.Markdown("**default** _optional, any type_\n\n" +
+			"The effective value of `default` comes from the override file `override.tf`"),
+		Range: dataRng,
+	}
+	if diff := cmp.Diff(expectedData, hoverData); diff != "" {
+		t.Fatalf("unexpected hover data: %s", diff)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/mitchellh/go-homedir"
 	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	"github.com/opentofu/tofu-ls/internal/document"
+	varsAst "github.com/opentofu/tofu-ls/internal/features/variables/ast"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 	"github.com/opentofu/tofu-ls/internal/settings"
@@ -77,16 +78,38 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 	caps := ilsp.SemanticTokensClientCapabilities{
 		SemanticTokensClientCapabilities: clientCaps.TextDocument.SemanticTokens,
 	}
-	semanticTokensOpts := lsp.SemanticTokensOptions{
+	semanticTokensOpts := ilsp.SemanticTokensOptions{
 		Legend: lsp.SemanticTokensLegend{
 			TokenTypes:     ilsp.TokenTypesLegend(stCaps.TokenTypes).AsStrings(),
 			TokenModifiers: ilsp.TokenModifiersLegend(stCaps.TokenModifiers).AsStrings(),
 		},
-		Full: caps.FullRequest(),
+		Range: caps.RangeRequest(),
+	}
+	if caps.FullDeltaRequest() {
+		semanticTokensOpts.Full = lsp.PFullESemanticTokensOptions{Delta: true}
+	} else if caps.FullRequest() {
+		semanticTokensOpts.Full = true
 	}
 
 	serverCaps.Capabilities.SemanticTokensProvider = semanticTokensOpts
 
+	if clientCaps.TextDocument.Diagnostic != nil {
+		// Clients supporting the pull model get diagnostics on demand
+		// instead of having them published on every change
+		serverCaps.Capabilities.DiagnosticProvider = lsp.DiagnosticOptions{
+			InterFileDependencies: true,
+			WorkspaceDiagnostics:  true,
+		}
+	}
+
+	if clientCaps.TextDocument.Rename.PrepareSupport {
+		serverCaps.Capabilities.RenameProvider = lsp.RenameOptions{
+			PrepareProvider: true,
+		}
+	} else {
+		serverCaps.Capabilities.RenameProvider = true
+	}
+
 	// set commandPrefix for session
 	lsctx.SetCommandPrefix(ctx, out.Options.CommandPrefix)
 	// apply prefix to executeCommand handler names
@@ -101,6 +124,10 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 	lsctx.SetExperimentalFeatures(ctx, out.Options.ExperimentalFeatures)
 	// set validation options for jobs
 	lsctx.SetValidationOptions(ctx, out.Options.Validation)
+	// set formatting options
+	lsctx.SetFormattingOptions(ctx, out.Options.Formatting)
+	// set inlay hints options
+	lsctx.SetInlayHintsOptions(ctx, out.Options.InlayHints)
 
 	if len(out.UnusedKeys) > 0 {
 		jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
@@ -148,6 +175,8 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 		if err != nil {
 			return serverCaps, err
 		}
+
+		svc.configureVarFiles(ctx, cfgOpts)
 	}
 
 	// Walkers run asynchronously so we're intentionally *not*
@@ -183,14 +212,22 @@ func initializeResult(ctx context.Context) lsp.InitializeResult {
 				CodeActionKinds: ilsp.SupportedCodeActions.AsSlice(),
 				ResolveProvider: false,
 			},
-			DeclarationProvider:        true,
-			DefinitionProvider:         true,
-			CodeLensProvider:           &lsp.CodeLensOptions{},
-			ReferencesProvider:         true,
-			HoverProvider:              true,
-			DocumentFormattingProvider: true,
-			DocumentSymbolProvider:     true,
-			WorkspaceSymbolProvider:    true,
+			DeclarationProvider:             true,
+			DefinitionProvider:              true,
+			CodeLensProvider:                &lsp.CodeLensOptions{},
+			ReferencesProvider:              true,
+			HoverProvider:                   true,
+			DocumentFormattingProvider:      true,
+			DocumentRangeFormattingProvider: true,
+			DocumentOnTypeFormattingProvider: &lsp.DocumentOnTypeFormattingOptions{
+				FirstTriggerCharacter: "}",
+				MoreTriggerCharacter:  []string{"\n"},
+			},
+			DocumentSymbolProvider:  true,
+			FoldingRangeProvider:    true,
+			SelectionRangeProvider:  true,
+			InlayHintProvider:       true,
+			WorkspaceSymbolProvider: true,
 			Workspace: lsp.Workspace6Gn{
 				WorkspaceFolders: lsp.WorkspaceFolders5Gn{
 					Supported:           true,
@@ -295,6 +332,38 @@ func (svc *service) setupWalker(ctx context.Context, params lsp.InitializeParams
 	return nil
 }
 
+// configureVarFiles resolves patterns of variable definitions files
+// and their root modules relative to the root directory
+func (svc *service) configureVarFiles(ctx context.Context, options *settings.Options) {
+	rootDir, _ := lsctx.RootDirectory(ctx)
+
+	var patterns varsAst.VarFilePatterns
+	for _, varFile := range options.Variables.VarFiles {
+		pattern, err := resolvePath(rootDir, varFile.Pattern)
+		if err != nil {
+			jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+				Type:    lsp.Warning,
+				Message: fmt.Sprintf("Ignoring variables.varFiles pattern %s: %s", varFile.Pattern, err),
+			})
+			continue
+		}
+		modPath, err := resolvePath(rootDir, varFile.Module)
+		if err != nil {
+			jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+				Type:    lsp.Warning,
+				Message: fmt.Sprintf("Ignoring variables.varFiles module %s: %s", varFile.Module, err),
+			})
+			continue
+		}
+		patterns = append(patterns, varsAst.VarFilePattern{
+			Pattern:    pattern,
+			ModulePath: modPath,
+		})
+	}
+
+	svc.features.Variables.SetVarFilePatterns(patterns)
+}
+
 func resolvePath(rootDir, rawPath string) (string, error) {
 	path, err := homedir.Expand(rawPath)
 	if err != nil {
@@ -8,6 +8,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -116,7 +117,7 @@ func BenchmarkInitializeFolder_basic(b *testing.B) {
 			if err != nil {
 				b.Fatal(err)
 			}
-			err = tf.Init(b.Context(), tfexec.FromModule(mod.sourceAddr))
+			err = tf.Init(b.Context(), io.Discard, io.Discard, tfexec.FromModule(mod.sourceAddr))
 			if err != nil {
 				b.Fatal(err)
 			}
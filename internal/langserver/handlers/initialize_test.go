@@ -8,7 +8,9 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/creachadair/jrpc2"
@@ -17,9 +19,13 @@ import (
 	"github.com/opentofu/tofu-ls/internal/eventbus"
 	"github.com/opentofu/tofu-ls/internal/filesystem"
 	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
 	"github.com/opentofu/tofu-ls/internal/state"
 	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/tofu/installer"
 	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/opentofu/tofudl"
+	"github.com/opentofu/tofudl/mockmirror"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -591,3 +597,109 @@ func TestInitialize_differentWorkspaceLayouts(t *testing.T) {
 		})
 	}
 }
+
+func TestInitialize_tofuInstall(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	executableName := "tofu"
+	if runtime.GOOS == "windows" {
+		executableName += ".exe"
+	}
+
+	// A cached version not satisfying required_version must not be used
+	cacheDir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(cacheDir, "1.8.0"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(cacheDir, "1.8.0", executableName), []byte("1.8.0"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mirror := mockmirror.NewFromBinary(t, []byte("1.0.0"))
+	tfInstaller, err := installer.NewInstaller(cacheDir,
+		tofudl.ConfigGPGKey(mirror.GPGKey()),
+		tofudl.ConfigAPIURL(mirror.APIURL()),
+		tofudl.ConfigDownloadMirrorURLTemplate(mirror.DownloadMirrorURLTemplate()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			AnyWorkDir: append(validTfMockCalls(), &mock.Call{
+				Method:        "Init",
+				Repeatability: 1,
+				Arguments: []interface{}{
+					mock.AnythingOfType(""),
+					mock.Anything,
+					mock.Anything,
+				},
+				ReturnArguments: []interface{}{
+					nil,
+				},
+			}),
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+		TofuInstaller:   tfInstaller,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {},
+		"rootUri": %q,
+		"processId": 12345,
+		"initializationOptions": {
+			"tofu": {
+				"install": true
+			}
+		}
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "terraform {\n  required_version = \"~> 1.0.0\"\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s"]
+	}`, cmd.Name("tofu.init"), tmpDir.URI)}, `{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": null
+	}`)
+
+	b, err := os.ReadFile(filepath.Join(cacheDir, "1.0.0", executableName))
+	if err != nil {
+		t.Fatalf("expected OpenTofu 1.0.0 to be installed: %s", err)
+	}
+	if string(b) != "1.0.0" {
+		t.Fatalf("unexpected binary: %q", b)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/inlay"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+func (svc *service) TextDocumentInlayHint(ctx context.Context, params lsp.InlayHintParams) ([]lsp.InlayHint, error) {
+	hints := make([]lsp.InlayHint, 0)
+
+	opts, err := lsctx.InlayHintsOptions(ctx)
+	if err != nil {
+		return hints, err
+	}
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return hints, err
+	}
+
+	if ilsp.ParseLanguageID(doc.LanguageID) != ilsp.OpenTofu {
+		return hints, nil
+	}
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return hints, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	file, ok := svc.features.Modules.ParsedFile(dh.Dir.Path(), dh.Filename)
+	if !ok {
+		return hints, nil
+	}
+
+	start, err := ilsp.HCLPositionFromLspPosition(params.Range.Start, doc)
+	if err != nil {
+		return hints, err
+	}
+	end, err := ilsp.HCLPositionFromLspPosition(params.Range.End, doc)
+	if err != nil {
+		return hints, err
+	}
+	rng := hcl.Range{
+		Filename: doc.Filename,
+		Start:    start,
+		End:      end,
+	}
+
+	modPath := dh.Dir.Path()
+	allHints := make([]inlay.Hint, 0)
+
+	// Inputs of child modules come from module blocks of their callers,
+	// so defaults, environment variables and tfvars files do not apply
+	if opts.VariableValues && svc.isRootModule(ctx, modPath) {
+		variables, err := svc.features.Modules.ModuleInputs(modPath)
+		if err == nil {
+			varsFiles := svc.features.Variables.AutoloadedVarsFiles(modPath)
+			values := inlay.VariableValues(variables, varsFiles, os.Environ())
+			allHints = append(allHints, inlay.VariableValueHints(file, rng, variables, values)...)
+		}
+	}
+
+	if opts.ModuleInputTypes {
+		moduleInputs := func(callName string) (map[string]tfmod.Variable, bool) {
+			return svc.features.Modules.ModuleCallInputs(modPath, callName)
+		}
+		allHints = append(allHints, inlay.ModuleInputTypeHints(file, rng, moduleInputs)...)
+	}
+
+	sort.SliceStable(allHints, func(i, j int) bool {
+		return allHints[i].Pos.Byte < allHints[j].Pos.Byte
+	})
+
+	for _, hint := range allHints {
+		hints = append(hints, inlayHintToLSP(hint))
+	}
+
+	return hints, nil
+}
+
+// isRootModule returns true if the module in modPath is initialized
+// or declares a backend, or if no other module is known to call it
+func (svc *service) isRootModule(ctx context.Context, modPath string) bool {
+	if svc.features.RootModules.Store.Exists(modPath) {
+		return true
+	}
+	backend, err := svc.features.Modules.ModuleBackend(modPath)
+	if err == nil && backend != nil {
+		return true
+	}
+
+	// Callers are only known from module manifests, which are not
+	// parsed for root modules without any open files
+	manifestIds, err := svc.features.RootModules.IndexModuleManifests(ctx)
+	if err == nil {
+		svc.stateStore.JobStore.WaitForJobs(ctx, manifestIds...)
+	}
+	callers, err := svc.features.RootModules.CallersOfModule(modPath)
+	if err == nil && len(callers) > 0 {
+		return false
+	}
+	localCallers, err := svc.features.Modules.LocalModuleCallers(modPath)
+	if err == nil && len(localCallers) > 0 {
+		return false
+	}
+
+	return true
+}
+
+func inlayHintToLSP(hint inlay.Hint) lsp.InlayHint {
+	pos := ilsp.HCLPosToLSP(hint.Pos)
+
+	lspHint := lsp.InlayHint{
+		Position: &pos,
+		Label: []lsp.InlayHintLabelPart{
+			{Value: hint.Label},
+		},
+	}
+
+	switch hint.Kind {
+	case inlay.HintKindValue:
+		lspHint.PaddingLeft = true
+	case inlay.HintKindType:
+		// Types are attached to the names of attributes, as in name: string
+		lspHint.Kind = lsp.Type
+	}
+
+	if hint.Tooltip != "" {
+		lspHint.Tooltip = &lsp.OrPTooltip_textDocument_inlayHint{
+			Value: hint.Tooltip,
+		}
+	}
+
+	return lspHint
+}
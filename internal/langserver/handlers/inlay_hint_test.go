@@ -0,0 +1,337 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInlayHint_basic(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	childDir := filepath.Join(tmpDir.Path(), "child")
+	err := os.Mkdir(childDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(childDir, "variables.tf"), []byte(`variable "instances" {
+  type = number
+}
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(tmpDir.Path(), "variables.tf"), []byte(`variable "env" {
+  default = "dev"
+}
+variable "region" {}
+variable "instances" {
+  type = number
+}
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(tmpDir.Path(), "terraform.tfvars"), []byte(`env = "prod"
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TF_VAR_region", "eu-west-1")
+
+	mainCfg := `module "child" {
+  source    = "./child"
+  instances = var.instances
+}
+
+output "env" {
+  value = "${var.env}-${var.region}"
+}
+`
+	err = os.WriteFile(filepath.Join(tmpDir.Path(), "main.tf"), []byte(mainCfg), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, mainCfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/inlayHint",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"range": {
+				"start": {"line": 0, "character": 0},
+				"end": {"line": 8, "character": 0}
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"position": {"line": 2, "character": 11},
+					"label": [{"value": ": number"}],
+					"kind": 1
+				},
+				{
+					"position": {"line": 6, "character": 20},
+					"label": [{"value": "= \"prod\""}],
+					"tooltip": "Value from terraform.tfvars",
+					"paddingLeft": true
+				},
+				{
+					"position": {"line": 6, "character": 34},
+					"label": [{"value": "= \"eu-west-1\""}],
+					"tooltip": "Value from TF_VAR_region",
+					"paddingLeft": true
+				}
+			]
+		}`)
+}
+
+func TestInlayHint_disabled(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	mainCfg := `variable "env" {
+  default = "dev"
+}
+
+output "env" {
+  value = var.env
+}
+`
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "main.tf"), []byte(mainCfg), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345,
+	    "initializationOptions": {
+	        "inlayHints": {
+	            "variableValues": false
+	        }
+	    }
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, mainCfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/inlayHint",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"range": {
+				"start": {"line": 0, "character": 0},
+				"end": {"line": 7, "character": 0}
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": []
+		}`)
+}
+
+func TestInlayHint_childModule(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	childDir := filepath.Join(tmpDir.Path(), "child")
+	err := os.Mkdir(childDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(childDir, "variables.tf"), []byte(`variable "region" {
+  default = "us-east-1"
+}
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(childDir, "child.auto.tfvars"), []byte(`region = "ap-south-1"
+`), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TF_VAR_region", "eu-west-1")
+
+	mainCfg := `module "child" {
+  source = "./child"
+  region = "eu-central-1"
+}
+`
+	err = os.WriteFile(filepath.Join(tmpDir.Path(), "main.tf"), []byte(mainCfg), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	childCfg := `output "region" {
+  value = var.region
+}
+`
+	err = os.WriteFile(filepath.Join(childDir, "main.tf"), []byte(childCfg), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+				childDir:      validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, mainCfg, tmpDir.URI)})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/child/main.tf"
+		}
+	}`, childCfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// The input of the child module comes from the module block,
+	// so neither its default, nor the environment variable,
+	// nor its autoloaded tfvars file determine its value
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/inlayHint",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/child/main.tf"
+			},
+			"range": {
+				"start": {"line": 0, "character": 0},
+				"end": {"line": 3, "character": 0}
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": []
+		}`)
+}
@@ -8,7 +8,6 @@ package handlers
 import (
 	"context"
 
-	"github.com/hashicorp/hcl-lang/lang"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 )
@@ -33,12 +32,14 @@ func (svc *service) References(ctx context.Context, params lsp.ReferenceParams)
 		return list, err
 	}
 
-	path := lang.Path{
-		Path:       doc.Dir.Path(),
-		LanguageID: ilsp.ParseLanguageID(doc.LanguageID).String(),
-	}
+	path := documentPath(doc)
 	// TODO? maybe kick off indexing of the whole workspace here
 	origins := svc.decoder.ReferenceOriginsTargetingPos(path, doc.Filename, pos)
+	if ilsp.IsValidLockLanguage(path.LanguageID) {
+		// Locked providers are not referenced, but declared in
+		// required_providers blocks of the modules requiring them
+		origins = append(origins, svc.features.LockFile.ProviderDeclarations(ctx, doc.Dir.Path(), doc.Filename, pos)...)
+	}
 
 	return ilsp.RefOriginsToLocations(origins), nil
 }
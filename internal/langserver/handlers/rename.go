@@ -0,0 +1,133 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/job"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/rename"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+func (svc *service) PrepareRename(ctx context.Context, params lsp.PrepareRenameParams) (*lsp.PrepareRenameResult, error) {
+	decl, rng, err := svc.renameTarget(ctx, params.TextDocument.URI, params.Position)
+	if err != nil {
+		return nil, err
+	}
+	if decl == nil {
+		return nil, nil
+	}
+
+	return &lsp.PrepareRenameResult{
+		Range:       ilsp.HCLRangeToLSP(rng),
+		Placeholder: decl.Name,
+	}, nil
+}
+
+func (svc *service) Rename(ctx context.Context, params lsp.RenameParams) (*lsp.WorkspaceEdit, error) {
+	decl, _, err := svc.renameTarget(ctx, params.TextDocument.URI, params.Position)
+	if err != nil {
+		return nil, err
+	}
+	if decl == nil {
+		return nil, nil
+	}
+
+	// References to the declaration may live in modules which
+	// call the module being renamed in, which may not be indexed yet
+	if decl.Path.LanguageID == ilsp.OpenTofu.String() {
+		err = svc.indexModuleCallers(ctx, decl.Path.Path)
+		if err != nil {
+			svc.logger.Printf("failed to index callers of %q: %s", decl.Path.Path, err)
+		}
+	}
+
+	edits, err := rename.RenameEdits(ctx, svc.pathReader, decl, params.NewName)
+	if err != nil {
+		var nameErr *rename.InvalidNameError
+		if errors.As(err, &nameErr) {
+			return nil, fmt.Errorf("%w: %s", jrpc2.InvalidParams.Err(), err)
+		}
+		return nil, err
+	}
+
+	changes := make(map[lsp.DocumentURI][]lsp.TextEdit, len(edits))
+	for filePath, fileEdits := range edits {
+		changes[lsp.DocumentURI(uri.FromPath(filePath))] = ilsp.TextEdits(fileEdits, false)
+	}
+
+	return &lsp.WorkspaceEdit{
+		Changes: changes,
+	}, nil
+}
+
+func (svc *service) renameTarget(ctx context.Context, docUri lsp.DocumentURI, lspPos lsp.Position) (*rename.Declaration, hcl.Range, error) {
+	dh := ilsp.HandleFromDocumentURI(docUri)
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return nil, hcl.Range{}, err
+	}
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return nil, hcl.Range{}, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	pos, err := ilsp.HCLPositionFromLspPosition(lspPos, doc)
+	if err != nil {
+		return nil, hcl.Range{}, err
+	}
+
+	path := documentPath(doc)
+
+	decl, rng, err := rename.Target(svc.pathReader, path, doc.Filename, pos)
+	if err != nil {
+		var builtinErr *rename.BuiltinReferenceError
+		if errors.As(err, &builtinErr) {
+			return nil, hcl.Range{}, fmt.Errorf("%w: %s", jrpc2.InvalidRequest.Err(), err)
+		}
+		return nil, hcl.Range{}, err
+	}
+
+	return decl, rng, nil
+}
+
+func (svc *service) indexModuleCallers(ctx context.Context, modPath string) error {
+	// Callers are only known from module manifests, which are not
+	// parsed for root modules without any open files
+	manifestIds, err := svc.features.RootModules.IndexModuleManifests(ctx)
+	if err != nil {
+		return err
+	}
+	err = svc.stateStore.JobStore.WaitForJobs(ctx, manifestIds...)
+	if err != nil {
+		return err
+	}
+
+	callers, err := svc.features.RootModules.CallersOfModule(modPath)
+	if err != nil {
+		return err
+	}
+
+	ids := make(job.IDs, 0)
+	for _, callerPath := range callers {
+		callerIds, err := svc.features.Modules.IndexModule(ctx, callerPath)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, callerIds...)
+	}
+
+	return svc.stateStore.JobStore.WaitForJobs(ctx, ids...)
+}
@@ -0,0 +1,1152 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRename_variable(t *testing.T) {
+	tmpDir := TempDir(t)
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"textDocument": {
+	    		"rename": {
+	    			"prepareSupport": true
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": `+fmt.Sprintf("%q",
+			`variable "test" {
+}
+
+output "foo" {
+  value = "${var.test}-${var.test}"
+}`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu-vars",
+			"text": "test = \"bar\"\n",
+			"uri": "%s/terraform.tfvars"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/prepareRename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 4,
+				"character": 17
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"range": {
+					"start": {
+						"line": 4,
+						"character": 17
+					},
+					"end": {
+						"line": 4,
+						"character": 21
+					}
+				},
+				"placeholder": "test"
+			}
+		}`)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 0,
+				"character": 11
+			},
+			"newName": "renamed"
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 5,
+			"result": {
+				"changes": {
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 0,
+									"character": 10
+								},
+								"end": {
+									"line": 0,
+									"character": 14
+								}
+							},
+							"newText": "renamed"
+						},
+						{
+							"range": {
+								"start": {
+									"line": 4,
+									"character": 17
+								},
+								"end": {
+									"line": 4,
+									"character": 21
+								}
+							},
+							"newText": "renamed"
+						},
+						{
+							"range": {
+								"start": {
+									"line": 4,
+									"character": 29
+								},
+								"end": {
+									"line": 4,
+									"character": 33
+								}
+							},
+							"newText": "renamed"
+						}
+					],
+					"%s/terraform.tfvars": [
+						{
+							"range": {
+								"start": {
+									"line": 0,
+									"character": 0
+								},
+								"end": {
+									"line": 0,
+									"character": 4
+								}
+							},
+							"newText": "renamed"
+						}
+					]
+				}
+			}
+		}`, tmpDir.URI, tmpDir.URI))
+}
+
+func TestRename_invalidName(t *testing.T) {
+	tmpDir := TempDir(t)
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"textDocument": {
+	    		"rename": {
+	    			"prepareSupport": true
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "locals {\n  foo = 42\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectError(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 1,
+				"character": 3
+			},
+			"newName": "1foo"
+		}`, tmpDir.URI)}, jrpc2.InvalidParams.Err())
+}
+
+func TestPrepareRename_builtinReference(t *testing.T) {
+	tmpDir := TempDir(t)
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"textDocument": {
+	    		"rename": {
+	    			"prepareSupport": true
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": `+fmt.Sprintf("%q",
+			`output "foo" {
+  value = path.module
+}`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectError(t, &langserver.CallRequest{
+		Method: "textDocument/prepareRename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 1,
+				"character": 15
+			}
+		}`, tmpDir.URI)}, jrpc2.InvalidRequest.Err())
+}
+
+func TestPrepareRename_noTarget(t *testing.T) {
+	tmpDir := TempDir(t)
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"textDocument": {
+	    		"rename": {
+	    			"prepareSupport": true
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "provider \"aws\" {\n  region = \"eu-west-1\"\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/prepareRename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 1,
+				"character": 4
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": null
+		}`)
+}
+
+func TestRename_resourceOffersMovedBlock(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": `+fmt.Sprintf("%q",
+			`resource "random_pet" "a" {
+  count = 2
+}
+
+moved {
+  from = random_pet.old[0]
+  to   = random_pet.a[0]
+}
+
+output "name" {
+  value = random_pet.a[0].id
+}
+`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// Nothing has been renamed yet
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 24 },
+				"end": { "line": 0, "character": 24 }
+			},
+			"context": { "diagnostics": [] }
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": null
+		}`)
+
+	// The rename itself leaves moved blocks alone
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 0,
+				"character": 24
+			},
+			"newName": "b"
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"changes": {
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 0,
+									"character": 23
+								},
+								"end": {
+									"line": 0,
+									"character": 24
+								}
+							},
+							"newText": "b"
+						},
+						{
+							"range": {
+								"start": {
+									"line": 10,
+									"character": 21
+								},
+								"end": {
+									"line": 10,
+									"character": 22
+								}
+							},
+							"newText": "b"
+						}
+					]
+				}
+			}
+		}`, tmpDir.URI))
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didChange",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 1,
+			"uri": "%s/main.tf"
+		},
+		"contentChanges": [
+			{
+				"text": "b",
+				"range": {
+					"start": { "line": 10, "character": 21 },
+					"end": { "line": 10, "character": 22 }
+				}
+			},
+			{
+				"text": "b",
+				"range": {
+					"start": { "line": 0, "character": 23 },
+					"end": { "line": 0, "character": 24 }
+				}
+			}
+		]
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 24 },
+				"end": { "line": 0, "character": 24 }
+			},
+			"context": { "diagnostics": [], "only": ["refactor.rewrite"] }
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 6,
+			"result": [
+				{
+					"title": "Add moved block from random_pet.a",
+					"kind": "refactor.rewrite",
+					"edit": {
+						"changes": {
+							"%s/main.tf": [
+								{
+									"range": {
+										"start": {
+											"line": 12,
+											"character": 0
+										},
+										"end": {
+											"line": 12,
+											"character": 0
+										}
+									},
+									"newText": "\nmoved {\n  from = random_pet.a\n  to   = random_pet.b\n}\n"
+								}
+							]
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI))
+}
+
+func TestRename_labelEditOffersMovedBlock(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "resource \"random_pet\" \"a\" {\n  count = 2\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// A rename which the client never applies is not a move
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"position": { "line": 0, "character": 24 },
+			"newName": "b"
+		}`, tmpDir.URI)})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 24 },
+				"end": { "line": 0, "character": 24 }
+			},
+			"context": { "diagnostics": [] }
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": null
+		}`)
+
+	// Editing the label by hand is
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didChange",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 1,
+			"uri": "%s/main.tf"
+		},
+		"contentChanges": [
+			{
+				"text": "c",
+				"range": {
+					"start": { "line": 0, "character": 23 },
+					"end": { "line": 0, "character": 24 }
+				}
+			}
+		]
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 24 },
+				"end": { "line": 0, "character": 24 }
+			},
+			"context": { "diagnostics": [] }
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 6,
+			"result": [
+				{
+					"title": "Add moved block from random_pet.a",
+					"kind": "refactor.rewrite",
+					"edit": {
+						"changes": {
+							"%s/main.tf": [
+								{
+									"range": {
+										"start": {
+											"line": 3,
+											"character": 0
+										},
+										"end": {
+											"line": 3,
+											"character": 0
+										}
+									},
+									"newText": "\nmoved {\n  from = random_pet.a\n  to   = random_pet.c\n}\n"
+								}
+							]
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI))
+
+	// Once the moved block is added, the move is dropped
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didChange",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 2,
+			"uri": "%s/main.tf"
+		},
+		"contentChanges": [
+			{
+				"text": "\nmoved {\n  from = random_pet.a\n  to   = random_pet.c\n}\n",
+				"range": {
+					"start": { "line": 3, "character": 0 },
+					"end": { "line": 3, "character": 0 }
+				}
+			}
+		]
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 24 },
+				"end": { "line": 0, "character": 24 }
+			},
+			"context": { "diagnostics": [] }
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 8,
+			"result": null
+		}`)
+}
+
+func TestRename_acrossModuleCalls(t *testing.T) {
+	tmpDir := TempDir(t)
+	childDir := filepath.Join(tmpDir.Path(), "child")
+
+	rootCfg := `module "child" {
+  source = "./child"
+  region = "eu"
+}
+
+output "child_foo" {
+  value = module.child.foo
+}
+`
+	childCfg := `variable "region" {
+}
+
+output "foo" {
+  value = var.region
+}
+`
+	files := map[string]string{
+		filepath.Join(tmpDir.Path(), "main.tf"): rootCfg,
+		filepath.Join(childDir, "main.tf"):      childCfg,
+		filepath.Join(tmpDir.Path(), ".terraform", "modules", "modules.json"): `{"Modules":[` +
+			`{"Key":"","Source":"","Dir":"."},` +
+			`{"Key":"child","Source":"./child","Dir":"child"}]}`,
+	}
+	for path, content := range files {
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+				childDir:      validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	// Only the child module is open, so the calling
+	// module has to be indexed when renaming
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/child/main.tf"
+		}
+	}`, childCfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// Outputs are referenced as module.child.foo by callers
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/child/main.tf"
+			},
+			"position": {
+				"line": 3,
+				"character": 9
+			},
+			"newName": "bar"
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"changes": {
+					"%s/child/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 3,
+									"character": 8
+								},
+								"end": {
+									"line": 3,
+									"character": 11
+								}
+							},
+							"newText": "bar"
+						}
+					],
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 6,
+									"character": 23
+								},
+								"end": {
+									"line": 6,
+									"character": 26
+								}
+							},
+							"newText": "bar"
+						}
+					]
+				}
+			}
+		}`, tmpDir.URI, tmpDir.URI))
+
+	// Variables are set as module inputs by callers
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/child/main.tf"
+			},
+			"position": {
+				"line": 4,
+				"character": 16
+			},
+			"newName": "location"
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"changes": {
+					"%s/child/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 0,
+									"character": 10
+								},
+								"end": {
+									"line": 0,
+									"character": 16
+								}
+							},
+							"newText": "location"
+						},
+						{
+							"range": {
+								"start": {
+									"line": 4,
+									"character": 14
+								},
+								"end": {
+									"line": 4,
+									"character": 20
+								}
+							},
+							"newText": "location"
+						}
+					],
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 2,
+									"character": 2
+								},
+								"end": {
+									"line": 2,
+									"character": 8
+								}
+							},
+							"newText": "location"
+						}
+					]
+				}
+			}
+		}`, tmpDir.URI, tmpDir.URI))
+}
+
+func TestRename_localDataAndModule(t *testing.T) {
+	tmpDir := TempDir(t)
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": `+fmt.Sprintf("%q",
+			`locals {
+  name = "app"
+}
+
+data "random_string" "suffix" {
+  length = 4
+}
+
+module "child" {
+  source = "./child"
+}
+
+output "out" {
+  value = "${local.name}-${data.random_string.suffix.result}-${module.child.foo}"
+}
+`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// local values are declared as attributes of a locals block
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 13,
+				"character": 20
+			},
+			"newName": "prefix"
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"changes": {
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 1,
+									"character": 2
+								},
+								"end": {
+									"line": 1,
+									"character": 6
+								}
+							},
+							"newText": "prefix"
+						},
+						{
+							"range": {
+								"start": {
+									"line": 13,
+									"character": 19
+								},
+								"end": {
+									"line": 13,
+									"character": 23
+								}
+							},
+							"newText": "prefix"
+						}
+					]
+				}
+			}
+		}`, tmpDir.URI))
+
+	// data sources are referenced with the data prefix
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 4,
+				"character": 23
+			},
+			"newName": "id"
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"changes": {
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 4,
+									"character": 22
+								},
+								"end": {
+									"line": 4,
+									"character": 28
+								}
+							},
+							"newText": "id"
+						},
+						{
+							"range": {
+								"start": {
+									"line": 13,
+									"character": 46
+								},
+								"end": {
+									"line": 13,
+									"character": 52
+								}
+							},
+							"newText": "id"
+						}
+					]
+				}
+			}
+		}`, tmpDir.URI))
+
+	// module calls are referenced via their outputs
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 8,
+				"character": 9
+			},
+			"newName": "app"
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 5,
+			"result": {
+				"changes": {
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 8,
+									"character": 8
+								},
+								"end": {
+									"line": 8,
+									"character": 13
+								}
+							},
+							"newText": "app"
+						},
+						{
+							"range": {
+								"start": {
+									"line": 13,
+									"character": 70
+								},
+								"end": {
+									"line": 13,
+									"character": 75
+								}
+							},
+							"newText": "app"
+						}
+					]
+				}
+			}
+		}`, tmpDir.URI))
+}
@@ -0,0 +1,75 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl/v2"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/selection"
+)
+
+func (svc *service) TextDocumentSelectionRange(ctx context.Context, params lsp.SelectionRangeParams) ([]lsp.SelectionRange, error) {
+	selectionRanges := make([]lsp.SelectionRange, 0, len(params.Positions))
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return selectionRanges, err
+	}
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return selectionRanges, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	file, ok := svc.parsedFile(doc)
+
+	for _, lspPos := range params.Positions {
+		// The response has to contain a range for each position,
+		// so we fall back to an empty range at the position
+		sr := lsp.SelectionRange{
+			Range: lsp.Range{Start: lspPos, End: lspPos},
+		}
+
+		pos, err := ilsp.HCLPositionFromLspPosition(lspPos, doc)
+		if err != nil {
+			return selectionRanges, err
+		}
+
+		if ok {
+			sr = selectionRangeToLSP(selection.Ranges(file, pos), sr)
+		}
+
+		selectionRanges = append(selectionRanges, sr)
+	}
+
+	return selectionRanges, nil
+}
+
+// selectionRangeToLSP turns ranges ordered from the innermost
+// to the outermost one into a linked selection range
+func selectionRangeToLSP(ranges []hcl.Range, fallback lsp.SelectionRange) lsp.SelectionRange {
+	if len(ranges) == 0 {
+		return fallback
+	}
+
+	var parent *lsp.SelectionRange
+	for i := len(ranges) - 1; i > 0; i-- {
+		parent = &lsp.SelectionRange{
+			Range:  ilsp.HCLRangeToLSP(ranges[i]),
+			Parent: parent,
+		}
+	}
+
+	return lsp.SelectionRange{
+		Range:  ilsp.HCLRangeToLSP(ranges[0]),
+		Parent: parent,
+	}
+}
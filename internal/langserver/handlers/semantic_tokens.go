@@ -9,6 +9,8 @@ import (
 	"context"
 
 	"github.com/creachadair/jrpc2"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/opentofu/tofu-ls/internal/document"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 )
@@ -33,33 +35,187 @@ func (svc *service) TextDocumentSemanticTokensFull(ctx context.Context, params l
 	}
 
 	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
-	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	doc, err := svc.semanticTokensDocument(ctx, dh)
 	if err != nil {
 		return tks, err
 	}
 
-	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	result, ok := svc.semTokensCache.Current(dh, doc.Version)
+	if !ok {
+		tokens, err := svc.semanticTokens(ctx, doc)
+		if err != nil {
+			return tks, err
+		}
+
+		te := &ilsp.TokenEncoder{
+			Lines:      doc.Lines,
+			Tokens:     tokens,
+			ClientCaps: cc.TextDocument.SemanticTokens,
+		}
+		result.Data = te.Encode()
+		result.ResultID = svc.semTokensCache.Put(dh, doc.Version, tokens, result.Data)
+	}
+
+	tks.Data = result.Data
+	if caps.FullDeltaRequest() {
+		tks.ResultID = result.ResultID
+	}
+
+	return tks, nil
+}
+
+// TextDocumentSemanticTokensFullDelta returns edits to the tokens of the previous
+// result if it is still cached, or all tokens (as lsp.SemanticTokens) otherwise
+func (svc *service) TextDocumentSemanticTokensFullDelta(ctx context.Context, params lsp.SemanticTokensDeltaParams) (interface{}, error) {
+	cc, err := ilsp.ClientCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	caps := ilsp.SemanticTokensClientCapabilities{
+		SemanticTokensClientCapabilities: cc.TextDocument.SemanticTokens,
+	}
+	if !caps.FullDeltaRequest() {
+		svc.logger.Printf("semantic tokens full/delta request support not announced by client")
+		return nil, jrpc2.MethodNotFound.Err()
+	}
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.semanticTokensDocument(ctx, dh)
+	if err != nil {
+		return nil, err
+	}
+
+	// The document has not changed since the last result
+	if result, ok := svc.semTokensCache.Current(dh, doc.Version); ok {
+		if result.ResultID == params.PreviousResultID {
+			return lsp.SemanticTokensDelta{
+				ResultID: result.ResultID,
+				Edits:    []lsp.SemanticTokensEdit{},
+			}, nil
+		}
+		return lsp.SemanticTokens{
+			ResultID: result.ResultID,
+			Data:     result.Data,
+		}, nil
+	}
+
+	tokens, err := svc.semanticTokens(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	te := &ilsp.TokenEncoder{
+		Lines:      doc.Lines,
+		Tokens:     tokens,
+		ClientCaps: cc.TextDocument.SemanticTokens,
+	}
+	data := te.Encode()
+
+	previousData, ok := svc.semTokensCache.Get(dh, params.PreviousResultID)
+	resultId := svc.semTokensCache.Put(dh, doc.Version, tokens, data)
+
+	if !ok {
+		return lsp.SemanticTokens{
+			ResultID: resultId,
+			Data:     data,
+		}, nil
+	}
+
+	return lsp.SemanticTokensDelta{
+		ResultID: resultId,
+		Edits:    ilsp.SemanticTokensEdits(previousData, data),
+	}, nil
+}
+
+func (svc *service) TextDocumentSemanticTokensRange(ctx context.Context, params lsp.SemanticTokensRangeParams) (lsp.SemanticTokens, error) {
+	tks := lsp.SemanticTokens{}
+
+	cc, err := ilsp.ClientCapabilities(ctx)
 	if err != nil {
 		return tks, err
 	}
-	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
 
-	d, err := svc.decoderForDocument(ctx, doc)
+	caps := ilsp.SemanticTokensClientCapabilities{
+		SemanticTokensClientCapabilities: cc.TextDocument.SemanticTokens,
+	}
+	if !caps.RangeRequest() {
+		svc.logger.Printf("semantic tokens range request support not announced by client")
+		return tks, jrpc2.MethodNotFound.Err()
+	}
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.semanticTokensDocument(ctx, dh)
 	if err != nil {
 		return tks, err
 	}
 
-	tokens, err := d.SemanticTokensInFile(ctx, doc.Filename)
+	// Tokens of the whole document are reused if it has not changed
+	// since, but only tokens within the range are encoded either way
+	var tokens []lang.SemanticToken
+	if result, ok := svc.semTokensCache.Current(dh, doc.Version); ok {
+		tokens = result.Tokens
+	} else {
+		tokens, err = svc.semanticTokens(ctx, doc)
+		if err != nil {
+			return tks, err
+		}
+	}
+
+	start, err := ilsp.HCLPositionFromLspPosition(params.Range.Start, doc)
 	if err != nil {
 		return tks, err
 	}
+	end, err := ilsp.HCLPositionFromLspPosition(params.Range.End, doc)
+	if err != nil {
+		return tks, err
+	}
+
+	// Tokens are sorted by their start, so tokens
+	// starting after the range need no checking
+	tokensInRange := make([]lang.SemanticToken, 0)
+	for _, token := range tokens {
+		if token.Range.Start.Byte >= end.Byte {
+			break
+		}
+		if token.Range.End.Byte <= start.Byte {
+			continue
+		}
+		tokensInRange = append(tokensInRange, token)
+	}
 
 	te := &ilsp.TokenEncoder{
 		Lines:      doc.Lines,
-		Tokens:     tokens,
+		Tokens:     tokensInRange,
 		ClientCaps: cc.TextDocument.SemanticTokens,
 	}
 	tks.Data = te.Encode()
 
 	return tks, nil
 }
+
+// semanticTokensDocument returns the document once
+// all jobs which may affect its tokens are done
+func (svc *service) semanticTokensDocument(ctx context.Context, dh document.Handle) (*document.Document, error) {
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return nil, err
+	}
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return nil, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	return doc, nil
+}
+
+func (svc *service) semanticTokens(ctx context.Context, doc *document.Document) ([]lang.SemanticToken, error) {
+	d, err := svc.decoderForDocument(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.SemanticTokensInFile(ctx, doc.Filename)
+}
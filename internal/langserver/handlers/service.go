@@ -16,19 +16,26 @@ import (
 
 	"github.com/creachadair/jrpc2"
 	rpch "github.com/creachadair/jrpc2/handler"
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
 	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/eventbus"
+	fbackends "github.com/opentofu/tofu-ls/internal/features/backends"
+	fcliconfig "github.com/opentofu/tofu-ls/internal/features/cliconfig"
+	flockfile "github.com/opentofu/tofu-ls/internal/features/lockfile"
 	fmodules "github.com/opentofu/tofu-ls/internal/features/modules"
 	frootmodules "github.com/opentofu/tofu-ls/internal/features/rootmodules"
+	ftemplates "github.com/opentofu/tofu-ls/internal/features/templates"
+	ftests "github.com/opentofu/tofu-ls/internal/features/tests"
 	fvariables "github.com/opentofu/tofu-ls/internal/features/variables"
 	"github.com/opentofu/tofu-ls/internal/filesystem"
 	"github.com/opentofu/tofu-ls/internal/job"
 	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
 	"github.com/opentofu/tofu-ls/internal/langserver/notifier"
+	"github.com/opentofu/tofu-ls/internal/langserver/progress"
 	"github.com/opentofu/tofu-ls/internal/langserver/session"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
@@ -38,6 +45,7 @@ import (
 	"github.com/opentofu/tofu-ls/internal/state"
 	"github.com/opentofu/tofu-ls/internal/tofu/discovery"
 	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/tofu/installer"
 	"github.com/opentofu/tofu-ls/internal/walker"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -50,6 +58,31 @@ type Features struct {
 	Modules     *fmodules.ModulesFeature
 	RootModules *frootmodules.RootModulesFeature
 	Variables   *fvariables.VariablesFeature
+	Tests       *ftests.TestsFeature
+	Backends    *fbackends.BackendsFeature
+	CLIConfig   *fcliconfig.CLIConfigFeature
+	LockFile    *flockfile.LockFileFeature
+	Templates   *ftemplates.TemplatesFeature
+}
+
+// DiagnosticsProvider is a feature which reports
+// diagnostics for the paths it indexes
+type DiagnosticsProvider interface {
+	Paths(ctx context.Context) []lang.Path
+	Diagnostics(path string) diagnostics.Diagnostics
+}
+
+// DiagnosticsProviders returns all features which report diagnostics
+func (f *Features) DiagnosticsProviders() []DiagnosticsProvider {
+	return []DiagnosticsProvider{
+		f.Modules,
+		f.Variables,
+		f.Tests,
+		f.Backends,
+		f.CLIConfig,
+		f.LockFile,
+		f.Templates,
+	}
 }
 
 type service struct {
@@ -71,7 +104,10 @@ type service struct {
 	tfDiscoFunc    discovery.DiscoveryFunc
 	tfExecFactory  exec.ExecutorFactory
 	tfExecOpts     *exec.ExecutorOpts
+	tfInstaller    *installer.Installer
 	decoder        *decoder.Decoder
+	pathReader     *idecoder.GlobalPathReader
+	semTokensCache *ilsp.SemanticTokensCache
 	stateStore     *state.StateStore
 	server         session.Server
 	diagsNotifier  *diagnostics.Notifier
@@ -84,6 +120,8 @@ type service struct {
 	walkerCollector    *walker.WalkerCollector
 	additionalHandlers rpch.Map
 
+	workDoneProgress progress.Cancellations
+
 	singleFileMode bool
 }
 
@@ -127,6 +165,8 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 	clientName := ""
 	var expFeatures settings.ExperimentalFeatures
 	var validationOptions settings.ValidationOptions
+	var formattingOptions settings.Formatting
+	var inlayHintsOptions settings.InlayHints
 
 	m := rpch.Map{
 		"initialize": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
@@ -141,6 +181,8 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			ctx = ilsp.ContextWithClientName(ctx, &clientName)
 			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
 			ctx = lsctx.WithValidationOptions(ctx, &validationOptions)
+			ctx = lsctx.WithFormattingOptions(ctx, &formattingOptions)
+			ctx = lsctx.WithInlayHintsOptions(ctx, &inlayHintsOptions)
 
 			version, ok := lsctx.LanguageServerVersion(svc.srvCtx)
 			if ok {
@@ -267,6 +309,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			ctx = ilsp.WithClientCapabilities(ctx, cc)
 			ctx = exec.WithExecutorOpts(ctx, svc.tfExecOpts)
 			ctx = exec.WithExecutorFactory(ctx, svc.tfExecFactory)
+			ctx = lsctx.WithFormattingOptions(ctx, &formattingOptions)
 
 			return handle(ctx, req, svc.TextDocumentCodeAction)
 		},
@@ -277,6 +320,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			}
 
 			ctx = ilsp.WithClientCapabilities(ctx, cc)
+			ctx = lsctx.WithCommandPrefix(ctx, &commandPrefix)
 
 			return handle(ctx, req, svc.TextDocumentCodeLens)
 		},
@@ -288,9 +332,74 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 
 			ctx = exec.WithExecutorOpts(ctx, svc.tfExecOpts)
 			ctx = exec.WithExecutorFactory(ctx, svc.tfExecFactory)
+			ctx = lsctx.WithFormattingOptions(ctx, &formattingOptions)
 
 			return handle(ctx, req, svc.TextDocumentFormatting)
 		},
+		"textDocument/rangeFormatting": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = exec.WithExecutorOpts(ctx, svc.tfExecOpts)
+			ctx = exec.WithExecutorFactory(ctx, svc.tfExecFactory)
+			ctx = lsctx.WithFormattingOptions(ctx, &formattingOptions)
+
+			return handle(ctx, req, svc.TextDocumentRangeFormatting)
+		},
+		"textDocument/onTypeFormatting": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.TextDocumentOnTypeFormatting)
+		},
+		"textDocument/foldingRange": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = ilsp.WithClientCapabilities(ctx, cc)
+
+			return handle(ctx, req, svc.TextDocumentFoldingRange)
+		},
+		"textDocument/inlayHint": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithInlayHintsOptions(ctx, &inlayHintsOptions)
+
+			return handle(ctx, req, svc.TextDocumentInlayHint)
+		},
+		"textDocument/diagnostic": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.TextDocumentDiagnostic)
+		},
+		"workspace/diagnostic": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.WorkspaceDiagnostic)
+		},
+		"textDocument/selectionRange": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.TextDocumentSelectionRange)
+		},
 		"textDocument/signatureHelp": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -311,6 +420,26 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 
 			return handle(ctx, req, svc.TextDocumentSemanticTokensFull)
 		},
+		"textDocument/semanticTokens/full/delta": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = ilsp.WithClientCapabilities(ctx, cc)
+
+			return handle(ctx, req, svc.TextDocumentSemanticTokensFullDelta)
+		},
+		"textDocument/semanticTokens/range": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = ilsp.WithClientCapabilities(ctx, cc)
+
+			return handle(ctx, req, svc.TextDocumentSemanticTokensRange)
+		},
 		"textDocument/didSave": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -349,6 +478,22 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 
 			return handle(ctx, req, svc.References)
 		},
+		"textDocument/prepareRename": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.PrepareRename)
+		},
+		"textDocument/rename": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.Rename)
+		},
 		"workspace/executeCommand": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -359,6 +504,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			ctx = lsctx.WithRootDirectory(ctx, &rootDir)
 			ctx = lsctx.WithDiagnosticsNotifier(ctx, svc.diagsNotifier)
 			ctx = ilsp.ContextWithClientName(ctx, &clientName)
+			ctx = lsctx.WithValidationOptions(ctx, &validationOptions)
 			ctx = exec.WithExecutorOpts(ctx, svc.tfExecOpts)
 			ctx = exec.WithExecutorFactory(ctx, svc.tfExecFactory)
 
@@ -392,6 +538,14 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 
 			return nil, nil
 		},
+		"window/workDoneProgress/cancel": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.WorkDoneProgressCancel)
+		},
 		"$/cancelRequest": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -408,6 +562,23 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 	return m, nil
 }
 
+// installedTofuPath returns the path to an installed OpenTofu version
+// satisfying required_version of the module in the given directory,
+// installing it first if necessary
+func (svc *service) installedTofuPath(ctx context.Context, modPath string) (string, error) {
+	var constraints version.Constraints
+	if svc.features != nil && svc.features.Modules != nil {
+		coreRequirements, err := svc.features.Modules.CoreRequirements(modPath)
+		if err != nil {
+			// Modules which are not indexed (yet) have no known requirements
+			svc.logger.Printf("unable to find required_version of %q, using latest OpenTofu version: %s", modPath, err)
+		}
+		constraints = coreRequirements
+	}
+
+	return svc.tfInstaller.Install(ctx, constraints)
+}
+
 func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *settings.Options) error {
 	// Raise warnings for deprecated options
 	if cfgOpts.XLegacyTofuExecPath != "" {
@@ -436,6 +607,19 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 	execOpts := &exec.ExecutorOpts{}
 	if len(cfgOpts.TofuOptions.Path) > 0 {
 		execOpts.ExecPath = cfgOpts.TofuOptions.Path
+	} else if cfgOpts.TofuOptions.Install {
+		if svc.tfInstaller == nil {
+			cacheDir, err := installer.DefaultCacheDir()
+			if err != nil {
+				return fmt.Errorf("failed to find cache directory for tofu.install: %w", err)
+			}
+			svc.tfInstaller, err = installer.NewInstaller(cacheDir)
+			if err != nil {
+				return err
+			}
+		}
+		svc.tfInstaller.SetLogger(svc.logger)
+		execOpts.ExecPathFunc = svc.installedTofuPath
 	} else {
 		path, err := svc.tfDiscoFunc()
 		if err == nil {
@@ -528,30 +712,96 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 		variablesFeature.SetLogger(svc.logger)
 		variablesFeature.Start(svc.sessCtx)
 
+		testsFeature, err := ftests.NewTestsFeature(svc.eventBus, svc.stateStore, svc.fs,
+			modulesFeature)
+		if err != nil {
+			return err
+		}
+		testsFeature.SetLogger(svc.logger)
+		testsFeature.Start(svc.sessCtx)
+
+		backendsFeature, err := fbackends.NewBackendsFeature(svc.eventBus, svc.stateStore, svc.fs,
+			modulesFeature)
+		if err != nil {
+			return err
+		}
+		backendsFeature.SetLogger(svc.logger)
+		backendsFeature.Start(svc.sessCtx)
+
+		cliConfigFeature, err := fcliconfig.NewCLIConfigFeature(svc.eventBus, svc.stateStore, svc.fs)
+		if err != nil {
+			return err
+		}
+		cliConfigFeature.SetLogger(svc.logger)
+		cliConfigFeature.Start(svc.sessCtx)
+
+		lockFileFeature, err := flockfile.NewLockFileFeature(svc.eventBus, svc.stateStore, svc.fs,
+			modulesFeature)
+		if err != nil {
+			return err
+		}
+		lockFileFeature.SetLogger(svc.logger)
+		lockFileFeature.Start(svc.sessCtx)
+
+		templatesFeature, err := ftemplates.NewTemplatesFeature(svc.eventBus, svc.stateStore, svc.fs,
+			modulesFeature)
+		if err != nil {
+			return err
+		}
+		templatesFeature.SetLogger(svc.logger)
+		templatesFeature.Start(svc.sessCtx)
+
 		svc.features = &Features{
 			Modules:     modulesFeature,
 			RootModules: rootModulesFeature,
 			Variables:   variablesFeature,
+			Tests:       testsFeature,
+			Backends:    backendsFeature,
+			CLIConfig:   cliConfigFeature,
+			LockFile:    lockFileFeature,
+			Templates:   templatesFeature,
 		}
 	}
 
-	svc.decoder = decoder.NewDecoder(&idecoder.GlobalPathReader{
+	svc.pathReader = &idecoder.GlobalPathReader{
 		PathReaderMap: idecoder.PathReaderMap{
-			ilsp.OpenTofu.String():     svc.features.Modules,
-			ilsp.OpenTofuVars.String(): svc.features.Variables,
+			ilsp.OpenTofu.String():          svc.features.Modules,
+			ilsp.OpenTofuVars.String():      svc.features.Variables,
+			ilsp.OpenTofuTest.String():      svc.features.Tests,
+			ilsp.OpenTofuMock.String():      svc.features.Tests,
+			ilsp.OpenTofuBackend.String():   svc.features.Backends,
+			ilsp.OpenTofuCLIConfig.String(): svc.features.CLIConfig,
+			ilsp.OpenTofuLock.String():      svc.features.LockFile,
 		},
-	})
+	}
+	svc.decoder = decoder.NewDecoder(svc.pathReader)
+	svc.semTokensCache = ilsp.NewSemanticTokensCache()
 	decoderContext := idecoder.DecoderContext(ctx)
 	svc.features.Modules.AppendCompletionHooks(svc.srvCtx, decoderContext)
+	decoderContext.CodeLenses = svc.features.LockFile.AppendCodeLenses(ctx, decoderContext.CodeLenses)
+	decoderContext.CodeLenses = svc.features.Tests.AppendCodeLenses(ctx, decoderContext.CodeLenses)
+	decoderContext.CodeLenses = svc.features.Modules.AppendCodeLenses(ctx, decoderContext.CodeLenses)
 	svc.decoder.SetContext(decoderContext)
 
 	moduleHooks := []notifier.Hook{
-		updateDiagnostics(svc.features, svc.diagsNotifier),
+		// Cached tokens must be invalidated before the client is asked
+		// to refresh them, so the hook goes first
+		invalidateSemanticTokens(svc.semTokensCache),
 	}
 
 	cc, err := ilsp.ClientCapabilities(ctx)
+	if err == nil && cc.TextDocument.Diagnostic != nil {
+		// Diagnostics are pulled by the client instead
+		if cc.Workspace.Diagnostics != nil && cc.Workspace.Diagnostics.RefreshSupport {
+			moduleHooks = append(moduleHooks, refreshDiagnostics(svc.server))
+		}
+	} else {
+		moduleHooks = append(moduleHooks, updateDiagnostics(svc.features, svc.diagsNotifier))
+	}
+
 	if err == nil {
-		if _, ok := lsp.ExperimentalClientCapabilities(cc.Experimental).ShowReferencesCommandId(); ok {
+		_, showReferences := lsp.ExperimentalClientCapabilities(cc.Experimental).ShowReferencesCommandId()
+		if showReferences || (cc.Workspace.CodeLens != nil && cc.Workspace.CodeLens.RefreshSupport) {
 			moduleHooks = append(moduleHooks, refreshCodeLens(svc.server))
 		}
 
@@ -570,6 +820,10 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 		if cc.Workspace.SemanticTokens != nil && cc.Workspace.SemanticTokens.RefreshSupport {
 			moduleHooks = append(moduleHooks, refreshSemanticTokens(svc.server))
 		}
+
+		if cc.Workspace.InlayHint != nil && cc.Workspace.InlayHint.RefreshSupport {
+			moduleHooks = append(moduleHooks, refreshInlayHints(svc.server))
+		}
 	}
 
 	svc.notifier = notifier.NewNotifier(svc.stateStore.ChangeStore, moduleHooks)
@@ -617,6 +871,21 @@ func (svc *service) shutdown() {
 		if svc.features.Variables != nil {
 			svc.features.Variables.Stop()
 		}
+		if svc.features.Tests != nil {
+			svc.features.Tests.Stop()
+		}
+		if svc.features.Backends != nil {
+			svc.features.Backends.Stop()
+		}
+		if svc.features.CLIConfig != nil {
+			svc.features.CLIConfig.Stop()
+		}
+		if svc.features.LockFile != nil {
+			svc.features.LockFile.Stop()
+		}
+		if svc.features.Templates != nil {
+			svc.features.Templates.Stop()
+		}
 	}
 }
 
@@ -690,8 +959,22 @@ func handle(ctx context.Context, req *jrpc2.Request, fn interface{}) (interface{
 }
 
 func (svc *service) decoderForDocument(_ context.Context, doc *document.Document) (*decoder.PathDecoder, error) {
-	return svc.decoder.Path(lang.Path{
+	return svc.decoder.Path(documentPath(doc))
+}
+
+// documentPath returns the path of the decoding context of the document.
+// Variable files are decoded per file, as files of a single directory
+// may be passed to different root modules via -var-file.
+func documentPath(doc *document.Document) lang.Path {
+	languageID := ilsp.ParseLanguageID(doc.LanguageID)
+	if languageID == ilsp.OpenTofuVars {
+		return lang.Path{
+			Path:       doc.FullPath(),
+			LanguageID: languageID.String(),
+		}
+	}
+	return lang.Path{
 		Path:       doc.Dir.Path(),
-		LanguageID: string(ilsp.ParseLanguageID(doc.LanguageID)),
-	})
+		LanguageID: languageID.String(),
+	}
 }
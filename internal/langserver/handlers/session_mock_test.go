@@ -17,8 +17,13 @@ import (
 
 	"github.com/creachadair/jrpc2/handler"
 	"github.com/opentofu/tofu-ls/internal/eventbus"
+	fbackends "github.com/opentofu/tofu-ls/internal/features/backends"
+	fcliconfig "github.com/opentofu/tofu-ls/internal/features/cliconfig"
+	flockfile "github.com/opentofu/tofu-ls/internal/features/lockfile"
 	fmodules "github.com/opentofu/tofu-ls/internal/features/modules"
 	frootmodules "github.com/opentofu/tofu-ls/internal/features/rootmodules"
+	ftemplates "github.com/opentofu/tofu-ls/internal/features/templates"
+	ftests "github.com/opentofu/tofu-ls/internal/features/tests"
 	fvariables "github.com/opentofu/tofu-ls/internal/features/variables"
 	"github.com/opentofu/tofu-ls/internal/filesystem"
 	"github.com/opentofu/tofu-ls/internal/langserver/session"
@@ -26,6 +31,7 @@ import (
 	"github.com/opentofu/tofu-ls/internal/state"
 	"github.com/opentofu/tofu-ls/internal/tofu/discovery"
 	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/tofu/installer"
 	"github.com/opentofu/tofu-ls/internal/walker"
 )
 
@@ -38,6 +44,7 @@ type MockSessionInput struct {
 	Features           *Features
 	FileSystem         *filesystem.Filesystem
 	EventBus           *eventbus.EventBus
+	TofuInstaller      *installer.Installer
 }
 
 type mockSession struct {
@@ -59,6 +66,7 @@ func (ms *mockSession) new(srvCtx context.Context) session.Session {
 	var walkerCollector *walker.WalkerCollector
 	var fileSystem *filesystem.Filesystem
 	var eventBus *eventbus.EventBus
+	var tfInstaller *installer.Installer
 	if ms.mockInput != nil {
 		stateStore = ms.mockInput.StateStore
 		walkerCollector = ms.mockInput.WalkerCollector
@@ -67,6 +75,7 @@ func (ms *mockSession) new(srvCtx context.Context) session.Session {
 		features = ms.mockInput.Features
 		fileSystem = ms.mockInput.FileSystem
 		eventBus = ms.mockInput.EventBus
+		tfInstaller = ms.mockInput.TofuInstaller
 	}
 
 	var tfCalls *exec.TofuMockCalls
@@ -93,6 +102,7 @@ func (ms *mockSession) new(srvCtx context.Context) session.Session {
 		stopSession:        ms.stop,
 		tfDiscoFunc:        d.LookPath,
 		tfExecFactory:      exec.NewMockExecutor(tfCalls),
+		tfInstaller:        tfInstaller,
 		additionalHandlers: handlers,
 		stateStore:         stateStore,
 		walkerCollector:    walkerCollector,
@@ -163,9 +173,39 @@ func NewTestFeatures(eventBus *eventbus.EventBus, s *state.StateStore, fs *files
 		return nil, err
 	}
 
+	testsFeature, err := ftests.NewTestsFeature(eventBus, s, fs, modulesFeature)
+	if err != nil {
+		return nil, err
+	}
+
+	backendsFeature, err := fbackends.NewBackendsFeature(eventBus, s, fs, modulesFeature)
+	if err != nil {
+		return nil, err
+	}
+
+	cliConfigFeature, err := fcliconfig.NewCLIConfigFeature(eventBus, s, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	lockFileFeature, err := flockfile.NewLockFileFeature(eventBus, s, fs, modulesFeature)
+	if err != nil {
+		return nil, err
+	}
+
+	templatesFeature, err := ftemplates.NewTemplatesFeature(eventBus, s, fs, modulesFeature)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Features{
 		Modules:     modulesFeature,
 		RootModules: rootModulesFeature,
 		Variables:   variablesFeature,
+		Tests:       testsFeature,
+		Backends:    backendsFeature,
+		CLIConfig:   cliConfigFeature,
+		LockFile:    lockFileFeature,
+		Templates:   templatesFeature,
 	}, nil
 }
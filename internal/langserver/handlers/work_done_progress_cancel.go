@@ -0,0 +1,19 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+func (svc *service) WorkDoneProgressCancel(ctx context.Context, params lsp.WorkDoneProgressCancelParams) error {
+	if !svc.workDoneProgress.Cancel(params.Token) {
+		svc.logger.Printf("no cancellable work found for progress token %v", params.Token)
+	}
+	return nil
+}
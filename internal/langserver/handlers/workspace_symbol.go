@@ -8,6 +8,7 @@ package handlers
 import (
 	"context"
 
+	"github.com/hashicorp/hcl-lang/decoder"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 )
@@ -24,5 +25,16 @@ func (svc *service) WorkspaceSymbol(ctx context.Context, params lsp.WorkspaceSym
 		return nil, err
 	}
 
+	// Files shadowed by .tofu files are ignored by OpenTofu,
+	// so their symbols would only duplicate the real ones
+	unshadowed := make([]decoder.Symbol, 0, len(symbols))
+	for _, symbol := range symbols {
+		if svc.features.Modules.IsShadowedFile(symbol.Path().Path, symbol.Range().Filename) {
+			continue
+		}
+		unshadowed = append(unshadowed, symbol)
+	}
+	symbols = unshadowed
+
 	return ilsp.WorkspaceSymbols(symbols, cc.Workspace.Symbol), nil
 }
@@ -70,6 +70,11 @@ func DefaultConcurrency() int {
 	if cpu >= 4 {
 		return cpu / 2
 	}
+	// Leave room for cancellation of long-running
+	// requests, such as commands running tofu init
+	if cpu < 2 {
+		return 2
+	}
 	return cpu
 }
 
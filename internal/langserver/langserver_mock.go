@@ -43,6 +43,8 @@ type langServerMock struct {
 	client       *jrpc2.Client
 	clientStdin  io.Reader
 	clientStdout io.WriteCloser
+
+	onNotify func(*jrpc2.Request)
 }
 
 func NewLangServerMock(t T, sf session.SessionFactory) *langServerMock {
@@ -103,7 +105,9 @@ func (lsm *langServerMock) Start(t T) context.CancelFunc {
 	}()
 
 	clientCh := channel.LSP(lsm.clientStdin, lsm.clientStdout)
-	opts := &jrpc2.ClientOptions{}
+	opts := &jrpc2.ClientOptions{
+		OnNotify: lsm.onNotify,
+	}
 	if testing.Verbose() {
 		opts.Logger = jrpc2.StdLogger(testLogger(os.Stdout, "[CLIENT] "))
 	}
@@ -112,6 +116,12 @@ func (lsm *langServerMock) Start(t T) context.CancelFunc {
 	return lsm.Stop
 }
 
+// OnNotify sets a function to be called for every notification
+// sent by the server to the client. It must be set before Start.
+func (lsm *langServerMock) OnNotify(f func(req *jrpc2.Request)) {
+	lsm.onNotify = f
+}
+
 func (lsm *langServerMock) CloseClientStdout(t T) {
 	err := lsm.clientStdout.Close()
 	if err != nil {
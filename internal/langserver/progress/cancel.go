@@ -0,0 +1,63 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package progress
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+// Cancellations keeps track of work reporting progress, which
+// the client can cancel via window/workDoneProgress/cancel.
+// The zero value is ready to use.
+type Cancellations struct {
+	mu          sync.Mutex
+	cancelFuncs map[string]context.CancelFunc
+}
+
+// WithCancel returns a copy of ctx which is cancelled once the client
+// cancels the progress with the given token. The returned function
+// must be called once the work is done.
+func (c *Cancellations) WithCancel(ctx context.Context, token lsp.ProgressToken) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	key := tokenKey(token)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancelFuncs == nil {
+		c.cancelFuncs = make(map[string]context.CancelFunc)
+	}
+	c.cancelFuncs[key] = cancel
+
+	return ctx, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.cancelFuncs, key)
+		cancel()
+	}
+}
+
+// Cancel cancels the work reporting progress with the given
+// token and reports whether any such work was in progress
+func (c *Cancellations) Cancel(token lsp.ProgressToken) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cancel, ok := c.cancelFuncs[tokenKey(token)]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// tokenKey turns the token, which is either an integer
+// or a string, into a key distinguishing both types
+func tokenKey(token lsp.ProgressToken) string {
+	return fmt.Sprintf("%#v", token)
+}
@@ -24,6 +24,8 @@ func Begin(ctx context.Context, title string) error {
 		Value: lsp.WorkDoneProgressBegin{
 			Kind:  "begin",
 			Title: title,
+			// Commands reporting progress can be cancelled (see Cancellations)
+			Cancellable: true,
 		},
 	})
 }
@@ -31,12 +31,16 @@ var (
 	// ** We don't support this as terraform fmt only adjusts style**
 	// lsp.SourceFixAll: true,
 
+	// `refactor.rewrite`: Rewrite actions, such as recording
+	// a renamed resource or module call in a moved block.
+
 	// `source.formatAll`: Generic format code action.
 	// We do not register this for terraform to allow fine grained selection of actions.
 	// A user should be able to set `source.formatAll` to true, and source.formatAll.opentofu to false to allow all
 	// files to be formatted, but not terraform files (or vice versa).
 	SupportedCodeActions = CodeActions{
 		SourceFormatAllTofu: true,
+		lsp.RefactorRewrite: true,
 	}
 )
 
@@ -6,8 +6,13 @@
 package lsp
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
 	"github.com/hashicorp/hcl/v2"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/tofu/ast"
 )
 
 func HCLSeverityToLSP(severity hcl.DiagnosticSeverity) lsp.DiagnosticSeverity {
@@ -35,9 +40,13 @@ func HCLDiagsToLSP(hclDiags hcl.Diagnostics, source string) []lsp.Diagnostic {
 		if hclDiag.Subject != nil {
 			rnge = HCLRangeToLSP(*hclDiag.Subject)
 		}
+		severity := HCLSeverityToLSP(hclDiag.Severity)
+		if ast.IsInformational(hclDiag) {
+			severity = lsp.SeverityInformation
+		}
 		diags = append(diags, lsp.Diagnostic{
 			Range:    rnge,
-			Severity: HCLSeverityToLSP(hclDiag.Severity),
+			Severity: severity,
 			Source:   source,
 			Message:  msg,
 		})
@@ -45,3 +54,41 @@ func HCLDiagsToLSP(hclDiags hcl.Diagnostics, source string) []lsp.Diagnostic {
 	}
 	return diags
 }
+
+// DiagnosticsResultID returns an ID identifying the given diagnostics
+// by their content, so that a client which already has the same
+// diagnostics can be told they remained unchanged
+func DiagnosticsResultID(diags []lsp.Diagnostic) string {
+	b, err := json.Marshal(diags)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16])
+}
+
+// WorkspaceDiagnosticReport is the result of a workspace/diagnostic request.
+//
+// Unlike its generated counterpart, its items are serialized as either
+// WorkspaceFullDocumentDiagnosticReport or WorkspaceUnchangedDocumentDiagnosticReport.
+type WorkspaceDiagnosticReport struct {
+	Items []interface{} `json:"items"`
+}
+
+// WorkspaceFullDocumentDiagnosticReport is a full diagnostic report
+// for a document, where a null version denotes a document which
+// is not open in the client
+type WorkspaceFullDocumentDiagnosticReport struct {
+	URI     lsp.DocumentURI `json:"uri"`
+	Version *int32          `json:"version"`
+	lsp.FullDocumentDiagnosticReport
+}
+
+// WorkspaceUnchangedDocumentDiagnosticReport is an unchanged diagnostic report
+// for a document, where a null version denotes a document which
+// is not open in the client
+type WorkspaceUnchangedDocumentDiagnosticReport struct {
+	URI     lsp.DocumentURI `json:"uri"`
+	Version *int32          `json:"version"`
+	lsp.UnchangedDocumentDiagnosticReport
+}
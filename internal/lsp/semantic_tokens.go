@@ -79,3 +79,58 @@ func (c SemanticTokensClientCapabilities) FullRequest() bool {
 	}
 	return false
 }
+
+func (c SemanticTokensClientCapabilities) FullDeltaRequest() bool {
+	full, ok := c.Requests.Full.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	delta, ok := full["delta"].(bool)
+	return ok && delta
+}
+
+func (c SemanticTokensClientCapabilities) RangeRequest() bool {
+	return c.Requests.Range
+}
+
+// SemanticTokensOptions represents the server capability for semantic tokens.
+//
+// Unlike lsp.SemanticTokensOptions it allows announcing
+// support for delta requests, in which case Full is
+// lsp.PFullESemanticTokensOptions rather than a bool.
+type SemanticTokensOptions struct {
+	Legend lsp.SemanticTokensLegend `json:"legend"`
+	Range  bool                     `json:"range,omitempty"`
+	Full   interface{}              `json:"full,omitempty"`
+}
+
+// SemanticTokensEdits returns edits transforming the old encoded tokens
+// into the new ones. Since most changes are local to a single place
+// in the document, the difference is expressed as a single edit
+// replacing everything between the common prefix and suffix.
+func SemanticTokensEdits(oldData, newData []uint32) []lsp.SemanticTokensEdit {
+	prefix := 0
+	for prefix < len(oldData) && prefix < len(newData) && oldData[prefix] == newData[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(oldData)-prefix && suffix < len(newData)-prefix &&
+		oldData[len(oldData)-1-suffix] == newData[len(newData)-1-suffix] {
+		suffix++
+	}
+
+	deleteCount := len(oldData) - prefix - suffix
+	insertData := newData[prefix : len(newData)-suffix]
+	if deleteCount == 0 && len(insertData) == 0 {
+		return []lsp.SemanticTokensEdit{}
+	}
+
+	return []lsp.SemanticTokensEdit{
+		{
+			Start:       uint32(prefix),
+			DeleteCount: uint32(deleteCount),
+			Data:        insertData,
+		},
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lsp
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/opentofu/tofu-ls/internal/document"
+)
+
+// SemanticTokensCache keeps the last semantic tokens sent to the client
+// for each document, so that subsequent requests can be answered
+// with the difference only, or without decoding the document again
+// if it has not changed since.
+type SemanticTokensCache struct {
+	mu      sync.Mutex
+	results map[document.Handle]semanticTokensResult
+	lastId  uint64
+}
+
+// SemanticTokensResult represents the semantic tokens
+// of a document as decoded and encoded for the client
+type SemanticTokensResult struct {
+	ResultID string
+	Tokens   []lang.SemanticToken
+	Data     []uint32
+}
+
+type semanticTokensResult struct {
+	SemanticTokensResult
+	version int
+
+	// stale results may still serve as a base for edits,
+	// but are not reused for the same version of the document
+	stale bool
+}
+
+func NewSemanticTokensCache() *SemanticTokensCache {
+	return &SemanticTokensCache{
+		results: make(map[document.Handle]semanticTokensResult),
+	}
+}
+
+// Put caches the tokens of the given version of the document,
+// replacing any earlier result, and returns the ID of the new result
+func (c *SemanticTokensCache) Put(dh document.Handle, version int, tokens []lang.SemanticToken, data []uint32) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The same version may be requested more than once and result
+	// in different tokens, e.g. after a referenced module has changed,
+	// so the ID is made unique by a sequence number
+	c.lastId++
+	resultId := fmt.Sprintf("%d.%d", version, c.lastId)
+
+	c.results[dh] = semanticTokensResult{
+		SemanticTokensResult: SemanticTokensResult{
+			ResultID: resultId,
+			Tokens:   tokens,
+			Data:     data,
+		},
+		version: version,
+	}
+
+	return resultId
+}
+
+// Get returns the encoded tokens of the document, if
+// the given result ID is the last one cached for it
+func (c *SemanticTokensCache) Get(dh document.Handle, resultId string) ([]uint32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.results[dh]
+	if !ok || result.ResultID != resultId {
+		return nil, false
+	}
+	return result.Data, true
+}
+
+// Current returns the last result cached for the document, if it
+// is of the given version and has not been invalidated since
+func (c *SemanticTokensCache) Current(dh document.Handle, version int) (SemanticTokensResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.results[dh]
+	if !ok || result.version != version || result.stale {
+		return SemanticTokensResult{}, false
+	}
+	return result.SemanticTokensResult, true
+}
+
+// Invalidate marks results of all documents in the directory as stale,
+// e.g. because schemas or references of the module have changed
+func (c *SemanticTokensCache) Invalidate(dir document.DirHandle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for dh, result := range c.results {
+		if dh.Dir == dir {
+			result.stale = true
+			c.results[dh] = result
+		}
+	}
+}
+
+// Remove drops any tokens cached for the document
+func (c *SemanticTokensCache) Remove(dh document.Handle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.results, dh)
+}
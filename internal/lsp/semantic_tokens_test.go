@@ -0,0 +1,115 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lsp
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+func TestSemanticTokensEdits(t *testing.T) {
+	testCases := []struct {
+		name          string
+		oldData       []uint32
+		newData       []uint32
+		expectedEdits []protocol.SemanticTokensEdit
+	}{
+		{
+			"no change",
+			[]uint32{0, 0, 8, 3, 0},
+			[]uint32{0, 0, 8, 3, 0},
+			[]protocol.SemanticTokensEdit{},
+		},
+		{
+			"token appended",
+			[]uint32{0, 0, 8, 3, 0},
+			[]uint32{0, 0, 8, 3, 0, 1, 2, 5, 1, 0},
+			[]protocol.SemanticTokensEdit{
+				{Start: 5, DeleteCount: 0, Data: []uint32{1, 2, 5, 1, 0}},
+			},
+		},
+		{
+			"token removed",
+			[]uint32{0, 0, 8, 3, 0, 1, 2, 5, 1, 0, 1, 2, 4, 1, 0},
+			[]uint32{0, 0, 8, 3, 0, 1, 2, 4, 1, 0},
+			[]protocol.SemanticTokensEdit{
+				{Start: 7, DeleteCount: 5, Data: []uint32{}},
+			},
+		},
+		{
+			"token changed in the middle",
+			[]uint32{0, 0, 8, 3, 0, 1, 2, 5, 1, 0, 1, 2, 4, 1, 0},
+			[]uint32{0, 0, 8, 3, 0, 1, 2, 6, 1, 0, 1, 2, 4, 1, 0},
+			[]protocol.SemanticTokensEdit{
+				{Start: 7, DeleteCount: 1, Data: []uint32{6}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			edits := SemanticTokensEdits(tc.oldData, tc.newData)
+			if diff := cmp.Diff(tc.expectedEdits, edits); diff != "" {
+				t.Fatalf("unexpected edits: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSemanticTokensCache(t *testing.T) {
+	cache := NewSemanticTokensCache()
+	dh := document.HandleFromPath("/test/main.tf")
+
+	firstId := cache.Put(dh, 1, nil, []uint32{0, 0, 8, 3, 0})
+	secondId := cache.Put(dh, 1, nil, []uint32{0, 0, 8, 3, 1})
+	if firstId == secondId {
+		t.Fatalf("expected unique result IDs, given %q twice", firstId)
+	}
+
+	_, ok := cache.Get(dh, firstId)
+	if ok {
+		t.Fatalf("expected result %q to be replaced", firstId)
+	}
+	data, ok := cache.Get(dh, secondId)
+	if !ok {
+		t.Fatalf("expected result %q to be cached", secondId)
+	}
+	if diff := cmp.Diff([]uint32{0, 0, 8, 3, 1}, data); diff != "" {
+		t.Fatalf("unexpected data: %s", diff)
+	}
+
+	result, ok := cache.Current(dh, 1)
+	if !ok {
+		t.Fatal("expected result of the same version to be current")
+	}
+	if result.ResultID != secondId {
+		t.Fatalf("expected current result %q, given %q", secondId, result.ResultID)
+	}
+	_, ok = cache.Current(dh, 2)
+	if ok {
+		t.Fatal("expected no current result of another version")
+	}
+
+	// Invalidated results are no longer current, but still serve for edits
+	cache.Invalidate(dh.Dir)
+	_, ok = cache.Current(dh, 1)
+	if ok {
+		t.Fatal("expected invalidated result not to be current")
+	}
+	_, ok = cache.Get(dh, secondId)
+	if !ok {
+		t.Fatalf("expected invalidated result %q to be cached", secondId)
+	}
+
+	cache.Remove(dh)
+	_, ok = cache.Get(dh, secondId)
+	if ok {
+		t.Fatalf("expected result %q to be removed", secondId)
+	}
+}
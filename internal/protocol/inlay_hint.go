@@ -0,0 +1,20 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package protocol
+
+import "encoding/json"
+
+// MarshalJSON encodes the tooltip as either a string or markup content,
+// rather than an object wrapping either of them
+func (t OrPTooltip_textDocument_inlayHint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Value)
+}
+
+// MarshalJSON encodes the tooltip as either a string or markup content,
+// rather than an object wrapping either of them
+func (t OrPTooltipPLabel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Value)
+}
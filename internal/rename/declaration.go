@@ -0,0 +1,205 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rename
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type DeclarationKind uint
+
+const (
+	DeclarationKindUnknown DeclarationKind = iota
+	DeclarationKindVariable
+	DeclarationKindLocal
+	DeclarationKindOutput
+	DeclarationKindResource
+	DeclarationKindDataSource
+	DeclarationKindModule
+)
+
+// Declaration represents a named item of module configuration
+// which can be renamed, such as a variable or a resource.
+type Declaration struct {
+	Path     lang.Path
+	Filename string
+	Kind     DeclarationKind
+
+	// Address is the address under which the declared item
+	// is referenced elsewhere, e.g. var.foo or aws_instance.foo
+	Address lang.Address
+
+	// Name is the (renameable) name of the declared item
+	Name string
+
+	// NameRange is the range of the name, excluding any quotes
+	NameRange hcl.Range
+
+	// Range is the range of the whole block or attribute
+	Range hcl.Range
+}
+
+// DeclarationAtPos returns the declaration whose name is at the given position.
+func DeclarationAtPos(path lang.Path, files map[string]*hcl.File, filename string, pos hcl.Pos) (*Declaration, bool) {
+	f, ok := files[filename]
+	if !ok {
+		return nil, false
+	}
+
+	for _, decl := range declarationsInFile(path, filename, f) {
+		if decl.NameRange.ContainsPos(pos) || decl.NameRange.End == pos {
+			return decl, true
+		}
+	}
+
+	return nil, false
+}
+
+func declarationsInFile(path lang.Path, filename string, f *hcl.File) []*Declaration {
+	decls := make([]*Declaration, 0)
+
+	// JSON configuration is not supported
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return decls
+	}
+
+	for _, block := range body.Blocks {
+		if block.Type == "locals" {
+			for _, attr := range block.Body.Attributes {
+				decls = append(decls, &Declaration{
+					Path:     path,
+					Filename: filename,
+					Kind:     DeclarationKindLocal,
+					Address: lang.Address{
+						lang.RootStep{Name: "local"},
+						lang.AttrStep{Name: attr.Name},
+					},
+					Name:      attr.Name,
+					NameRange: attr.NameRange,
+					Range:     attr.SrcRange,
+				})
+			}
+			continue
+		}
+
+		decl, ok := blockDeclaration(block)
+		if !ok {
+			continue
+		}
+		decl.Path = path
+		decl.Filename = filename
+		decls = append(decls, decl)
+	}
+
+	sort.SliceStable(decls, func(i, j int) bool {
+		return decls[i].NameRange.Start.Byte < decls[j].NameRange.Start.Byte
+	})
+
+	return decls
+}
+
+func blockDeclaration(block *hclsyntax.Block) (*Declaration, bool) {
+	var kind DeclarationKind
+	var addr lang.Address
+
+	switch block.Type {
+	case "variable":
+		if len(block.Labels) != 1 {
+			return nil, false
+		}
+		kind = DeclarationKindVariable
+		addr = lang.Address{
+			lang.RootStep{Name: "var"},
+			lang.AttrStep{Name: block.Labels[0]},
+		}
+	case "output":
+		if len(block.Labels) != 1 {
+			return nil, false
+		}
+		kind = DeclarationKindOutput
+		addr = lang.Address{
+			lang.RootStep{Name: "output"},
+			lang.AttrStep{Name: block.Labels[0]},
+		}
+	case "module":
+		if len(block.Labels) != 1 {
+			return nil, false
+		}
+		kind = DeclarationKindModule
+		addr = lang.Address{
+			lang.RootStep{Name: "module"},
+			lang.AttrStep{Name: block.Labels[0]},
+		}
+	case "resource":
+		if len(block.Labels) != 2 {
+			return nil, false
+		}
+		kind = DeclarationKindResource
+		addr = lang.Address{
+			lang.RootStep{Name: block.Labels[0]},
+			lang.AttrStep{Name: block.Labels[1]},
+		}
+	case "data":
+		if len(block.Labels) != 2 {
+			return nil, false
+		}
+		kind = DeclarationKindDataSource
+		addr = lang.Address{
+			lang.RootStep{Name: "data"},
+			lang.AttrStep{Name: block.Labels[0]},
+			lang.AttrStep{Name: block.Labels[1]},
+		}
+	default:
+		return nil, false
+	}
+
+	nameIdx := len(block.Labels) - 1
+	nameRange, ok := labelNameRange(block.LabelRanges[nameIdx], block.Labels[nameIdx])
+	if !ok {
+		return nil, false
+	}
+
+	return &Declaration{
+		Kind:      kind,
+		Address:   addr,
+		Name:      block.Labels[nameIdx],
+		NameRange: nameRange,
+		Range:     block.Range(),
+	}, true
+}
+
+// labelNameRange strips the quotes from the range of a quoted label
+func labelNameRange(rng hcl.Range, name string) (hcl.Range, bool) {
+	length := rng.End.Byte - rng.Start.Byte
+	switch length {
+	case len(name):
+		// unquoted label
+		return rng, true
+	case len(name) + 2:
+		return hcl.Range{
+			Filename: rng.Filename,
+			Start: hcl.Pos{
+				Line:   rng.Start.Line,
+				Column: rng.Start.Column + 1,
+				Byte:   rng.Start.Byte + 1,
+			},
+			End: hcl.Pos{
+				Line:   rng.End.Line,
+				Column: rng.End.Column - 1,
+				Byte:   rng.End.Byte - 1,
+			},
+		}, true
+	}
+
+	// The label likely contains escape sequences
+	// which we cannot safely rename
+	return hcl.Range{}, false
+}
@@ -0,0 +1,161 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rename
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestDeclarationAtPos(t *testing.T) {
+	cfg := `variable "region" {}
+
+locals {
+  name = "foo"
+}
+
+resource "aws_instance" "web" {}
+
+data "aws_ami" "ubuntu" {}
+
+module "network" {
+  source = "./network"
+}
+
+output "id" {
+  value = aws_instance.web.id
+}
+
+provider "aws" {}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	files := map[string]*hcl.File{"main.tf": f}
+	path := lang.Path{Path: "/tmp", LanguageID: "opentofu"}
+
+	testCases := []struct {
+		pos          hcl.Pos
+		expectedKind DeclarationKind
+		expectedAddr lang.Address
+		expectFound  bool
+	}{
+		{
+			hcl.Pos{Line: 1, Column: 12, Byte: 11},
+			DeclarationKindVariable,
+			lang.Address{lang.RootStep{Name: "var"}, lang.AttrStep{Name: "region"}},
+			true,
+		},
+		{
+			// end of the name
+			hcl.Pos{Line: 1, Column: 17, Byte: 16},
+			DeclarationKindVariable,
+			lang.Address{lang.RootStep{Name: "var"}, lang.AttrStep{Name: "region"}},
+			true,
+		},
+		{
+			// opening quote
+			hcl.Pos{Line: 1, Column: 10, Byte: 9},
+			DeclarationKindUnknown,
+			nil,
+			false,
+		},
+		{
+			hcl.Pos{Line: 4, Column: 4, Byte: 34},
+			DeclarationKindLocal,
+			lang.Address{lang.RootStep{Name: "local"}, lang.AttrStep{Name: "name"}},
+			true,
+		},
+		{
+			// resource type is not renameable
+			hcl.Pos{Line: 7, Column: 12, Byte: 60},
+			DeclarationKindUnknown,
+			nil,
+			false,
+		},
+		{
+			hcl.Pos{Line: 7, Column: 27, Byte: 75},
+			DeclarationKindResource,
+			lang.Address{lang.RootStep{Name: "aws_instance"}, lang.AttrStep{Name: "web"}},
+			true,
+		},
+		{
+			hcl.Pos{Line: 9, Column: 18, Byte: 100},
+			DeclarationKindDataSource,
+			lang.Address{lang.RootStep{Name: "data"}, lang.AttrStep{Name: "aws_ami"}, lang.AttrStep{Name: "ubuntu"}},
+			true,
+		},
+		{
+			hcl.Pos{Line: 11, Column: 10, Byte: 120},
+			DeclarationKindModule,
+			lang.Address{lang.RootStep{Name: "module"}, lang.AttrStep{Name: "network"}},
+			true,
+		},
+		{
+			hcl.Pos{Line: 15, Column: 10, Byte: 165},
+			DeclarationKindOutput,
+			lang.Address{lang.RootStep{Name: "output"}, lang.AttrStep{Name: "id"}},
+			true,
+		},
+		{
+			hcl.Pos{Line: 19, Column: 11, Byte: 213},
+			DeclarationKindUnknown,
+			nil,
+			false,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			decl, ok := DeclarationAtPos(path, files, "main.tf", tc.pos)
+			if ok != tc.expectFound {
+				t.Fatalf("expected found: %t, given: %t (%#v)", tc.expectFound, ok, decl)
+			}
+			if !ok {
+				return
+			}
+			if decl.Kind != tc.expectedKind {
+				t.Fatalf("expected kind %d, given %d", tc.expectedKind, decl.Kind)
+			}
+			if diff := cmp.Diff(tc.expectedAddr, decl.Address); diff != "" {
+				t.Fatalf("unexpected address: %s", diff)
+			}
+		})
+	}
+}
+
+func TestLabelNameRange(t *testing.T) {
+	rng := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 1, Column: 10, Byte: 9},
+		End:      hcl.Pos{Line: 1, Column: 15, Byte: 14},
+	}
+
+	nameRange, ok := labelNameRange(rng, "foo")
+	if !ok {
+		t.Fatal("expected quoted label to be renameable")
+	}
+	expectedRange := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 1, Column: 11, Byte: 10},
+		End:      hcl.Pos{Line: 1, Column: 14, Byte: 13},
+	}
+	if diff := cmp.Diff(expectedRange, nameRange); diff != "" {
+		t.Fatalf("unexpected range: %s", diff)
+	}
+
+	// label with escape sequences
+	_, ok = labelNameRange(rng, "f")
+	if ok {
+		t.Fatal("expected label with escape sequences not to be renameable")
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rename
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+var (
+	resourceScopeId = lang.ScopeId("resource")
+	moduleScopeId   = lang.ScopeId("module")
+)
+
+// movedBlock represents a moved block declared in the module
+type movedBlock struct {
+	filename string
+	rng      hcl.Range
+	from     hcl.Traversal
+	to       hcl.Traversal
+}
+
+// movedRefactoring describes how moved blocks need to change
+// when a managed resource or a module call is renamed
+type movedRefactoring struct {
+	decl *Declaration
+	from lang.Address
+	to   lang.Address
+
+	// existing is the list of moved blocks already declared in the module
+	existing []movedBlock
+
+	// reverted is an existing moved block which the rename reverts,
+	// i.e. one moving from the new address to the old one
+	reverted *movedBlock
+}
+
+// newMovedRefactoring returns the moved refactoring for the declaration,
+// if the declaration is a managed resource or module call, i.e. whenever
+// OpenTofu would otherwise plan to destroy and recreate the objects.
+//
+// Reference targets are used to confirm that the address is indeed
+// a resource or module call known to the module.
+func newMovedRefactoring(files map[string]*hcl.File, targets reference.Targets, decl *Declaration, newName string) (*movedRefactoring, bool) {
+	if !isMovable(targets, decl) {
+		return nil, false
+	}
+
+	newAddr := make(lang.Address, len(decl.Address))
+	copy(newAddr, decl.Address)
+	newAddr[len(newAddr)-1] = lang.AttrStep{Name: newName}
+
+	mr := &movedRefactoring{
+		decl:     decl,
+		from:     decl.Address,
+		to:       newAddr,
+		existing: movedBlocksInFiles(files),
+	}
+
+	for i, mb := range mr.existing {
+		if traversalEqualsAddress(mb.from, newAddr) && traversalEqualsAddress(mb.to, decl.Address) {
+			mr.reverted = &mr.existing[i]
+			break
+		}
+	}
+
+	return mr, true
+}
+
+func isMovable(targets reference.Targets, decl *Declaration) bool {
+	if decl.Kind != DeclarationKindResource && decl.Kind != DeclarationKindModule {
+		return false
+	}
+	return hasMovableTarget(targets, decl.Address)
+}
+
+func hasMovableTarget(targets reference.Targets, addr lang.Address) bool {
+	for _, target := range targets {
+		if !target.Addr.Equals(addr) {
+			continue
+		}
+		if target.ScopeId == resourceScopeId || target.ScopeId == moduleScopeId {
+			return true
+		}
+	}
+	return false
+}
+
+// excludesOrigin reports whether the given origin (found in the module
+// of the declaration) should be left as is. This is the case for
+// addresses in existing moved blocks, which describe the history
+// of addresses and are chained with the new moved block instead.
+func (mr *movedRefactoring) excludesOrigin(origin reference.Origin) bool {
+	rng := origin.OriginRange()
+
+	for _, mb := range mr.existing {
+		if mb.filename == rng.Filename && rangeContainsRange(mb.rng, rng) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// edits returns edits which either add a new moved block
+// to the file of the declaration, or remove the moved block
+// which the rename reverts.
+func (mr *movedRefactoring) edits(files map[string]*hcl.File) []lang.TextEdit {
+	if mr.reverted != nil {
+		return []lang.TextEdit{
+			{
+				Range:   blockRangeWithNewline(files[mr.reverted.filename], mr.reverted.rng),
+				NewText: "",
+				Snippet: "",
+			},
+		}
+	}
+
+	f, ok := files[mr.decl.Filename]
+	if !ok {
+		return []lang.TextEdit{}
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return []lang.TextEdit{}
+	}
+
+	prefix := "\n"
+	if len(f.Bytes) > 0 && f.Bytes[len(f.Bytes)-1] != '\n' {
+		prefix = "\n\n"
+	}
+	text := fmt.Sprintf("%smoved {\n  from = %s\n  to   = %s\n}\n",
+		prefix, mr.from.String(), mr.to.String())
+
+	endPos := body.SrcRange.End
+	return []lang.TextEdit{
+		{
+			Range: hcl.Range{
+				Filename: mr.decl.Filename,
+				Start:    endPos,
+				End:      endPos,
+			},
+			NewText: text,
+			Snippet: text,
+		},
+	}
+}
+
+// hasMovedBlock reports whether the move is already
+// recorded in any of the existing moved blocks
+func (mr *movedRefactoring) hasMovedBlock() bool {
+	for _, mb := range mr.existing {
+		if traversalEqualsAddress(mb.from, mr.from) && traversalEqualsAddress(mb.to, mr.to) {
+			return true
+		}
+	}
+	return false
+}
+
+func movedBlocksInFiles(files map[string]*hcl.File) []movedBlock {
+	blocks := make([]movedBlock, 0)
+
+	for filename, f := range files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "moved" {
+				continue
+			}
+			fromAttr, ok := block.Body.Attributes["from"]
+			if !ok {
+				continue
+			}
+			toAttr, ok := block.Body.Attributes["to"]
+			if !ok {
+				continue
+			}
+
+			from, diags := hcl.AbsTraversalForExpr(fromAttr.Expr)
+			if diags.HasErrors() {
+				continue
+			}
+			to, diags := hcl.AbsTraversalForExpr(toAttr.Expr)
+			if diags.HasErrors() {
+				continue
+			}
+
+			blocks = append(blocks, movedBlock{
+				filename: filename,
+				rng:      block.Range(),
+				from:     from,
+				to:       to,
+			})
+		}
+	}
+
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if blocks[i].filename != blocks[j].filename {
+			return blocks[i].filename < blocks[j].filename
+		}
+		return blocks[i].rng.Start.Byte < blocks[j].rng.Start.Byte
+	})
+
+	return blocks
+}
+
+// traversalEqualsAddress reports whether the traversal is exactly
+// the given address, i.e. it does not refer to any particular instance
+func traversalEqualsAddress(traversal hcl.Traversal, addr lang.Address) bool {
+	if len(traversal) != len(addr) {
+		return false
+	}
+
+	for i, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			if addr[i] != (lang.RootStep{Name: s.Name}) {
+				return false
+			}
+		case hcl.TraverseAttr:
+			if addr[i] != (lang.AttrStep{Name: s.Name}) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func rangeContainsRange(outer, inner hcl.Range) bool {
+	return outer.Start.Byte <= inner.Start.Byte && inner.End.Byte <= outer.End.Byte
+}
+
+// blockRangeWithNewline extends the range of a block to also
+// cover the newline following it, so removing the block
+// does not leave an empty line behind.
+func blockRangeWithNewline(f *hcl.File, rng hcl.Range) hcl.Range {
+	if f == nil || rng.End.Byte >= len(f.Bytes) || f.Bytes[rng.End.Byte] != '\n' {
+		return rng
+	}
+
+	rng.End = hcl.Pos{
+		Line:   rng.End.Line + 1,
+		Column: 1,
+		Byte:   rng.End.Byte + 1,
+	}
+	return rng
+}
+
+// MovedBlockEdits returns edits which add a moved block for the managed
+// resource or module call whose label is at the given position, if it
+// is relabeled in any of the moves (see [UpdateMoves]) and the move
+// is not recorded in any moved block yet.
+func MovedBlockEdits(pathReader decoder.PathReader, moves []Move, path lang.Path, filename string, pos hcl.Pos) (*Move, Edits, error) {
+	pathCtx, err := pathReader.PathContext(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decl, ok := DeclarationAtPos(path, pathCtx.Files, filename, pos)
+	if !ok || !isMovable(pathCtx.ReferenceTargets, decl) {
+		return nil, nil, nil
+	}
+	var move *Move
+	for _, m := range moves {
+		if m.To.Equals(decl.Address) {
+			move = &m
+			break
+		}
+	}
+	if move == nil {
+		return nil, nil, nil
+	}
+
+	mr := &movedRefactoring{
+		decl:     decl,
+		from:     move.From,
+		to:       move.To,
+		existing: movedBlocksInFiles(pathCtx.Files),
+	}
+	if mr.hasMovedBlock() {
+		return nil, nil, nil
+	}
+
+	edits := make(Edits, 0)
+	for _, te := range mr.edits(pathCtx.Files) {
+		edits.add(path.Path, te.Range, te.NewText)
+	}
+
+	return move, edits, nil
+}
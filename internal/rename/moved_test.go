@@ -0,0 +1,322 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rename
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type testPathReader struct {
+	paths map[string]*decoder.PathContext
+}
+
+func (r *testPathReader) Paths(ctx context.Context) []lang.Path {
+	paths := make([]lang.Path, 0)
+	for path := range r.paths {
+		paths = append(paths, lang.Path{Path: path, LanguageID: "opentofu"})
+	}
+	return paths
+}
+
+func (r *testPathReader) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	return r.paths[path.Path], nil
+}
+
+func TestRenameEdits_revertedMove(t *testing.T) {
+	cfg := `module "new" {
+  source = "./app"
+}
+
+moved {
+  from = module.old
+  to   = module.new
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	path := lang.Path{Path: "/tmp/mod", LanguageID: "opentofu"}
+	moduleAddr := lang.Address{lang.RootStep{Name: "module"}, lang.AttrStep{Name: "new"}}
+
+	pathReader := &testPathReader{
+		paths: map[string]*decoder.PathContext{
+			path.Path: {
+				Files: map[string]*hcl.File{"main.tf": f},
+				ReferenceTargets: reference.Targets{
+					{
+						Addr:    moduleAddr,
+						ScopeId: lang.ScopeId("module"),
+					},
+				},
+				ReferenceOrigins: reference.Origins{
+					reference.LocalOrigin{
+						Addr: lang.Address{lang.RootStep{Name: "module"}, lang.AttrStep{Name: "old"}},
+						Range: hcl.Range{
+							Filename: "main.tf",
+							Start:    hcl.Pos{Line: 6, Column: 10, Byte: 54},
+							End:      hcl.Pos{Line: 6, Column: 20, Byte: 64},
+						},
+					},
+					reference.LocalOrigin{
+						Addr: moduleAddr,
+						Range: hcl.Range{
+							Filename: "main.tf",
+							Start:    hcl.Pos{Line: 7, Column: 10, Byte: 74},
+							End:      hcl.Pos{Line: 7, Column: 20, Byte: 84},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	decl, ok := DeclarationAtPos(path, map[string]*hcl.File{"main.tf": f}, "main.tf",
+		hcl.Pos{Line: 1, Column: 10, Byte: 9})
+	if !ok {
+		t.Fatal("expected declaration to be found")
+	}
+
+	edits, err := RenameEdits(context.Background(), pathReader, decl, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rename reverts the previous one, so the moved block
+	// is removed rather than chained with a new one.
+	expectedEdits := Edits{
+		"/tmp/mod/main.tf": {
+			{
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 1, Column: 9, Byte: 8},
+					End:      hcl.Pos{Line: 1, Column: 12, Byte: 11},
+				},
+				NewText: "old",
+				Snippet: "old",
+			},
+			{
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 5, Column: 1, Byte: 37},
+					End:      hcl.Pos{Line: 9, Column: 1, Byte: 87},
+				},
+				NewText: "",
+				Snippet: "",
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedEdits, edits); diff != "" {
+		t.Fatalf("unexpected edits: %s", diff)
+	}
+}
+
+func TestUpdateMoves(t *testing.T) {
+	addr := func(name string) lang.Address {
+		return lang.Address{lang.RootStep{Name: "random_pet"}, lang.AttrStep{Name: name}}
+	}
+	target := func(name string, line int) reference.Target {
+		return reference.Target{
+			Addr:    addr(name),
+			ScopeId: lang.ScopeId("resource"),
+			RangePtr: &hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: line, Column: 1, Byte: (line - 1) * 10},
+				End:      hcl.Pos{Line: line, Column: 10, Byte: (line-1)*10 + 9},
+			},
+		}
+	}
+	movedCfg := `moved {
+  from = random_pet.a
+  to   = random_pet.b
+}
+`
+	movedFile, diags := hclsyntax.ParseConfig([]byte(movedCfg), "moved.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	testCases := []struct {
+		name          string
+		moves         []Move
+		files         map[string]*hcl.File
+		prevTargets   reference.Targets
+		targets       reference.Targets
+		expectedMoves []Move
+	}{
+		{
+			"relabeled",
+			[]Move{},
+			map[string]*hcl.File{},
+			reference.Targets{target("a", 1), target("x", 5)},
+			reference.Targets{target("b", 1), target("x", 5)},
+			[]Move{{From: addr("a"), To: addr("b")}},
+		},
+		{
+			"relabeled again",
+			[]Move{{From: addr("a"), To: addr("b")}},
+			map[string]*hcl.File{},
+			reference.Targets{target("b", 1)},
+			reference.Targets{target("c", 1)},
+			[]Move{{From: addr("a"), To: addr("c")}},
+		},
+		{
+			"relabeled back",
+			[]Move{{From: addr("a"), To: addr("b")}},
+			map[string]*hcl.File{},
+			reference.Targets{target("b", 1)},
+			reference.Targets{target("a", 1)},
+			[]Move{},
+		},
+		{
+			"reordered",
+			[]Move{},
+			map[string]*hcl.File{},
+			reference.Targets{target("a", 1), target("b", 5)},
+			reference.Targets{target("b", 1), target("a", 5)},
+			[]Move{},
+		},
+		{
+			"block added",
+			[]Move{},
+			map[string]*hcl.File{},
+			reference.Targets{target("a", 1)},
+			reference.Targets{target("b", 1), target("c", 5)},
+			[]Move{},
+		},
+		{
+			"block removed",
+			[]Move{{From: addr("a"), To: addr("b")}},
+			map[string]*hcl.File{},
+			reference.Targets{target("b", 1)},
+			reference.Targets{},
+			[]Move{},
+		},
+		{
+			"recorded in moved block",
+			[]Move{{From: addr("a"), To: addr("b")}},
+			map[string]*hcl.File{"moved.tf": movedFile},
+			reference.Targets{target("b", 1)},
+			reference.Targets{target("b", 1)},
+			[]Move{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			moves := UpdateMoves(tc.moves, tc.files, tc.prevTargets, tc.targets)
+			if diff := cmp.Diff(tc.expectedMoves, moves); diff != "" {
+				t.Fatalf("unexpected moves: %s", diff)
+			}
+		})
+	}
+}
+
+func TestMovedBlockEdits(t *testing.T) {
+	cfg := `resource "random_pet" "new" {
+  count = 2
+}
+
+module "app" {
+  source = "./app"
+}
+
+moved {
+  from = module.old
+  to   = module.app
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	path := lang.Path{Path: "/tmp/mod", LanguageID: "opentofu"}
+	resourceAddr := lang.Address{lang.RootStep{Name: "random_pet"}, lang.AttrStep{Name: "new"}}
+	moduleAddr := lang.Address{lang.RootStep{Name: "module"}, lang.AttrStep{Name: "app"}}
+
+	pathReader := &testPathReader{
+		paths: map[string]*decoder.PathContext{
+			path.Path: {
+				Files: map[string]*hcl.File{"main.tf": f},
+				ReferenceTargets: reference.Targets{
+					{
+						Addr:    resourceAddr,
+						ScopeId: lang.ScopeId("resource"),
+					},
+					{
+						Addr:    moduleAddr,
+						ScopeId: lang.ScopeId("module"),
+					},
+				},
+			},
+		},
+	}
+
+	resourcePos := hcl.Pos{Line: 1, Column: 25, Byte: 24}
+	modulePos := hcl.Pos{Line: 5, Column: 10, Byte: 54}
+
+	moves := []Move{}
+	move, _, err := MovedBlockEdits(pathReader, moves, path, "main.tf", resourcePos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move != nil {
+		t.Fatalf("expected no moved block to be offered before rename, given %#v", move)
+	}
+
+	moves = []Move{
+		{
+			From: lang.Address{lang.RootStep{Name: "random_pet"}, lang.AttrStep{Name: "old"}},
+			To:   resourceAddr,
+		},
+		{
+			From: lang.Address{lang.RootStep{Name: "module"}, lang.AttrStep{Name: "old"}},
+			To:   moduleAddr,
+		},
+	}
+
+	move, edits, err := MovedBlockEdits(pathReader, moves, path, "main.tf", resourcePos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move == nil {
+		t.Fatal("expected moved block to be offered")
+	}
+	text := "\nmoved {\n  from = random_pet.old\n  to   = random_pet.new\n}\n"
+	expectedEdits := Edits{
+		"/tmp/mod/main.tf": {
+			{
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 13, Column: 1, Byte: 132},
+					End:      hcl.Pos{Line: 13, Column: 1, Byte: 132},
+				},
+				NewText: text,
+				Snippet: text,
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedEdits, edits); diff != "" {
+		t.Fatalf("unexpected edits: %s", diff)
+	}
+
+	// The move of the module call is already recorded
+	move, _, err = MovedBlockEdits(pathReader, moves, path, "main.tf", modulePos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move != nil {
+		t.Fatalf("expected no moved block to be offered for recorded move, given %#v", move)
+	}
+}
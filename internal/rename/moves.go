@@ -0,0 +1,161 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rename
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Move represents a managed resource or module call
+// relabeled from one address to another within a module
+type Move struct {
+	From lang.Address
+	To   lang.Address
+}
+
+// UpdateMoves returns the moves of managed resources and module calls
+// in a module, once its reference targets are decoded again,
+// i.e. once they changed from prevTargets to targets.
+//
+// A move is derived from any block whose label changed in between,
+// whether the label was edited by hand or via rename. Moves are chained,
+// i.e. relabeling a to b and then b to c is a single move from a to c,
+// and relabeling b back to a cancels out the earlier move.
+//
+// Moves which are already recorded in a moved block, or whose block
+// no longer exists, are dropped.
+func UpdateMoves(moves []Move, files map[string]*hcl.File, prevTargets, targets reference.Targets) []Move {
+	updated := make([]Move, len(moves))
+	copy(updated, moves)
+
+	for _, change := range labelChanges(movableBlocks(prevTargets), movableBlocks(targets)) {
+		updated = chainMove(updated, change)
+	}
+
+	declared := make([]lang.Address, 0)
+	for _, addrs := range movableBlocks(targets) {
+		declared = append(declared, addrs...)
+	}
+	existing := movedBlocksInFiles(files)
+
+	result := make([]Move, 0, len(updated))
+	for _, move := range updated {
+		if !containsAddress(declared, move.To) {
+			continue
+		}
+		mr := &movedRefactoring{from: move.From, to: move.To, existing: existing}
+		if mr.hasMovedBlock() {
+			continue
+		}
+		result = append(result, move)
+	}
+
+	return result
+}
+
+func chainMove(moves []Move, move Move) []Move {
+	chained := make([]Move, 0, len(moves)+1)
+	for _, existing := range moves {
+		if existing.To.Equals(move.From) {
+			move.From = existing.From
+			continue
+		}
+		chained = append(chained, existing)
+	}
+	if !move.From.Equals(move.To) {
+		chained = append(chained, move)
+	}
+	return chained
+}
+
+// movableKey groups blocks which can be relabeled into each other,
+// i.e. resources of the same type or module calls within the same file
+type movableKey struct {
+	filename string
+	kind     lang.AddressStep
+}
+
+// movableBlocks returns addresses of managed resources and module calls
+// declared in the targets, grouped by file and kind, in declaration order
+func movableBlocks(targets reference.Targets) map[movableKey][]lang.Address {
+	type block struct {
+		addr lang.Address
+		pos  hcl.Pos
+	}
+	blocks := make(map[movableKey][]block)
+
+	for _, target := range targets {
+		if target.ScopeId != resourceScopeId && target.ScopeId != moduleScopeId {
+			continue
+		}
+		if len(target.Addr) != 2 || target.RangePtr == nil {
+			continue
+		}
+
+		key := movableKey{filename: target.RangePtr.Filename, kind: target.Addr[0]}
+		seen := false
+		for _, b := range blocks[key] {
+			if b.addr.Equals(target.Addr) {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			blocks[key] = append(blocks[key], block{addr: target.Addr, pos: target.RangePtr.Start})
+		}
+	}
+
+	addrs := make(map[movableKey][]lang.Address, len(blocks))
+	for key, kindBlocks := range blocks {
+		sort.SliceStable(kindBlocks, func(i, j int) bool {
+			return kindBlocks[i].pos.Byte < kindBlocks[j].pos.Byte
+		})
+		for _, b := range kindBlocks {
+			addrs[key] = append(addrs[key], b.addr)
+		}
+	}
+
+	return addrs
+}
+
+// labelChanges pairs blocks declared before and after by their order
+// within the file, to find the ones which were relabeled.
+//
+// Blocks are only paired when none were added or removed in between,
+// and blocks which were merely reordered are not considered relabeled.
+func labelChanges(prev, next map[movableKey][]lang.Address) []Move {
+	changes := make([]Move, 0)
+
+	for key, prevAddrs := range prev {
+		nextAddrs, ok := next[key]
+		if !ok || len(nextAddrs) != len(prevAddrs) {
+			continue
+		}
+
+		for i, from := range prevAddrs {
+			to := nextAddrs[i]
+			if from.Equals(to) || containsAddress(nextAddrs, from) || containsAddress(prevAddrs, to) {
+				continue
+			}
+			changes = append(changes, Move{From: from, To: to})
+		}
+	}
+
+	return changes
+}
+
+func containsAddress(addrs []lang.Address, addr lang.Address) bool {
+	for _, a := range addrs {
+		if a.Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
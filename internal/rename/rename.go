@@ -0,0 +1,299 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rename
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// BuiltinReferenceError is returned when the reference
+// to be renamed is not declared anywhere in the configuration,
+// e.g. path.module or terraform.workspace.
+type BuiltinReferenceError struct {
+	Addr lang.Address
+}
+
+func (e *BuiltinReferenceError) Error() string {
+	return fmt.Sprintf("%s is a built-in reference and cannot be renamed", e.Addr)
+}
+
+// InvalidNameError is returned when the new name is not a valid identifier
+type InvalidNameError struct {
+	Name string
+}
+
+func (e *InvalidNameError) Error() string {
+	return fmt.Sprintf("%q is not a valid identifier", e.Name)
+}
+
+// Edits represents text edits to apply, grouped by the absolute path of the file
+type Edits map[string][]lang.TextEdit
+
+// Target finds the declaration to be renamed from a position in a file,
+// which may point either to the declaration itself, or to any reference to it.
+//
+// The returned range is the range of the name under the given position.
+// No declaration is returned if there is nothing renameable at the position.
+func Target(pathReader decoder.PathReader, path lang.Path, filename string, pos hcl.Pos) (*Declaration, hcl.Range, error) {
+	pathCtx, err := pathReader.PathContext(path)
+	if err != nil {
+		return nil, hcl.Range{}, err
+	}
+
+	origins, ok := pathCtx.ReferenceOrigins.AtPos(filename, pos)
+	if ok {
+		return targetForOrigins(pathReader, pathCtx, path, origins, pos)
+	}
+
+	decl, ok := DeclarationAtPos(path, pathCtx.Files, filename, pos)
+	if !ok {
+		return nil, hcl.Range{}, nil
+	}
+
+	return decl, decl.NameRange, nil
+}
+
+func targetForOrigins(pathReader decoder.PathReader, pathCtx *decoder.PathContext, path lang.Path, origins reference.Origins, pos hcl.Pos) (*Declaration, hcl.Range, error) {
+	var decl *Declaration
+	var nameRange hcl.Range
+	var builtinAddr lang.Address
+
+	for _, origin := range origins {
+		matchableOrigin, ok := origin.(reference.MatchableOrigin)
+		if !ok {
+			continue
+		}
+		originAddr := matchableOrigin.Address()
+
+		targetCtx := pathCtx
+		targetPath := path
+		if pathOrigin, ok := origin.(reference.PathOrigin); ok {
+			ctx, err := pathReader.PathContext(pathOrigin.TargetPath)
+			if err != nil {
+				continue
+			}
+			targetCtx = ctx
+			targetPath = pathOrigin.TargetPath
+		}
+
+		originDecl, ok := declarationForAddress(targetPath, targetCtx.Files, originAddr)
+		if !ok {
+			if len(originAddr) > 0 && isBuiltinRoot(originAddr[0].String()) {
+				builtinAddr = originAddr
+			}
+			continue
+		}
+
+		rng, ok := originNameRange(pathCtx.Files, origin, originDecl)
+		if !ok {
+			continue
+		}
+
+		// The same expression may reference more than one declaration,
+		// e.g. module.foo.bar references both the module call
+		// and the output of the module. We prefer the one under the cursor.
+		if decl == nil || rng.ContainsPos(pos) || rng.End == pos {
+			decl = originDecl
+			nameRange = rng
+		}
+	}
+
+	if decl == nil && builtinAddr != nil {
+		return nil, hcl.Range{}, &BuiltinReferenceError{Addr: builtinAddr}
+	}
+
+	return decl, nameRange, nil
+}
+
+// declarationForAddress finds the declaration which
+// the given (possibly nested) address refers to
+func declarationForAddress(path lang.Path, files map[string]*hcl.File, addr lang.Address) (*Declaration, bool) {
+	for name, f := range files {
+		for _, decl := range declarationsInFile(path, name, f) {
+			if len(addr) >= len(decl.Address) && decl.Address.Equals(addr.FirstSteps(uint(len(decl.Address)))) {
+				return decl, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// isBuiltinRoot reports whether references with the given root
+// are provided by OpenTofu rather than declared in the configuration
+func isBuiltinRoot(name string) bool {
+	switch name {
+	case "path", "terraform", "count", "each", "self":
+		return true
+	}
+	return false
+}
+
+// RenameEdits returns text edits which rename the given declaration
+// along with all references to it, across all paths known to the path reader.
+func RenameEdits(ctx context.Context, pathReader decoder.PathReader, decl *Declaration, newName string) (Edits, error) {
+	if !hclsyntax.ValidIdentifier(newName) {
+		return nil, &InvalidNameError{Name: newName}
+	}
+
+	edits := make(Edits, 0)
+	edits.add(decl.Path.Path, decl.NameRange, newName)
+
+	declCtx, err := pathReader.PathContext(decl.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Renaming a managed resource or a module call would make OpenTofu
+	// destroy and recreate it, unless the move is recorded in a moved block.
+	// Adding one is offered separately (see [MovedBlockEdits]), but a moved
+	// block which the rename reverts must go, as OpenTofu rejects moves
+	// from an address which is still declared.
+	moved, hasMoved := newMovedRefactoring(declCtx.Files, declCtx.ReferenceTargets, decl, newName)
+	if hasMoved && moved.reverted != nil {
+		for _, te := range moved.edits(declCtx.Files) {
+			edits.add(decl.Path.Path, te.Range, te.NewText)
+		}
+	}
+
+	for _, p := range pathReader.Paths(ctx) {
+		pathCtx, err := pathReader.PathContext(p)
+		if err != nil {
+			continue
+		}
+
+		for _, origin := range pathCtx.ReferenceOrigins {
+			if !originReferencesDeclaration(p, origin, decl) {
+				continue
+			}
+			if hasMoved && p.Equals(decl.Path) && moved.excludesOrigin(origin) {
+				continue
+			}
+			nameRange, ok := originNameRange(pathCtx.Files, origin, decl)
+			if !ok {
+				continue
+			}
+			edits.add(p.Path, nameRange, newName)
+		}
+	}
+
+	for filePath := range edits {
+		sort.SliceStable(edits[filePath], func(i, j int) bool {
+			return edits[filePath][i].Range.Start.Byte < edits[filePath][j].Range.Start.Byte
+		})
+	}
+
+	return edits, nil
+}
+
+// originReferencesDeclaration reports whether the origin found in the given path
+// references the declaration, or any attribute or element nested in it
+func originReferencesDeclaration(path lang.Path, origin reference.Origin, decl *Declaration) bool {
+	var targetPath lang.Path
+	var addr lang.Address
+
+	switch o := origin.(type) {
+	case reference.LocalOrigin:
+		targetPath = path
+		addr = o.Addr
+	case reference.PathOrigin:
+		targetPath = o.TargetPath
+		addr = o.TargetAddr
+	default:
+		return false
+	}
+
+	if !targetPath.Equals(decl.Path) || len(addr) < len(decl.Address) {
+		return false
+	}
+
+	return decl.Address.Equals(addr.FirstSteps(uint(len(decl.Address))))
+}
+
+func (e Edits) add(dirPath string, rng hcl.Range, newText string) {
+	filePath := filepath.Join(dirPath, rng.Filename)
+
+	for _, edit := range e[filePath] {
+		if edit.Range == rng {
+			// the same origin may match multiple targets
+			return
+		}
+	}
+
+	e[filePath] = append(e[filePath], lang.TextEdit{
+		Range:   rng,
+		NewText: newText,
+		Snippet: newText,
+	})
+}
+
+// originNameRange finds the range of the name of the declaration
+// within a reference origin, e.g. "foo" in var.foo.bar.
+func originNameRange(files map[string]*hcl.File, origin reference.Origin, decl *Declaration) (hcl.Range, bool) {
+	matchableOrigin, ok := origin.(reference.MatchableOrigin)
+	if !ok {
+		return hcl.Range{}, false
+	}
+	originAddr := matchableOrigin.Address()
+
+	if localOrigin, ok := origin.(reference.LocalOrigin); ok {
+		// Avoid renaming references which just happen to match
+		// by local address, such as self.foo
+		if len(localOrigin.Addr) == 0 || localOrigin.Addr[0].String() != decl.Address[0].String() {
+			return hcl.Range{}, false
+		}
+	}
+
+	rng := origin.OriginRange()
+	f, ok := files[rng.Filename]
+	if !ok {
+		return hcl.Range{}, false
+	}
+	if rng.End.Byte > len(f.Bytes) {
+		return hcl.Range{}, false
+	}
+
+	traversal, diags := hclsyntax.ParseTraversalAbs(rng.SliceBytes(f.Bytes), rng.Filename, rng.Start)
+	if diags.HasErrors() {
+		return hcl.Range{}, false
+	}
+
+	// The origin address is not necessarily the same as the traversal
+	// in the source. For example, the name of a module input "foo"
+	// is an origin with the address var.foo in the module itself.
+	idx := len(decl.Address) - 1 + len(traversal) - len(originAddr)
+	if idx < 0 || idx >= len(traversal) {
+		return hcl.Range{}, false
+	}
+
+	switch step := traversal[idx].(type) {
+	case hcl.TraverseRoot:
+		if step.Name != decl.Name {
+			return hcl.Range{}, false
+		}
+		return step.SrcRange, true
+	case hcl.TraverseAttr:
+		if step.Name != decl.Name {
+			return hcl.Range{}, false
+		}
+		// skip the leading dot
+		nameRange := step.SrcRange
+		nameRange.Start.Column++
+		nameRange.Start.Byte++
+		return nameRange, true
+	}
+
+	return hcl.Range{}, false
+}
@@ -0,0 +1,147 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package selection computes ranges for expanding and shrinking
+// selections in HCL files purely from their syntax, i.e. without any schema.
+package selection
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// Ranges returns the ranges of syntax nodes around the given position,
+// ordered from the innermost to the outermost one, where each range
+// contains the previous one.
+//
+// Expanding from a position inside a reference goes through
+// the traversal, the attribute expression, the attribute,
+// the enclosing block bodies and blocks up to the whole file.
+// JSON files are not supported.
+func Ranges(f *hcl.File, pos hcl.Pos) []hcl.Range {
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return []hcl.Range{}
+	}
+
+	ranges := bodyRanges(body, pos)
+	ranges = append(ranges, body.SrcRange)
+
+	return nest(ranges)
+}
+
+// bodyRanges returns the ranges of all attributes, blocks and their
+// nested syntax nodes which contain the position, in no particular order.
+// The range of the body itself is not included.
+func bodyRanges(body *hclsyntax.Body, pos hcl.Pos) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+
+	for _, attr := range body.Attributes {
+		if !containsPos(attr.SrcRange, pos) {
+			continue
+		}
+		ranges = append(ranges, attr.SrcRange)
+		ranges = append(ranges, exprRanges(attr.Expr, pos)...)
+	}
+
+	for _, block := range body.Blocks {
+		if !containsPos(block.Range(), pos) {
+			continue
+		}
+		ranges = append(ranges, block.Range())
+		if containsPos(block.Body.SrcRange, pos) {
+			ranges = append(ranges, block.Body.SrcRange)
+			ranges = append(ranges, bodyRanges(block.Body, pos)...)
+		}
+	}
+
+	return ranges
+}
+
+func exprRanges(expr hclsyntax.Expression, pos hcl.Pos) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+
+	hclsyntax.VisitAll(expr, func(node hclsyntax.Node) hcl.Diagnostics {
+		rng := node.Range()
+		if !containsPos(rng, pos) {
+			return nil
+		}
+		ranges = append(ranges, rng)
+
+		switch n := node.(type) {
+		case *hclsyntax.ScopeTraversalExpr:
+			ranges = append(ranges, traversalRanges(n.Traversal, pos)...)
+		case *hclsyntax.RelativeTraversalExpr:
+			ranges = append(ranges, traversalRanges(n.Traversal, pos)...)
+		case *hclsyntax.ObjectConsExpr:
+			// Object items are not represented by any node,
+			// so we select the key together with the value
+			for _, item := range n.Items {
+				itemRng := hcl.RangeBetween(item.KeyExpr.Range(), item.ValueExpr.Range())
+				if containsPos(itemRng, pos) {
+					ranges = append(ranges, itemRng)
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return ranges
+}
+
+// traversalRanges returns the ranges of leading parts of the traversal,
+// starting with the step containing the position, so that e.g.
+// var.foo is selected before var.foo.bar
+func traversalRanges(traversal hcl.Traversal, pos hcl.Pos) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+
+	for i, step := range traversal {
+		if len(ranges) == 0 && !containsPos(step.SourceRange(), pos) {
+			continue
+		}
+		ranges = append(ranges, hcl.RangeBetween(traversal[0].SourceRange(), traversal[i].SourceRange()))
+	}
+
+	return ranges
+}
+
+// nest orders the ranges from the smallest to the largest one and
+// leaves out any duplicates and ranges not containing the previous one
+func nest(ranges []hcl.Range) []hcl.Range {
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return rangeSize(ranges[i]) < rangeSize(ranges[j])
+	})
+
+	result := make([]hcl.Range, 0, len(ranges))
+	for _, rng := range ranges {
+		if len(result) > 0 && !containsRange(rng, result[len(result)-1]) {
+			continue
+		}
+		if len(result) > 0 && rangeSize(rng) == rangeSize(result[len(result)-1]) {
+			continue
+		}
+		result = append(result, rng)
+	}
+
+	return result
+}
+
+func rangeSize(rng hcl.Range) int {
+	return rng.End.Byte - rng.Start.Byte
+}
+
+// containsPos reports whether the position is within the range,
+// including its end, which is where the cursor is placed
+// after typing the last character of an expression
+func containsPos(rng hcl.Range, pos hcl.Pos) bool {
+	return rng.Start.Byte <= pos.Byte && pos.Byte <= rng.End.Byte
+}
+
+func containsRange(outer, inner hcl.Range) bool {
+	return outer.Start.Byte <= inner.Start.Byte && inner.End.Byte <= outer.End.Byte
+}
@@ -0,0 +1,137 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package selection
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+)
+
+func TestRanges(t *testing.T) {
+	cfg := `resource "aws_instance" "web" {
+  ami = var.images.web
+  tags = {
+    Name = "web-${var.env}"
+  }
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	block := cfg[:len(cfg)-1]
+	blockBody := block[len(`resource "aws_instance" "web" `):]
+
+	testCases := []struct {
+		// cursor marks the position in the configuration
+		cursor   string
+		expected []string
+	}{
+		{
+			"var.im|ages.web",
+			[]string{
+				"var.images",
+				"var.images.web",
+				"ami = var.images.web",
+				blockBody,
+				block,
+				cfg,
+			},
+		},
+		{
+			"${var.e|nv}",
+			[]string{
+				"var.env",
+				`"web-${var.env}"`,
+				`Name = "web-${var.env}"`,
+				"{\n    Name = \"web-${var.env}\"\n  }",
+				"tags = {\n    Name = \"web-${var.env}\"\n  }",
+				blockBody,
+				block,
+				cfg,
+			},
+		},
+		{
+			"create_before|_destroy",
+			[]string{
+				"create_before_destroy = true",
+				"{\n    create_before_destroy = true\n  }",
+				"lifecycle {\n    create_before_destroy = true\n  }",
+				blockBody,
+				block,
+				cfg,
+			},
+		},
+		{
+			`"aws_inst|ance"`,
+			[]string{
+				block,
+				cfg,
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			pos := cursorPos(t, cfg, tc.cursor)
+
+			ranges := Ranges(f, pos)
+			content := make([]string, 0, len(ranges))
+			for _, rng := range ranges {
+				content = append(content, string(rng.SliceBytes([]byte(cfg))))
+			}
+
+			if diff := cmp.Diff(tc.expected, content); diff != "" {
+				t.Fatalf("unexpected ranges: %s", diff)
+			}
+		})
+	}
+}
+
+func TestRanges_json(t *testing.T) {
+	f, diags := json.Parse([]byte(`{"variable": {"foo": {}}}`), "main.tf.json")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	ranges := Ranges(f, hcl.Pos{Line: 1, Column: 5, Byte: 4})
+	if len(ranges) != 0 {
+		t.Fatalf("expected no ranges for JSON, given: %#v", ranges)
+	}
+}
+
+// cursorPos returns the position marked by | within the given
+// snippet, which must appear in the configuration exactly once
+func cursorPos(t *testing.T, cfg, snippet string) hcl.Pos {
+	offset := bytes.IndexByte([]byte(snippet), '|')
+	needle := snippet[:offset] + snippet[offset+1:]
+
+	idx := bytes.Index([]byte(cfg), []byte(needle))
+	if idx < 0 {
+		t.Fatalf("snippet %q not found", needle)
+	}
+	byteOffset := idx + offset
+
+	line := 1 + bytes.Count([]byte(cfg[:byteOffset]), []byte("\n"))
+	lineStart := bytes.LastIndexByte([]byte(cfg[:byteOffset]), '\n') + 1
+
+	return hcl.Pos{
+		Line:   line,
+		Column: byteOffset - lineStart + 1,
+		Byte:   byteOffset,
+	}
+}
@@ -23,6 +23,42 @@ type ExperimentalFeatures struct {
 
 type ValidationOptions struct {
 	EnableEnhancedValidation bool `mapstructure:"enableEnhancedValidation" default:"true"`
+	// EnablePlanValidation enables the tofu.plan command, which
+	// runs tofu plan and may therefore talk to providers
+	EnablePlanValidation bool `mapstructure:"enablePlanValidation"`
+}
+
+const (
+	// FormattingModeCLI formats documents via `tofu fmt`
+	FormattingModeCLI = "cli"
+	// FormattingModeNative formats documents in-process,
+	// without the need for the tofu binary
+	FormattingModeNative = "native"
+)
+
+type Formatting struct {
+	Mode string `mapstructure:"mode" default:"cli"`
+}
+
+// InlayHints controls which kinds of inlay hints are shown
+type InlayHints struct {
+	// VariableValues shows values of variables next to their references
+	VariableValues bool `mapstructure:"variableValues" default:"true"`
+	// ModuleInputTypes shows declared types of inputs in module blocks
+	ModuleInputTypes bool `mapstructure:"moduleInputTypes" default:"true"`
+}
+
+// VarFile maps variable definitions files, which are passed
+// to OpenTofu via -var-file, to the root module using them
+type VarFile struct {
+	// Pattern is a glob pattern matching paths of the files
+	Pattern string `mapstructure:"pattern"`
+	// Module is the path of the root module
+	Module string `mapstructure:"module"`
+}
+
+type Variables struct {
+	VarFiles []VarFile `mapstructure:"varFiles"`
 }
 
 type Indexing struct {
@@ -34,6 +70,10 @@ type Tofu struct {
 	Path        string `mapstructure:"path"`
 	Timeout     string `mapstructure:"timeout"`
 	LogFilePath string `mapstructure:"logFilePath"`
+
+	// Install enables downloading OpenTofu versions
+	// satisfying required_version of root modules
+	Install bool `mapstructure:"install"`
 }
 
 type Options struct {
@@ -45,6 +85,12 @@ type Options struct {
 
 	Validation ValidationOptions `mapstructure:"validation"`
 
+	Formatting Formatting `mapstructure:"formatting"`
+
+	InlayHints InlayHints `mapstructure:"inlayHints"`
+
+	Variables Variables `mapstructure:"variables"`
+
 	IgnoreSingleFileWarning bool `mapstructure:"ignoreSingleFileWarning"`
 
 	TofuOptions Tofu `mapstructure:"tofu"`
@@ -72,6 +118,23 @@ func (o *Options) Validate() error {
 		}
 	}
 
+	switch o.Formatting.Mode {
+	case FormattingModeCLI, FormattingModeNative:
+	default:
+		return fmt.Errorf("unknown formatting mode %q, expected %q or %q",
+			o.Formatting.Mode, FormattingModeCLI, FormattingModeNative)
+	}
+
+	for _, varFile := range o.Variables.VarFiles {
+		if varFile.Pattern == "" || varFile.Module == "" {
+			return fmt.Errorf("expected both pattern and module for variables.varFiles, got %q and %q",
+				varFile.Pattern, varFile.Module)
+		}
+		if _, err := filepath.Match(varFile.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q in variables.varFiles: %s", varFile.Pattern, err)
+		}
+	}
+
 	if len(o.Indexing.IgnoreDirectoryNames) > 0 {
 		for _, directory := range o.Indexing.IgnoreDirectoryNames {
 			if directory == datadir.DataDirName {
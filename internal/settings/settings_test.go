@@ -109,3 +109,85 @@ func TestValidate_relativePath(t *testing.T) {
 		t.Fatal("expected decoding of relative path to result in error")
 	}
 }
+
+func TestValidate_formattingMode(t *testing.T) {
+	out, err := DecodeOptions(map[string]interface{}{
+		"formatting": map[string]interface{}{
+			"mode": "unknown",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := out.Options.Validate()
+	if result == nil {
+		t.Fatal("expected unknown formatting mode to result in error")
+	}
+}
+
+func TestDecodeOptions_inlayHints(t *testing.T) {
+	out, err := DecodeOptions(map[string]interface{}{
+		"inlayHints": map[string]interface{}{
+			"moduleInputTypes": false,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHints := InlayHints{
+		VariableValues:   true,
+		ModuleInputTypes: false,
+	}
+	if diff := cmp.Diff(expectedHints, out.Options.InlayHints); diff != "" {
+		t.Fatalf("options mismatch: %s", diff)
+	}
+}
+
+func TestDecodeOptions_varFiles(t *testing.T) {
+	out, err := DecodeOptions(map[string]interface{}{
+		"variables": map[string]interface{}{
+			"varFiles": []interface{}{
+				map[string]interface{}{
+					"pattern": "envs/*.tfvars",
+					"module":  "app",
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedVarFiles := []VarFile{
+		{Pattern: "envs/*.tfvars", Module: "app"},
+	}
+	if diff := cmp.Diff(expectedVarFiles, out.Options.Variables.VarFiles); diff != "" {
+		t.Fatalf("options mismatch: %s", diff)
+	}
+	if err := out.Options.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate_varFiles(t *testing.T) {
+	out, err := DecodeOptions(map[string]interface{}{
+		"variables": map[string]interface{}{
+			"varFiles": []interface{}{
+				map[string]interface{}{
+					"pattern": "envs/[.tfvars",
+					"module":  "app",
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := out.Options.Validate()
+	if result == nil {
+		t.Fatal("expected invalid pattern to result in error")
+	}
+}
@@ -43,6 +43,14 @@ type Changes struct {
 	Diagnostics          bool
 	ReferenceOrigins     bool
 	ReferenceTargets     bool
+
+	// Variables indicates a change of declared variables
+	// or of the values assigned to them in variable files
+	Variables bool
+
+	// PlannedChanges indicates that a plan was loaded
+	// for the root module (see [PlannedChangeStore])
+	PlannedChanges bool
 }
 
 const maxTimespan = 1 * time.Second
@@ -74,6 +82,8 @@ func (s *ChangeStore) QueueChange(dir document.DirHandle, changes Changes) error
 			Diagnostics:          cb.Changes.Diagnostics || changes.Diagnostics,
 			ReferenceOrigins:     cb.Changes.ReferenceOrigins || changes.ReferenceOrigins,
 			ReferenceTargets:     cb.Changes.ReferenceTargets || changes.ReferenceTargets,
+			Variables:            cb.Changes.Variables || changes.Variables,
+			PlannedChanges:       cb.Changes.PlannedChanges || changes.PlannedChanges,
 		}
 	} else {
 		// create new change batch
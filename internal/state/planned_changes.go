@@ -0,0 +1,151 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"log"
+	"sort"
+
+	"github.com/hashicorp/go-memdb"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-ls/internal/document"
+)
+
+type PlannedChangeStore struct {
+	db          *memdb.MemDB
+	tableName   string
+	logger      *log.Logger
+	changeStore *ChangeStore
+}
+
+// PlannedChange represents the planned change of a single
+// resource instance, as found in a plan of the root module in Dir
+type PlannedChange struct {
+	Dir document.DirHandle
+
+	// Address is the absolute address of the resource instance,
+	// e.g. module.network.aws_subnet.private[0]
+	Address       string
+	ModuleAddress string
+	Mode          tfjson.ResourceMode
+	Type          string
+	Name          string
+	Index         interface{}
+
+	// ModuleDir is the directory of the module declaring the resource,
+	// which is Dir for resources of the root module itself. It is empty
+	// if the directory of a child module is not known.
+	ModuleDir document.DirHandle
+
+	Change *tfjson.Change
+}
+
+// ReplacePlannedChanges replaces all planned changes of the root
+// module in the given directory, such as when a new plan is loaded
+func (s *PlannedChangeStore) ReplacePlannedChanges(dir document.DirHandle, changes []*PlannedChange) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Code lenses of child modules change along with the root module
+	changedDirs := map[document.DirHandle]struct{}{dir: {}}
+
+	it, err := txn.Get(s.tableName, "dir", dir)
+	if err != nil {
+		return err
+	}
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		if modDir := obj.(*PlannedChange).ModuleDir; modDir.URI != "" {
+			changedDirs[modDir] = struct{}{}
+		}
+	}
+
+	_, err = txn.DeleteAll(s.tableName, "dir", dir)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		err = txn.Insert(s.tableName, change)
+		if err != nil {
+			return err
+		}
+		if change.ModuleDir.URI != "" {
+			changedDirs[change.ModuleDir] = struct{}{}
+		}
+	}
+
+	txn.Commit()
+
+	for changedDir := range changedDirs {
+		err = s.changeStore.QueueChange(changedDir, Changes{PlannedChanges: true})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlannedChange returns the planned change of the resource
+// instance with the given address within the given root module
+func (s *PlannedChangeStore) PlannedChange(dir document.DirHandle, address string) (*PlannedChange, error) {
+	txn := s.db.Txn(false)
+
+	obj, err := txn.First(s.tableName, "id", dir, address)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, &RecordNotFoundError{
+			Source: address,
+		}
+	}
+
+	return obj.(*PlannedChange), nil
+}
+
+// PlannedChanges returns all planned changes of the root
+// module in the given directory, ordered by address
+func (s *PlannedChangeStore) PlannedChanges(dir document.DirHandle) ([]*PlannedChange, error) {
+	txn := s.db.Txn(false)
+
+	it, err := txn.Get(s.tableName, "dir", dir)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*PlannedChange, 0)
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		changes = append(changes, obj.(*PlannedChange))
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Address < changes[j].Address
+	})
+
+	return changes, nil
+}
+
+// ModulePlannedChanges returns all planned changes of resources declared
+// in the module in the given directory, from plans of the module itself
+// as well as of any root module calling it, ordered by address
+func (s *PlannedChangeStore) ModulePlannedChanges(modDir document.DirHandle) ([]*PlannedChange, error) {
+	txn := s.db.Txn(false)
+
+	it, err := txn.Get(s.tableName, "module_dir", modDir)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*PlannedChange, 0)
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		changes = append(changes, obj.(*PlannedChange))
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Address < changes[j].Address
+	})
+
+	return changes, nil
+}
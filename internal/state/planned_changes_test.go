@@ -0,0 +1,162 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-ls/internal/document"
+)
+
+func TestPlannedChangeStore_ReplacePlannedChanges(t *testing.T) {
+	ss, err := NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	dir := document.DirHandleFromPath(filepath.Join(tmpDir, "app"))
+	otherDir := document.DirHandleFromPath(filepath.Join(tmpDir, "app-other"))
+
+	err = ss.PlannedChanges.ReplacePlannedChanges(dir, []*PlannedChange{
+		testPlannedChange(dir, "random_pet.b", tfjson.ActionCreate),
+		testPlannedChange(dir, "random_pet.a", tfjson.ActionDelete),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ss.PlannedChanges.ReplacePlannedChanges(otherDir, []*PlannedChange{
+		testPlannedChange(otherDir, "random_pet.c", tfjson.ActionUpdate),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := ss.PlannedChanges.PlannedChanges(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Address != "random_pet.a" || changes[1].Address != "random_pet.b" {
+		t.Fatalf("unexpected planned changes: %#v", changes)
+	}
+
+	pc, err := ss.PlannedChanges.PlannedChange(dir, "random_pet.b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pc.Change.Actions.Create() {
+		t.Fatalf("unexpected actions: %q", pc.Change.Actions)
+	}
+
+	_, err = ss.PlannedChanges.PlannedChange(dir, "random_pet.c")
+	if !IsRecordNotFound(err) {
+		t.Fatalf("expected record not found error, given: %s", err)
+	}
+
+	// loading another plan replaces all changes of the directory
+	err = ss.PlannedChanges.ReplacePlannedChanges(dir, []*PlannedChange{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err = ss.PlannedChanges.PlannedChanges(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("expected no planned changes, given: %#v", changes)
+	}
+	changes, err = ss.PlannedChanges.PlannedChanges(otherDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("expected planned changes of other directory to be kept, given: %#v", changes)
+	}
+
+	batch, err := ss.ChangeStore.AwaitNextChangeBatch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !batch.Changes.PlannedChanges {
+		t.Fatalf("expected planned changes to be queued, given: %#v", batch)
+	}
+}
+
+func testPlannedChange(dir document.DirHandle, address string, action tfjson.Action) *PlannedChange {
+	return &PlannedChange{
+		Dir:     dir,
+		Address: address,
+		Mode:    tfjson.ManagedResourceMode,
+		Change: &tfjson.Change{
+			Actions: tfjson.Actions{action},
+		},
+	}
+}
+
+func TestPlannedChangeStore_ModulePlannedChanges(t *testing.T) {
+	ss, err := NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	dir := document.DirHandleFromPath(filepath.Join(tmpDir, "app"))
+	otherDir := document.DirHandleFromPath(filepath.Join(tmpDir, "app-other"))
+	modDir := document.DirHandleFromPath(filepath.Join(tmpDir, "modules", "db"))
+
+	rootChange := testPlannedChange(dir, "random_pet.a", tfjson.ActionCreate)
+	rootChange.ModuleDir = dir
+	modChange := testPlannedChange(dir, "module.db.random_pet.b", tfjson.ActionUpdate)
+	modChange.ModuleAddress = "module.db"
+	modChange.ModuleDir = modDir
+	unknownModChange := testPlannedChange(dir, "module.remote.random_pet.c", tfjson.ActionDelete)
+	unknownModChange.ModuleAddress = "module.remote"
+	err = ss.PlannedChanges.ReplacePlannedChanges(dir, []*PlannedChange{
+		rootChange, modChange, unknownModChange,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherModChange := testPlannedChange(otherDir, "module.main.random_pet.b", tfjson.ActionCreate)
+	otherModChange.ModuleAddress = "module.main"
+	otherModChange.ModuleDir = modDir
+	err = ss.PlannedChanges.ReplacePlannedChanges(otherDir, []*PlannedChange{otherModChange})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := ss.PlannedChanges.ModulePlannedChanges(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Address != "random_pet.a" {
+		t.Fatalf("unexpected planned changes of root module: %#v", changes)
+	}
+
+	// changes of a module called by several root modules are combined
+	changes, err = ss.PlannedChanges.ModulePlannedChanges(modDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Address != "module.db.random_pet.b" || changes[1].Address != "module.main.random_pet.b" {
+		t.Fatalf("unexpected planned changes of child module: %#v", changes)
+	}
+
+	err = ss.PlannedChanges.ReplacePlannedChanges(dir, []*PlannedChange{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err = ss.PlannedChanges.ModulePlannedChanges(modDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Address != "module.main.random_pet.b" {
+		t.Fatalf("unexpected planned changes of child module after replacing plan: %#v", changes)
+	}
+}
@@ -22,6 +22,7 @@ const (
 	providerIdsTableName    = "provider_ids"
 	walkerPathsTableName    = "walker_paths"
 	registryModuleTableName = "registry_module"
+	plannedChangeTableName  = "planned_change"
 
 	tracerName = "github.com/opentofu/tofu-ls/internal/state"
 )
@@ -164,6 +165,30 @@ var dbSchema = &memdb.DBSchema{
 				},
 			},
 		},
+		plannedChangeTableName: {
+			Name: plannedChangeTableName,
+			Indexes: map[string]*memdb.IndexSchema{
+				"id": {
+					Name:   "id",
+					Unique: true,
+					Indexer: &memdb.CompoundIndex{
+						Indexes: []memdb.Indexer{
+							&DirHandleFieldIndexer{Field: "Dir"},
+							&memdb.StringFieldIndex{Field: "Address"},
+						},
+					},
+				},
+				"dir": {
+					Name:    "dir",
+					Indexer: &DirHandleFieldIndexer{Field: "Dir"},
+				},
+				"module_dir": {
+					Name:         "module_dir",
+					Indexer:      &DirHandleFieldIndexer{Field: "ModuleDir"},
+					AllowMissing: true,
+				},
+			},
+		},
 		providerIdsTableName: {
 			Name: providerIdsTableName,
 			Indexes: map[string]*memdb.IndexSchema{
@@ -203,6 +228,7 @@ type StateStore struct {
 	ProviderSchemas *ProviderSchemaStore
 	WalkerPaths     *WalkerPathStore
 	RegistryModules *RegistryModuleStore
+	PlannedChanges  *PlannedChangeStore
 
 	db *memdb.MemDB
 }
@@ -232,14 +258,16 @@ func NewStateStore() (*StateStore, error) {
 		return nil, err
 	}
 
+	changeStore := &ChangeStore{
+		db:           db,
+		tableName:    changesTableName,
+		logger:       defaultLogger,
+		TimeProvider: time.Now,
+	}
+
 	return &StateStore{
-		db: db,
-		ChangeStore: &ChangeStore{
-			db:           db,
-			tableName:    changesTableName,
-			logger:       defaultLogger,
-			TimeProvider: time.Now,
-		},
+		db:          db,
+		ChangeStore: changeStore,
 		DocumentStore: &DocumentStore{
 			db:           db,
 			tableName:    documentsTableName,
@@ -263,6 +291,12 @@ func NewStateStore() (*StateStore, error) {
 			tableName: registryModuleTableName,
 			logger:    defaultLogger,
 		},
+		PlannedChanges: &PlannedChangeStore{
+			db:          db,
+			tableName:   plannedChangeTableName,
+			logger:      defaultLogger,
+			changeStore: changeStore,
+		},
 		WalkerPaths: &WalkerPathStore{
 			db:              db,
 			tableName:       walkerPathsTableName,
@@ -280,6 +314,7 @@ func (s *StateStore) SetLogger(logger *log.Logger) {
 	s.ProviderSchemas.logger = logger
 	s.WalkerPaths.logger = logger
 	s.RegistryModules.logger = logger
+	s.PlannedChanges.logger = logger
 }
 
 var defaultLogger = log.New(io.Discard, "", 0)
@@ -15,3 +15,16 @@ func IsIgnoredFile(name string) bool {
 		strings.HasSuffix(name, "~") || // vim
 		strings.HasPrefix(name, "#") && strings.HasSuffix(name, "#") // emacs
 }
+
+// ShadowingFilename returns the name of the file which takes precedence
+// over the given file when both exist in the same directory, as OpenTofu
+// ignores e.g. foo.tf if there is foo.tofu and foo.tf.json if there is foo.tofu.json.
+func ShadowingFilename(name string) (string, bool) {
+	if strings.HasSuffix(name, ".tf.json") {
+		return strings.TrimSuffix(name, ".tf.json") + ".tofu.json", true
+	}
+	if strings.HasSuffix(name, ".tf") {
+		return strings.TrimSuffix(name, ".tf") + ".tofu", true
+	}
+	return "", false
+}
@@ -6,6 +6,7 @@
 package ast
 
 import (
+	"github.com/hashicorp/hcl/v2"
 	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
 )
 
@@ -17,6 +18,8 @@ const (
 	SchemaValidationSource
 	ReferenceValidationSource
 	TofuValidateSource
+	TofuTestSource
+	TofuPlanSource
 )
 
 func (d DiagnosticSource) String() string {
@@ -33,3 +36,26 @@ func (dss DiagnosticSourceState) Copy() DiagnosticSourceState {
 
 	return newDiagnosticSourceState
 }
+
+// informationalExtra is attached to HCL diagnostics which are
+// only informational, as HCL only knows errors and warnings
+type informationalExtra struct{}
+
+// NewInformationalDiagnostic returns a diagnostic which is
+// reported with the information severity to the client
+func NewInformationalDiagnostic(summary, detail string, subject *hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  summary,
+		Detail:   detail,
+		Subject:  subject,
+		Extra:    informationalExtra{},
+	}
+}
+
+// IsInformational returns true for diagnostics
+// created via [NewInformationalDiagnostic]
+func IsInformational(diag *hcl.Diagnostic) bool {
+	_, ok := hcl.DiagnosticExtra[informationalExtra](diag)
+	return ok
+}
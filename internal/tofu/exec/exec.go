@@ -6,11 +6,17 @@
 package exec
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log"
+	"os"
 	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-version"
@@ -33,6 +39,7 @@ type Executor struct {
 	tf         *tfexec.Tofu
 	timeout    time.Duration
 	rawLogPath string
+	logger     *log.Logger
 }
 
 func NewExecutor(workDir, execPath string) (TofuExecutor, error) {
@@ -43,10 +50,12 @@ func NewExecutor(workDir, execPath string) (TofuExecutor, error) {
 	return &Executor{
 		timeout: defaultExecTimeout,
 		tf:      tf,
+		logger:  log.New(io.Discard, "", 0),
 	}, nil
 }
 
 func (e *Executor) SetLogger(logger *log.Logger) {
+	e.logger = logger
 	e.tf.SetLogger(logger)
 }
 
@@ -109,7 +118,10 @@ func (e *Executor) setLogPath(method string) error {
 	return e.tf.SetLogPath(logPath)
 }
 
-func (e *Executor) Init(ctx context.Context, opts ...tfexec.InitOption) error {
+// Init runs tofu init and writes its output to stdout and stderr
+// (either of which may be nil) line by line, as it is produced.
+// Cancelling ctx kills the process.
+func (e *Executor) Init(ctx context.Context, stdout, stderr io.Writer, opts ...tfexec.InitOption) error {
 	ctx, cancel := e.withTimeout(ctx)
 	defer cancel()
 	err := e.setLogPath("Init")
@@ -119,6 +131,13 @@ func (e *Executor) Init(ctx context.Context, opts ...tfexec.InitOption) error {
 	ctx, span := otel.Tracer(tracerName).Start(ctx, "tofu-exec:Init")
 	defer span.End()
 
+	e.tf.SetStdout(stdout)
+	e.tf.SetStderr(stderr)
+	defer func() {
+		e.tf.SetStdout(nil)
+		e.tf.SetStderr(nil)
+	}()
+
 	err = e.tf.Init(ctx, opts...)
 	e.setSpanStatus(span, err)
 
@@ -218,3 +237,137 @@ func (e *Executor) ProviderSchemas(ctx context.Context) (*tfjson.ProviderSchemas
 
 	return ps, e.contextfulError(ctx, "ProviderSchemas", err)
 }
+
+// ShowPlanFile reads the given saved plan file and returns
+// the plan in its machine-readable form
+func (e *Executor) ShowPlanFile(ctx context.Context, planPath string) (*tfjson.Plan, error) {
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	err := e.setLogPath("ShowPlanFile")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "tofu-exec:ShowPlanFile")
+	defer span.End()
+
+	plan, err := e.tf.ShowPlanFile(ctx, planPath)
+	e.setSpanStatus(span, err)
+
+	return plan, e.contextfulError(ctx, "ShowPlanFile", err)
+}
+
+// Test runs tofu test and writes its machine-readable output
+// to w as it is produced. Unlike other methods it is not subject
+// to the default timeout, as tests may provision real infrastructure
+// and take arbitrarily long. Cancelling ctx stops the run.
+//
+// If any filters (paths of test files relative to the working
+// directory) are given, only those test files are run.
+func (e *Executor) Test(ctx context.Context, w io.Writer, filters ...string) error {
+	logPath, err := logging.ParseExecLogPath("Test", e.rawLogPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "tofu-exec:Test")
+	defer span.End()
+
+	// tofu-exec ignores any options of tofu test, including -filter
+	args := []string{"test", "-json"}
+	for _, filter := range filters {
+		args = append(args, "-filter="+filter)
+	}
+	cmd := exec.CommandContext(ctx, e.tf.ExecPath(), args...)
+	cmd.Dir = e.tf.WorkingDir()
+	cmd.Env = testEnv(logPath)
+	cmd.Stdout = w
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	// Do not wait for providers started by tofu
+	// holding the output open once it was killed
+	cmd.WaitDelay = 5 * time.Second
+
+	e.logger.Printf("[INFO] running Tofu command: %s", cmd.String())
+	err = cmd.Run()
+	if err != nil && ctx.Err() == nil && stderr.Len() > 0 {
+		err = fmt.Errorf("%w\n%s", err, stderr.String())
+	}
+	e.setSpanStatus(span, err)
+
+	return e.contextfulError(ctx, "Test", err)
+}
+
+// testEnv returns the environment of tofu test, which
+// (like tofu-exec does) marks the run as automated and only
+// enables logging if a log path is given
+func testEnv(logPath string) []string {
+	env := make([]string, 0)
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "TF_LOG") || strings.HasPrefix(kv, "TF_WORKSPACE=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	env = append(env, "TF_IN_AUTOMATION=1")
+	if logPath != "" {
+		env = append(env, "TF_LOG=TRACE", "TF_LOG_PATH="+logPath)
+	}
+	return env
+}
+
+// Plan runs tofu plan and returns the diagnostics from its
+// machine-readable output. A plan failing due to errors in the
+// configuration is not considered an error of the execution.
+// Like Test, it is not subject to the default timeout.
+func (e *Executor) Plan(ctx context.Context, opts ...tfexec.PlanOption) ([]tfjson.Diagnostic, error) {
+	err := e.setLogPath("Plan")
+	if err != nil {
+		return []tfjson.Diagnostic{}, err
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "tofu-exec:Plan")
+	defer span.End()
+
+	buf := bytes.NewBuffer([]byte{})
+	_, err = e.tf.PlanJSON(ctx, buf, opts...)
+	e.setSpanStatus(span, err)
+
+	diags := planDiagnostics(buf)
+	if err != nil && (!hasErrorDiagnostic(diags) || ctx.Err() != nil) {
+		return []tfjson.Diagnostic{}, e.contextfulError(ctx, "Plan", err)
+	}
+
+	return diags, nil
+}
+
+func planDiagnostics(output *bytes.Buffer) []tfjson.Diagnostic {
+	diags := make([]tfjson.Diagnostic, 0)
+
+	scanner := bufio.NewScanner(output)
+	// Diagnostics may include large snippets of configuration
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var msg struct {
+			Type       string             `json:"type"`
+			Diagnostic *tfjson.Diagnostic `json:"diagnostic"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+		if msg.Type == "diagnostic" && msg.Diagnostic != nil {
+			diags = append(diags, *msg.Diagnostic)
+		}
+	}
+
+	return diags
+}
+
+func hasErrorDiagnostic(diags []tfjson.Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == tfjson.DiagnosticSeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -14,6 +14,11 @@ type ExecutorOpts struct {
 	ExecPath    string
 	ExecLogPath string
 	Timeout     time.Duration
+
+	// ExecPathFunc, if set, returns the path to the tofu executable
+	// for the module in the given directory, taking precedence over
+	// ExecPath, e.g. to use a version satisfying its required_version
+	ExecPathFunc func(ctx context.Context, modPath string) (string, error)
 }
 
 var ctxExecOpts = ctxKey("executor opts")
@@ -8,9 +8,16 @@ package exec_test
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-exec/tfexec"
 	"github.com/opentofu/tofu-ls/internal/testutils"
 	"github.com/opentofu/tofu-ls/internal/tofu/exec"
 )
@@ -60,3 +67,81 @@ func TestExec_cancel(t *testing.T) {
 
 	t.Fatalf("expected cancel error: %#v, given: %#v", expectedErr, err)
 }
+
+func TestExec_plan(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub binary requires a POSIX shell")
+	}
+
+	// Stub of tofu plan -json failing due to an error in the configuration
+	workDir := t.TempDir()
+	execPath := filepath.Join(workDir, "tofu")
+	script := `#!/bin/sh
+echo '{"@level":"info","@message":"OpenTofu 1.9.0","type":"version"}'
+echo '{"@level":"error","@message":"Error: Invalid for_each argument","diagnostic":{"severity":"error","summary":"Invalid for_each argument","detail":"The given \"for_each\" argument value is unsuitable.","range":{"filename":"main.tf","start":{"line":2,"column":14,"byte":36},"end":{"line":2,"column":26,"byte":48}}},"type":"diagnostic"}'
+exit 1
+`
+	err := os.WriteFile(execPath, []byte(script), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := exec.NewExecutor(workDir, execPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags, err := e.Plan(t.Context(), tfexec.Refresh(false), tfexec.Lock(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDiags := []tfjson.Diagnostic{
+		{
+			Severity: tfjson.DiagnosticSeverityError,
+			Summary:  "Invalid for_each argument",
+			Detail:   `The given "for_each" argument value is unsuitable.`,
+			Range: &tfjson.Range{
+				Filename: "main.tf",
+				Start:    tfjson.Pos{Line: 2, Column: 14, Byte: 36},
+				End:      tfjson.Pos{Line: 2, Column: 26, Byte: 48},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diags); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
+
+func TestExec_test(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub binary requires a POSIX shell")
+	}
+
+	// Stub of tofu test printing its arguments and environment
+	workDir := t.TempDir()
+	execPath := filepath.Join(workDir, "tofu")
+	script := `#!/bin/sh
+echo "$@"
+echo "TF_IN_AUTOMATION=$TF_IN_AUTOMATION TF_LOG=$TF_LOG"
+`
+	err := os.WriteFile(execPath, []byte(script), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := exec.NewExecutor(workDir, execPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TF_LOG", "DEBUG")
+
+	var output strings.Builder
+	err = e.Test(t.Context(), &output, "tests/main.tftest.hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOutput := "test -json -filter=tests/main.tftest.hcl\nTF_IN_AUTOMATION=1 TF_LOG=\n"
+	if diff := cmp.Diff(expectedOutput, output.String()); diff != "" {
+		t.Fatalf("unexpected output: %s", diff)
+	}
+}
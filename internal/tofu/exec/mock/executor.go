@@ -5,6 +5,8 @@ package mock
 import (
 	context "context"
 
+	io "io"
+
 	log "log"
 
 	mock "github.com/stretchr/testify/mock"
@@ -96,14 +98,14 @@ func (_m *Executor) GetExecPath() string {
 	return r0
 }
 
-// Init provides a mock function with given fields: ctx, opts
-func (_m *Executor) Init(ctx context.Context, opts ...tfexec.InitOption) error {
+// Init provides a mock function with given fields: ctx, stdout, stderr, opts
+func (_m *Executor) Init(ctx context.Context, stdout io.Writer, stderr io.Writer, opts ...tfexec.InitOption) error {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, ctx)
+	_ca = append(_ca, ctx, stdout, stderr)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
@@ -112,8 +114,8 @@ func (_m *Executor) Init(ctx context.Context, opts ...tfexec.InitOption) error {
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, ...tfexec.InitOption) error); ok {
-		r0 = rf(ctx, opts...)
+	if rf, ok := ret.Get(0).(func(context.Context, io.Writer, io.Writer, ...tfexec.InitOption) error); ok {
+		r0 = rf(ctx, stdout, stderr, opts...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -121,6 +123,43 @@ func (_m *Executor) Init(ctx context.Context, opts ...tfexec.InitOption) error {
 	return r0
 }
 
+// Plan provides a mock function with given fields: ctx, opts
+func (_m *Executor) Plan(ctx context.Context, opts ...tfexec.PlanOption) ([]tfjson.Diagnostic, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Plan")
+	}
+
+	var r0 []tfjson.Diagnostic
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...tfexec.PlanOption) ([]tfjson.Diagnostic, error)); ok {
+		return rf(ctx, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ...tfexec.PlanOption) []tfjson.Diagnostic); ok {
+		r0 = rf(ctx, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]tfjson.Diagnostic)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ...tfexec.PlanOption) error); ok {
+		r1 = rf(ctx, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ProviderSchemas provides a mock function with given fields: ctx
 func (_m *Executor) ProviderSchemas(ctx context.Context) (*tfjson.ProviderSchemas, error) {
 	ret := _m.Called(ctx)
@@ -179,6 +218,61 @@ func (_m *Executor) SetTimeout(duration time.Duration) {
 	_m.Called(duration)
 }
 
+// ShowPlanFile provides a mock function with given fields: ctx, planPath
+func (_m *Executor) ShowPlanFile(ctx context.Context, planPath string) (*tfjson.Plan, error) {
+	ret := _m.Called(ctx, planPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ShowPlanFile")
+	}
+
+	var r0 *tfjson.Plan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*tfjson.Plan, error)); ok {
+		return rf(ctx, planPath)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *tfjson.Plan); ok {
+		r0 = rf(ctx, planPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tfjson.Plan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, planPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Test provides a mock function with given fields: ctx, w, filters
+func (_m *Executor) Test(ctx context.Context, w io.Writer, filters ...string) error {
+	_va := make([]interface{}, len(filters))
+	for _i := range filters {
+		_va[_i] = filters[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, w)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Test")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Writer, ...string) error); ok {
+		r0 = rf(ctx, w, filters...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Validate provides a mock function with given fields: ctx
 func (_m *Executor) Validate(ctx context.Context) ([]tfjson.Diagnostic, error) {
 	ret := _m.Called(ctx)
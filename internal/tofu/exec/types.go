@@ -7,6 +7,7 @@ package exec
 
 import (
 	"context"
+	"io"
 	"log"
 	"time"
 
@@ -28,10 +29,13 @@ type TofuExecutor interface {
 	SetExecLogPath(path string) error
 	SetTimeout(duration time.Duration)
 	GetExecPath() string
-	Init(ctx context.Context, opts ...tfexec.InitOption) error
+	Init(ctx context.Context, stdout, stderr io.Writer, opts ...tfexec.InitOption) error
 	Get(ctx context.Context, opts ...tfexec.GetCmdOption) error
 	Format(ctx context.Context, input []byte) ([]byte, error)
 	Version(ctx context.Context) (*version.Version, map[string]*version.Version, error)
 	Validate(ctx context.Context) ([]tfjson.Diagnostic, error)
 	ProviderSchemas(ctx context.Context) (*tfjson.ProviderSchemas, error)
+	Test(ctx context.Context, w io.Writer, filters ...string) error
+	Plan(ctx context.Context, opts ...tfexec.PlanOption) ([]tfjson.Diagnostic, error)
+	ShowPlanFile(ctx context.Context, planPath string) (*tfjson.Plan, error)
 }
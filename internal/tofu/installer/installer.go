@@ -0,0 +1,183 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package installer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+	"github.com/opentofu/tofudl"
+)
+
+var discardLogs = log.New(io.Discard, "", 0)
+
+// Installer downloads OpenTofu releases satisfying version constraints
+// and keeps one binary per version in the cache directory, i.e.
+// <cacheDir>/<version>/tofu
+type Installer struct {
+	cacheDir string
+	dl       tofudl.Downloader
+	logger   *log.Logger
+
+	// mu ensures each version is only downloaded once
+	mu sync.Mutex
+}
+
+// NewInstaller returns an installer caching binaries in cacheDir.
+// Any options are passed to tofudl, e.g. to download from a mirror.
+func NewInstaller(cacheDir string, opts ...tofudl.ConfigOpt) (*Installer, error) {
+	dl, err := tofudl.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Installer{
+		cacheDir: cacheDir,
+		dl:       dl,
+		logger:   discardLogs,
+	}, nil
+}
+
+// DefaultCacheDir returns the directory within
+// the user cache directory to keep binaries in
+func DefaultCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "tofu-ls", "tofu"), nil
+}
+
+func (i *Installer) SetLogger(logger *log.Logger) {
+	i.logger = logger
+}
+
+// Install returns the path to a binary of the latest OpenTofu version
+// satisfying the given constraints. A cached version is preferred
+// over downloading a newer one, so that only the first use of
+// a version requires network access.
+//
+// Downloaded binaries are verified against the release checksums
+// and their signature before they are cached.
+func (i *Installer) Install(ctx context.Context, constraints version.Constraints) (string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	cached, err := i.cachedVersions()
+	if err != nil {
+		return "", err
+	}
+	for _, v := range cached {
+		if constraints.Check(v) {
+			return i.execPath(v), nil
+		}
+	}
+
+	versions, err := i.dl.ListVersions(ctx, tofudl.ListVersionOptMinimumStability(tofudl.StabilityStable))
+	if err != nil {
+		return "", fmt.Errorf("failed to list OpenTofu versions: %w", err)
+	}
+	for _, release := range versions {
+		v, err := version.NewVersion(string(release.ID))
+		if err != nil || !constraints.Check(v) {
+			continue
+		}
+
+		i.logger.Printf("downloading OpenTofu %s ...", v)
+		binary, err := i.dl.DownloadVersion(ctx, release, tofudl.PlatformAuto, tofudl.ArchitectureAuto)
+		if err != nil {
+			return "", fmt.Errorf("failed to download OpenTofu %s: %w", v, err)
+		}
+
+		path := i.execPath(v)
+		err = writeExecutable(path, binary)
+		if err != nil {
+			return "", err
+		}
+		i.logger.Printf("installed OpenTofu %s to %s", v, path)
+
+		return path, nil
+	}
+
+	return "", fmt.Errorf("no OpenTofu version found matching %q", constraints.String())
+}
+
+// cachedVersions returns versions found in the cache directory,
+// from the newest to the oldest
+func (i *Installer) cachedVersions() ([]*version.Version, error) {
+	entries, err := os.ReadDir(i.cacheDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*version.Version, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		v, err := version.NewVersion(entry.Name())
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(i.execPath(v)); err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sort.Sort(sort.Reverse(version.Collection(versions)))
+
+	return versions, nil
+}
+
+func (i *Installer) execPath(v *version.Version) string {
+	return filepath.Join(i.cacheDir, v.String(), executableName())
+}
+
+// writeExecutable writes the binary to a temporary file first,
+// so the path never refers to a partially written binary
+func writeExecutable(path string, binary []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(binary)
+	if err == nil {
+		err = f.Chmod(0o755)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func executableName() string {
+	if runtime.GOOS == "windows" {
+		return "tofu.exe"
+	}
+	return "tofu"
+}
@@ -0,0 +1,122 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package installer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/opentofu/tofudl"
+	"github.com/opentofu/tofudl/mockmirror"
+)
+
+var testBinary = []byte("#!/bin/sh\necho 'OpenTofu v1.0.0'\n")
+
+func TestInstaller_Install(t *testing.T) {
+	mirror := mockmirror.NewFromBinary(t, testBinary)
+	cacheDir := t.TempDir()
+
+	i, err := NewInstaller(cacheDir,
+		tofudl.ConfigGPGKey(mirror.GPGKey()),
+		tofudl.ConfigAPIURL(mirror.APIURL()),
+		tofudl.ConfigDownloadMirrorURLTemplate(mirror.DownloadMirrorURLTemplate()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := i.Install(context.Background(), version.MustConstraints(version.NewConstraint("~> 1.0")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPath := filepath.Join(cacheDir, "1.0.0", executableName())
+	if path != expectedPath {
+		t.Fatalf("expected binary at %q, given %q", expectedPath, path)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != string(testBinary) {
+		t.Fatalf("unexpected binary: %q", b)
+	}
+}
+
+func TestInstaller_Install_cached(t *testing.T) {
+	cacheDir := t.TempDir()
+	for _, v := range []string{"1.6.2", "1.7.3", "1.8.0"} {
+		err := writeExecutable(filepath.Join(cacheDir, v, executableName()), testBinary)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Cached versions must not require any requests
+	i, err := NewInstaller(cacheDir, tofudl.ConfigAPIURL("http://127.0.0.1:0/api.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		constraint   string
+		expectedPath string
+	}{
+		{"", filepath.Join(cacheDir, "1.8.0", executableName())},
+		{"~> 1.7.0", filepath.Join(cacheDir, "1.7.3", executableName())},
+		{">= 1.6, < 1.7", filepath.Join(cacheDir, "1.6.2", executableName())},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.constraint, func(t *testing.T) {
+			var constraints version.Constraints
+			if tc.constraint != "" {
+				constraints = version.MustConstraints(version.NewConstraint(tc.constraint))
+			}
+
+			path, err := i.Install(context.Background(), constraints)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path != tc.expectedPath {
+				t.Fatalf("expected %q, given %q", tc.expectedPath, path)
+			}
+		})
+	}
+}
+
+func TestInstaller_Install_noMatchingVersion(t *testing.T) {
+	mirror := mockmirror.NewFromBinary(t, testBinary)
+	cacheDir := t.TempDir()
+
+	i, err := NewInstaller(cacheDir,
+		tofudl.ConfigGPGKey(mirror.GPGKey()),
+		tofudl.ConfigAPIURL(mirror.APIURL()),
+		tofudl.ConfigDownloadMirrorURLTemplate(mirror.DownloadMirrorURLTemplate()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = i.Install(context.Background(), version.MustConstraints(version.NewConstraint(">= 2.0")))
+	if err == nil {
+		t.Fatal("expected error for constraint without matching version")
+	}
+	expectedErr := `no OpenTofu version found matching ">= 2.0"`
+	if err.Error() != expectedErr {
+		t.Fatalf("expected error %q, given %q", expectedErr, err)
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no cached binaries, given %d entries", len(entries))
+	}
+}
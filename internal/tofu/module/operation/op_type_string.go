@@ -26,11 +26,24 @@ func _() {
 	_ = x[OpTypeSchemaVarsValidation-15]
 	_ = x[OpTypeReferenceValidation-16]
 	_ = x[OpTypeTofuValidate-17]
+	_ = x[OpTypeParseTests-18]
+	_ = x[OpTypeDecodeTestReferenceTargets-19]
+	_ = x[OpTypeDecodeTestReferenceOrigins-20]
+	_ = x[OpTypeSchemaTestValidation-21]
+	_ = x[OpTypeParseBackends-22]
+	_ = x[OpTypeSchemaBackendValidation-23]
+	_ = x[OpTypeParseCLIConfig-24]
+	_ = x[OpTypeSchemaCLIConfigValidation-25]
+	_ = x[OpTypeParseLockFiles-26]
+	_ = x[OpTypeSchemaLockFileValidation-27]
+	_ = x[OpTypeParseTemplateFiles-28]
+	_ = x[OpTypeTemplateVariablesValidation-29]
+	_ = x[OpTypeTofuPlan-30]
 }
 
-const _OpType_name = "OpTypeUnknownOpTypeGetTofuVersionOpTypeGetInstalledTofuVersionOpTypeObtainSchemaOpTypeParseModuleConfigurationOpTypeParseVariablesOpTypeParseModuleManifestOpTypeLoadModuleMetadataOpTypeDecodeReferenceTargetsOpTypeDecodeReferenceOriginsOpTypeDecodeVarsReferencesOpTypeGetModuleDataFromRegistryOpTypeParseProviderVersionsOpTypePreloadEmbeddedSchemaOpTypeSchemaModuleValidationOpTypeSchemaVarsValidationOpTypeReferenceValidationOpTypeTofuValidate"
+const _OpType_name = "OpTypeUnknownOpTypeGetTofuVersionOpTypeGetInstalledTofuVersionOpTypeObtainSchemaOpTypeParseModuleConfigurationOpTypeParseVariablesOpTypeParseModuleManifestOpTypeLoadModuleMetadataOpTypeDecodeReferenceTargetsOpTypeDecodeReferenceOriginsOpTypeDecodeVarsReferencesOpTypeGetModuleDataFromRegistryOpTypeParseProviderVersionsOpTypePreloadEmbeddedSchemaOpTypeSchemaModuleValidationOpTypeSchemaVarsValidationOpTypeReferenceValidationOpTypeTofuValidateOpTypeParseTestsOpTypeDecodeTestReferenceTargetsOpTypeDecodeTestReferenceOriginsOpTypeSchemaTestValidationOpTypeParseBackendsOpTypeSchemaBackendValidationOpTypeParseCLIConfigOpTypeSchemaCLIConfigValidationOpTypeParseLockFilesOpTypeSchemaLockFileValidationOpTypeParseTemplateFilesOpTypeTemplateVariablesValidationOpTypeTofuPlan"
 
-var _OpType_index = [...]uint16{0, 13, 33, 62, 80, 110, 130, 155, 179, 207, 235, 261, 292, 319, 346, 374, 400, 425, 443}
+var _OpType_index = [...]uint16{0, 13, 33, 62, 80, 110, 130, 155, 179, 207, 235, 261, 292, 319, 346, 374, 400, 425, 443, 459, 491, 523, 549, 568, 597, 617, 648, 668, 698, 722, 755, 769}
 
 func (i OpType) String() string {
 	if i >= OpType(len(_OpType_index)-1) {
@@ -37,4 +37,17 @@ const (
 	OpTypeSchemaVarsValidation
 	OpTypeReferenceValidation
 	OpTypeTofuValidate
+	OpTypeParseTests
+	OpTypeDecodeTestReferenceTargets
+	OpTypeDecodeTestReferenceOrigins
+	OpTypeSchemaTestValidation
+	OpTypeParseBackends
+	OpTypeSchemaBackendValidation
+	OpTypeParseCLIConfig
+	OpTypeSchemaCLIConfigValidation
+	OpTypeParseLockFiles
+	OpTypeSchemaLockFileValidation
+	OpTypeParseTemplateFiles
+	OpTypeTemplateVariablesValidation
+	OpTypeTofuPlan
 )
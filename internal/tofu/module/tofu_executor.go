@@ -13,17 +13,24 @@ import (
 )
 
 func TofuExecutorForModule(ctx context.Context, modPath string) (exec.TofuExecutor, error) {
+	return TofuExecutorForWorkDir(ctx, modPath, modPath)
+}
+
+// TofuExecutorForWorkDir returns an executor running in the given working
+// directory, using the tofu executable chosen for the module in modPath.
+// This allows running commands for a module mirrored elsewhere.
+func TofuExecutorForWorkDir(ctx context.Context, workDir, modPath string) (exec.TofuExecutor, error) {
 	newExecutor, ok := exec.ExecutorFactoryFromContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("no tofu executor provided")
 	}
 
-	execPath, err := TofuExecPath(ctx)
+	execPath, err := TofuExecPathForModule(ctx, modPath)
 	if err != nil {
 		return nil, err
 	}
 
-	tfExec, err := newExecutor(modPath, execPath)
+	tfExec, err := newExecutor(workDir, execPath)
 	if err != nil {
 		return nil, err
 	}
@@ -47,3 +54,13 @@ func TofuExecPath(ctx context.Context) (string, error) {
 		return "", NoTofuExecPathErr{}
 	}
 }
+
+// TofuExecPathForModule returns the path to the tofu executable
+// to use for the module in the given directory
+func TofuExecPathForModule(ctx context.Context, modPath string) (string, error) {
+	opts, ok := exec.ExecutorOptsFromContext(ctx)
+	if ok && opts.ExecPathFunc != nil {
+		return opts.ExecPathFunc(ctx, modPath)
+	}
+	return TofuExecPath(ctx)
+}